@@ -32,6 +32,19 @@ const (
 	BDS44 = BDScode(44) // BDS 4,4 - Meteorological routine air report
 	BDS45 = BDScode(45) // BDS 4,5 - Meteorological hazard report
 
+	// Other Comm-B registers (reported in BDS 1,7 GICB capability report)
+	BDS21 = BDScode(21) // BDS 2,1 - Aircraft and airline registration markings
+	BDS41 = BDScode(41) // BDS 4,1 - Next waypoint identifier
+	BDS42 = BDScode(42) // BDS 4,2 - Next waypoint position
+	BDS43 = BDScode(43) // BDS 4,3 - Next waypoint information
+	BDS48 = BDScode(48) // BDS 4,8 - VHF channel report
+	BDS51 = BDScode(51) // BDS 5,1 - Position report coarse
+	BDS52 = BDScode(52) // BDS 5,2 - Position report fine
+	BDS53 = BDScode(53) // BDS 5,3 - Air-referenced state vector
+	BDS54 = BDScode(54) // BDS 5,4 - Waypoint 1
+	BDS55 = BDScode(55) // BDS 5,5 - Waypoint 2
+	BDS56 = BDScode(56) // BDS 5,6 - Waypoint 3
+
 	// Currently Unknown
 	BDS07 = BDScode(7) // BDS 0,7 - Extended squitter status
 )
@@ -47,6 +60,26 @@ const EmergencyNoCommunications = EmergencyPriorityStatus(4)     // No communica
 const EmergencyUnlawfulInterference = EmergencyPriorityStatus(5) // Unlawful interference
 const EmergencyDownedAircraft = EmergencyPriorityStatus(6)       // Downed aircraft
 
+func (eps EmergencyPriorityStatus) String() string {
+	switch eps {
+	case EmergencyNone:
+		return "no emergency"
+	case EmergencyGeneral:
+		return "general emergency"
+	case EmergencyLifeguardMedical:
+		return "lifeguard/medical emergency"
+	case EmergencyMinimumFuel:
+		return "minimum fuel"
+	case EmergencyNoCommunications:
+		return "no communications"
+	case EmergencyUnlawfulInterference:
+		return "unlawful interference"
+	case EmergencyDownedAircraft:
+		return "downed aircraft"
+	}
+	return "unknown"
+}
+
 // Time Synchronization
 type TimeSynchronization uint8
 
@@ -74,6 +107,9 @@ var waypointCharMap = callsignCharMap
 // regex to check for valid waypoints
 var validWaypoint = validCallsign
 
+// regex to check for plausible waypoints when inferring BDS
+var plausibleWaypoint = regexp.MustCompile("^[A-Z][A-Z0-9]{1,4}$")
+
 // --------------------
 
 func decodeEmergencyState(emergencyStateBits int) (emergencyState EmergencyPriorityStatus, err error) {
@@ -68,7 +68,9 @@ type BDS05Frame struct {
 	//
 	// At any time that the diversity configuration cannot guarantee that both antenna channels are functional,
 	// then the single antenna subfield shall be set to SingleTransmitAntenna.
-	saf SingleAntennaFlag
+	//
+	// In ADS-B version 2 this bit is NIC supplement-B.
+	SAF SingleAntennaFlag
 
 	// Surveillance Status
 	//
@@ -125,9 +127,9 @@ func DecodeBDS05(mb []byte) (frame BDS05Frame, err error) {
 
 	switch int(mb[0]) & 0b00000001 {
 	case 0:
-		frame.saf = DualTransmitAntenna
+		frame.SAF = DualTransmitAntenna
 	case 1:
-		frame.saf = SingleTransmitAntenna
+		frame.SAF = SingleTransmitAntenna
 	}
 
 	// ALTITUDE
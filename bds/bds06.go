@@ -8,11 +8,10 @@ import (
 	"beastdecoder/common"
 	"errors"
 	"fmt"
-	"math"
 )
 
 type BDS06Frame struct {
-	tc int // Type Code
+	Tc int // Type Code
 
 	// Movement
 	//
@@ -28,11 +27,15 @@ type BDS06Frame struct {
 	//   94-108 = 129.64 km/h (70 kt) ≤ ground speed < 185.2 km/h (100 kt); LSB: 3.704 km/h (2.0 kt)
 	//  109-123 = 185.2 km/h (100 kt) ≤ ground speed < 324.1 km/h (175 kt); LSB: 9.26 km/h (5.0 kt)
 	//      124 = Ground speed ≥ 324.1 km/h (175 kt)
-	mov int
+	Mov int
 
 	// Ground Speed (decoded)
 	GroundSpeed string
 
+	// Ground Speed (kt), valid for movement 1-124. 175 kt means greater than or equal to 175 kt.
+	GroundSpeedValid bool
+	GroundSpeedKnots float64
+
 	// Ground track status
 	//
 	// This field shall define the validity of the ground track value. Coding for this field shall be as follows:
@@ -45,11 +48,14 @@ type BDS06Frame struct {
 	// The ground track shall be encoded as an unsigned angular weighted binary numeral,
 	// with an MSB of 180 degrees and an LSB of 360/128 degrees, with zero indicating true north.
 	// The data in the field shall be rounded to the nearest multiple of 360/128 degrees.
-	trk int
+	Trk int
 
 	// Ground track value (decoded)
 	GroundTrack string
 
+	// Ground track value (degrees), only valid if S = GroundTrackStatusValid
+	GroundTrackDegrees float64
+
 	// Compact Position Reporting (CPR) Format (F)
 	//
 	// The CPR format field for the surface position message shall be encoded as specified for the airborne message.
@@ -96,16 +102,16 @@ func DecodeBDS06(mb []byte) (frame BDS06Frame, err error) {
 
 	frame = BDS06Frame{}
 
-	frame.tc = (int(mb[0]) & 0b11111000) >> 3 // Type Code
+	frame.Tc = (int(mb[0]) & 0b11111000) >> 3 // Type Code
 
-	if frame.tc < 5 || frame.tc > 8 {
+	if frame.Tc < 5 || frame.Tc > 8 {
 		err = errors.New("type code not from 5 to 8")
 		return
 	}
 
-	frame.mov = (((int(mb[0]) & 0b00000111) << 4) + ((int(mb[1]) & 0b11110000) >> 4))
+	frame.Mov = (((int(mb[0]) & 0b00000111) << 4) + ((int(mb[1]) & 0b11110000) >> 4))
 	frame.S = GroundTrackStatus((int(mb[1]) & 0b00001000) >> 3)
-	frame.trk = (((int(mb[1]) & 0b00000111) << 4) + (int(mb[2]) & 0b11110000 >> 4))
+	frame.Trk = (((int(mb[1]) & 0b00000111) << 4) + (int(mb[2]) & 0b11110000 >> 4))
 
 	switch (int(mb[2]) & 0b00001000) >> 3 {
 	case 0:
@@ -124,8 +130,12 @@ func DecodeBDS06(mb []byte) (frame BDS06Frame, err error) {
 	frame.LatCpr = (((int(mb[2]) & 0b00000011) << 15) + (int(mb[3]) << 7) + ((int(mb[4]) & 0b11111110) >> 1))
 	frame.LonCpr = (((int(mb[4]) & 0b00000001) << 16) + (int(mb[5]) << 8) + int(mb[6]))
 
-	frame.GroundTrack = DecodeBDS05GroundTrack(frame.S, frame.trk)
-	frame.GroundSpeed = DecodeBDS05SurfaceMovementSpeed(frame.mov)
+	frame.GroundTrack = DecodeBDS05GroundTrack(frame.S, frame.Trk)
+	frame.GroundSpeed = DecodeBDS05SurfaceMovementSpeed(frame.Mov)
+	frame.GroundSpeedKnots, frame.GroundSpeedValid = SurfaceMovementKnots(frame.Mov)
+	if frame.S == GroundTrackStatusValid {
+		frame.GroundTrackDegrees = (360 * float64(frame.Trk)) / 128
+	}
 
 	return
 }
@@ -137,8 +147,8 @@ func DecodeBDS05GroundTrack(s GroundTrackStatus, trk int) (groundTrack string) {
 	case GroundTrackStatusInvalid:
 		groundTrack = "ground track status invalid"
 	case GroundTrackStatusValid:
-		hdg := int(math.Round((360 * float64(trk)) / 128))
-		groundTrack = fmt.Sprintf("%d°", hdg)
+		hdg := (360 * float64(trk)) / 128
+		groundTrack = fmt.Sprintf("%g°", hdg)
 	}
 	return
 }
@@ -146,8 +156,6 @@ func DecodeBDS05GroundTrack(s GroundTrackStatus, trk int) (groundTrack string) {
 func DecodeBDS05SurfaceMovementSpeed(mov int) (speed string) {
 	// https://mode-s.org/decode/content/ads-b/4-surface-position.html#movement
 
-	var speedKnots, speedKmh float64
-
 	switch {
 	case mov == 0:
 		speed = "No information available"
@@ -155,23 +163,39 @@ func DecodeBDS05SurfaceMovementSpeed(mov int) (speed string) {
 	case mov == 1:
 		speed = "Aircraft stopped (< 0.2315 km/h (0.125 kt))"
 		return
+	case mov >= 124:
+		speed = "≥ 324.1 km/h (175 kt)"
+		return
+	}
+	speedKnots, _ := SurfaceMovementKnots(mov)
+	speedKmh := 1.852 * speedKnots
+	speed = fmt.Sprintf("%.4f km/h (%.3f kt)", speedKmh, speedKnots)
+	return
+}
+
+func SurfaceMovementKnots(mov int) (speedKnots float64, ok bool) {
+	// returns ground speed (kt) from the surface position movement field
+	// https://mode-s.org/decode/content/ads-b/4-surface-position.html#movement
+
+	switch {
+	case mov == 1:
+		return 0, true
 	case mov >= 2 && mov <= 8:
-		speedKnots = 0.125 + ((float64(mov) - 2) * 0.125)
+		return 0.125 + ((float64(mov) - 2) * 0.125), true
 	case mov >= 9 && mov <= 12:
-		speedKnots = 1 + ((float64(mov) - 9) * 0.25)
+		return 1 + ((float64(mov) - 9) * 0.25), true
 	case mov >= 13 && mov <= 38:
-		speedKnots = 2 + ((float64(mov) - 13) * 0.5)
+		return 2 + ((float64(mov) - 13) * 0.5), true
 	case mov >= 39 && mov <= 93:
-		speedKnots = 15 + (float64(mov) - 39)
+		return 15 + (float64(mov) - 39), true
 	case mov >= 94 && mov <= 108:
-		speedKnots = 70 + ((float64(mov) - 94) * 2)
+		return 70 + ((float64(mov) - 94) * 2), true
 	case mov >= 109 && mov <= 123:
-		speedKnots = 100 + ((float64(mov) - 109) * 5)
-	case mov >= 124:
-		speed = "≥ 324.1 km/h (175 kt)"
-		return
+		return 100 + ((float64(mov) - 109) * 5), true
+	case mov == 124:
+		return 175, true
 	}
-	speedKmh = 1.852 * speedKnots
-	speed = fmt.Sprintf("%.4f km/h (%.3f kt)", speedKmh, speedKnots)
-	return
+
+	// 0 = no information, 125-127 reserved
+	return 0, false
 }
@@ -17,6 +17,8 @@ func TestDecodeBDS06(t *testing.T) {
 		groundSpeed string            // Ground speed (decoded)
 		s           GroundTrackStatus // Status for ground track
 		groundTrack string            // Ground track (decoded)
+		speedKnots  float64           // Ground speed (kt)
+		trackDeg    float64           // Ground track (degrees)
 		f           common.CprFormat  // CPR Format
 		latCpr      int               // Encoded latitude
 		lonCpr      int               // Encoded longitude
@@ -27,6 +29,8 @@ func TestDecodeBDS06(t *testing.T) {
 			groundSpeed: "10.1860 km/h (5.500 kt)",
 			s:           GroundTrackStatusValid,
 			groundTrack: "177.1875°",
+			speedKnots:  5.5,
+			trackDeg:    177.1875,
 			f:           common.CprFormatEvenFrame,
 			latCpr:      93258,
 			lonCpr:      55582,
@@ -37,6 +41,8 @@ func TestDecodeBDS06(t *testing.T) {
 			groundSpeed: "10.1860 km/h (5.500 kt)",
 			s:           GroundTrackStatusValid,
 			groundTrack: "177.1875°",
+			speedKnots:  5.5,
+			trackDeg:    177.1875,
 			f:           common.CprFormatOddFrame,
 			latCpr:      8690,
 			lonCpr:      17770,
@@ -48,13 +54,32 @@ func TestDecodeBDS06(t *testing.T) {
 		frame, err := DecodeBDS06(testData.data)
 		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
 		assert.NoError(err, testMsg+"decodeBDS06 error")
-		assert.Equal(testData.tc, frame.tc, testMsg+"tc")
+		assert.Equal(testData.tc, frame.Tc, testMsg+"tc")
 		assert.Equal(testData.groundSpeed, frame.GroundSpeed, testMsg+"groundSpeed")
 		assert.Equal(testData.s, frame.S, testMsg+"s")
 		assert.Equal(testData.groundTrack, frame.GroundTrack, testMsg+"groundTrack")
+		assert.True(frame.GroundSpeedValid, testMsg+"groundSpeedValid")
+		assert.Equal(testData.speedKnots, frame.GroundSpeedKnots, testMsg+"groundSpeedKnots")
+		assert.Equal(testData.trackDeg, frame.GroundTrackDegrees, testMsg+"groundTrackDegrees")
 		assert.Equal(testData.f, frame.F, testMsg+"f")
 		assert.Equal(testData.latCpr, frame.LatCpr, testMsg+"latCpr")
 		assert.Equal(testData.lonCpr, frame.LonCpr, testMsg+"lonCpr")
 
 	}
 }
+
+func TestSurfaceMovementKnots(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := SurfaceMovementKnots(0)
+	assert.False(ok)
+	kts, ok := SurfaceMovementKnots(1)
+	assert.True(ok)
+	assert.Equal(0.0, kts)
+	kts, _ = SurfaceMovementKnots(9)
+	assert.Equal(1.0, kts)
+	kts, _ = SurfaceMovementKnots(124)
+	assert.Equal(175.0, kts)
+	_, ok = SurfaceMovementKnots(125)
+	assert.False(ok)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type BDS07Frame struct {
-	tc int // Type code
+	Tc int // Type code
 	St int // Sub-type code
 
 	om int // Operational mode codes
@@ -34,20 +34,20 @@ type BDS07Frame struct {
 type BDS07FrameVersion2 struct {
 	// Aircraft operational status (Version 2)
 
-	cc                   int                                    // Capability class codes
-	airborneCapabilities BDS07FrameVersion2AirborneCapabilities // Capability class codes decoded to bools
-	surfaceCapabilities  BDS07FrameVersion2SurfaceCapabilities  // Capability class codes decoded to bools
+	CC                   int                                    // Capability class codes
+	AirborneCapabilities BDS07FrameVersion2AirborneCapabilities // Capability class codes decoded to bools
+	SurfaceCapabilities  BDS07FrameVersion2SurfaceCapabilities  // Capability class codes decoded to bools
 
-	om                       int                                        // Operational mode codes
-	airborneOperationalModes BDS07FrameVersion2AirborneOperationalModes // Operational mode codes decoded to bools
-	surfaceOperationalModes  BDS07FrameVersion2SurfaceOperationalModes  // Operational mode codes decoded to bools
+	OM                       int                                        // Operational mode codes
+	AirborneOperationalModes BDS07FrameVersion2AirborneOperationalModes // Operational mode codes decoded to bools
+	SurfaceOperationalModes  BDS07FrameVersion2SurfaceOperationalModes  // Operational mode codes decoded to bools
 
-	nica int // NIC supplement - A
-	nacp int // Navigational accuracy category - position
-	gva  int // Geometric vertical accuracy (depending on st bits)
-	sil  int // Source integrity level
-	bai  int // Barometric altitude integrity (depending on st bits) (NIC-baro)
-	sils int // SIL supplement
+	NICSupplementA int // NIC supplement - A
+	NACp           int // Navigational accuracy category - position
+	GVA            int // Geometric vertical accuracy (depending on st bits)
+	SIL            int // Source integrity level
+	NICbaro        int // Barometric altitude integrity (depending on st bits) (NIC-baro)
+	SILSupplement  int // SIL supplement
 
 	Airborne bool
 	Hrd      int // Horizontal reference direction
@@ -90,18 +90,18 @@ type BDS07FrameVersion2SurfaceOperationalModes struct {
 type BDS07FrameVersion1 struct {
 	// Aircraft operational status (Version 1)
 
-	cc                   int                                    // Capability class codes
-	airborneCapabilities BDS07FrameVersion1AirborneCapabilities // Capability class codes decoded to bools
-	surfaceCapabilities  BDS07FrameVersion1SurfaceCapabilities  // Capability class codes decoded to bools
+	CC                   int                                    // Capability class codes
+	AirborneCapabilities BDS07FrameVersion1AirborneCapabilities // Capability class codes decoded to bools
+	SurfaceCapabilities  BDS07FrameVersion1SurfaceCapabilities  // Capability class codes decoded to bools
 
-	om               int                                // Operational mode codes
-	operationalModes BDS07FrameVersion1OperationalModes // Operational mode codes decoded to bools
+	OM               int                                // Operational mode codes
+	OperationalModes BDS07FrameVersion1OperationalModes // Operational mode codes decoded to bools
 
-	nics int // NIC supplement
-	nacp int // Navigational accuracy category - position
-	sil  int // Surveillance integrity level
-	baq  int // Barometric altitude quality (airborne only)
-	bai  int // Barometric altitude integrity (airborne only)
+	NICSupplement int // NIC supplement
+	NACp          int // Navigational accuracy category - position
+	SIL           int // Surveillance integrity level
+	BAQ           int // Barometric altitude quality (airborne only)
+	NICbaro       int // Barometric altitude integrity (airborne only)
 
 	Airborne bool
 	Hrd      int // Horizontal reference direction
@@ -130,14 +130,14 @@ type BDS07FrameVersion1OperationalModes struct {
 }
 
 type BDS07FrameVersion0 struct {
-	cc4 int
-	cc3 int
-	cc2 int
-	cc1 int
-	om4 int
-	om3 int
-	om2 int
-	om1 int
+	CC4 int // En-Route Capabilities (CC-4)
+	CC3 int // Terminal Area Operational Capabilities (CC-3)
+	CC2 int // Approach/Landing Operational Capabilities (CC-2)
+	CC1 int // Surface Operational Capabilities (CC-1)
+	OM4 int // En-Route Operational Capability Status (OM-4)
+	OM3 int // Terminal Area Operational Capability Status (OM-3)
+	OM2 int // Approach/Landing Operational Capability Status (OM-2)
+	OM1 int // Surface Operational Capability Status (OM-1)
 }
 
 func inferBDS07FrameVersion(mb []byte) (ver int, err error) {
@@ -179,24 +179,24 @@ func inferBDS07FrameVersion(mb []byte) (ver int, err error) {
 }
 
 func decodeBDS07Version0(mb []byte) (frame BDS07Frame) {
-	frame.tc = (int(mb[0]) & 0b11111000) >> 3
+	frame.Tc = (int(mb[0]) & 0b11111000) >> 3
 	frame.St = int(mb[0]) & 0b00000111
-	frame.Version0Data.cc4 = (int(mb[1]) & 0b11110000) >> 4
-	frame.Version0Data.cc3 = int(mb[1]) & 0b00001111
-	frame.Version0Data.cc2 = (int(mb[2]) & 0b11110000) >> 4
-	frame.Version0Data.cc1 = int(mb[2]) & 0b00001111
-	frame.Version0Data.om4 = (int(mb[3]) & 0b11110000) >> 4
-	frame.Version0Data.om3 = int(mb[3]) & 0b00001111
-	frame.Version0Data.om2 = (int(mb[4]) & 0b11110000) >> 4
-	frame.Version0Data.om1 = int(mb[4]) & 0b00001111
+	frame.Version0Data.CC4 = (int(mb[1]) & 0b11110000) >> 4
+	frame.Version0Data.CC3 = int(mb[1]) & 0b00001111
+	frame.Version0Data.CC2 = (int(mb[2]) & 0b11110000) >> 4
+	frame.Version0Data.CC1 = int(mb[2]) & 0b00001111
+	frame.Version0Data.OM4 = (int(mb[3]) & 0b11110000) >> 4
+	frame.Version0Data.OM3 = int(mb[3]) & 0b00001111
+	frame.Version0Data.OM2 = (int(mb[4]) & 0b11110000) >> 4
+	frame.Version0Data.OM1 = int(mb[4]) & 0b00001111
 	return
 }
 
 func decodeBDS07Version1(mb []byte) (frame BDS07Frame) {
-	frame.tc = (int(mb[0]) & 0b11111000) >> 3
+	frame.Tc = (int(mb[0]) & 0b11111000) >> 3
 	frame.St = int(mb[0]) & 0b00000111
 
-	frame.Version1Data.cc = (int(mb[1]) << 8) + int(mb[2])
+	frame.Version1Data.CC = (int(mb[1]) << 8) + int(mb[2])
 
 	// See B.2.3.10.3 "Capability Class (CC) Codes"
 	// http://www.aviationchief.com/uploads/9/2/0/9/92098238/icao_doc_9871_-_technical_provisions_for_mode_s_-_advanced_edition_1.pdf
@@ -210,30 +210,30 @@ func decodeBDS07Version1(mb []byte) (frame BDS07Frame) {
 		//     0 = ACAS operational or unknown
 		//     1 = ACAS not installed or not operational
 		if (int(mb[1])&0b00100000)>>5 == 0 {
-			frame.Version1Data.airborneCapabilities.AcasOperationalOrUnknown = true
+			frame.Version1Data.AirborneCapabilities.AcasOperationalOrUnknown = true
 		} else {
-			frame.Version1Data.airborneCapabilities.AcasOperationalOrUnknown = false
+			frame.Version1Data.AirborneCapabilities.AcasOperationalOrUnknown = false
 		}
 
 		// 2. CDTI (Cockpit Display of Traffic Information Status)
 		//     0 = Traffic display not operational
 		//     1 = Traffic display operational
 		if (int(mb[1])&0b00010000)>>4 == 1 {
-			frame.Version1Data.airborneCapabilities.CockpitDisplayOfTrafficInformation = true
+			frame.Version1Data.AirborneCapabilities.CockpitDisplayOfTrafficInformation = true
 		}
 
 		// 3. ARV (Air-Referenced Velocity Report Capability)
 		//     0 = No capability for sending messages to support Air-Referenced Velocity Reports
 		//     1 = Capability of sending messages to support Air-Referenced Velocity Reports
 		if (int(mb[1])&0b00000010)>>1 == 1 {
-			frame.Version1Data.airborneCapabilities.AirReferencedVelocityReport = true
+			frame.Version1Data.AirborneCapabilities.AirReferencedVelocityReport = true
 		}
 
 		// 4. TS (Target State Report Capability)
 		//     0 = No capability for sending messages to support Target State Reports
 		//     1 = Capability of sending messages to support Target State Reports
 		if (int(mb[1]) & 0b00000001) == 1 {
-			frame.Version1Data.airborneCapabilities.TargetStateReport = true
+			frame.Version1Data.AirborneCapabilities.TargetStateReport = true
 		}
 
 		// 5. TC (Target Change Report Capability)
@@ -243,9 +243,9 @@ func decodeBDS07Version1(mb []byte) (frame BDS07Frame) {
 		//     3 = Reserved
 		switch (int(mb[2]) & 0b11000000) >> 6 {
 		case 1:
-			frame.Version1Data.airborneCapabilities.SupportTargetChangePlus0ReportOnly = true
+			frame.Version1Data.AirborneCapabilities.SupportTargetChangePlus0ReportOnly = true
 		case 2:
-			frame.Version1Data.airborneCapabilities.SupportMultipleTargetChangeReports = true
+			frame.Version1Data.AirborneCapabilities.SupportMultipleTargetChangeReports = true
 		}
 
 	case 1: // surface
@@ -256,55 +256,55 @@ func decodeBDS07Version1(mb []byte) (frame BDS07Frame) {
 		//     0 = Traffic display not operational
 		//     1 = Traffic display operational
 		if (int(mb[1])&0b00010000)>>4 == 1 {
-			frame.Version1Data.surfaceCapabilities.CockpitDisplayOfTrafficInformation = true
+			frame.Version1Data.SurfaceCapabilities.CockpitDisplayOfTrafficInformation = true
 		}
 
 		// 2. POA (Position Offset Applied)
 		//     0 = Position transmitted is not the ADS-B position reference point
 		//     1 = Position transmitted is the ADS-B position reference point
 		if (int(mb[1])&0b00100000)>>5 == 1 {
-			frame.Version1Data.surfaceCapabilities.PositionOffsetApplied = true
+			frame.Version1Data.SurfaceCapabilities.PositionOffsetApplied = true
 		}
 
 		// 3. B2 Low (Class B2 transmit power less than 70 Watts)
 		//     0 = Greater than or equal to 70 Watts transmit power
 		//     1 = Less than 70 Watts transmit power
 		if (int(mb[1])&0b00000010)>>1 == 1 {
-			frame.Version1Data.surfaceCapabilities.ClassB2TransmitPower = true
+			frame.Version1Data.SurfaceCapabilities.ClassB2TransmitPower = true
 		}
 	}
 
-	frame.Version1Data.om = (int(mb[3]) << 8) + int(mb[4])
+	frame.Version1Data.OM = (int(mb[3]) << 8) + int(mb[4])
 
 	// 1. ACAS Resolution Advisory (RA) active
 	//     0 = ACAS II or ACAS RA not active
 	//     1 = ACAS RA is active
 	if (int(mb[3])&0b00100000)>>5 == 1 {
-		frame.Version1Data.operationalModes.AcasResolutionAdvisoryActive = true
+		frame.Version1Data.OperationalModes.AcasResolutionAdvisoryActive = true
 	}
 
 	// 2. IDENT switch active
 	//     0 = Ident switch not active
 	//     1 = Ident switch active — retained for 18 ±1 seconds
 	if (int(mb[3])&0b00010000)>>4 == 1 {
-		frame.Version1Data.operationalModes.IdentSwitchActive = true
+		frame.Version1Data.OperationalModes.IdentSwitchActive = true
 	}
 
 	// 3. Receiving ATC services
 	//     0 = Aircraft not receiving ATC services
 	//     1 = Aircraft receiving ATC services
 	if (int(mb[3])&0b00001000)>>3 == 1 {
-		frame.Version1Data.operationalModes.ReceivingATCServices = true
+		frame.Version1Data.OperationalModes.ReceivingATCServices = true
 	}
 
-	frame.Version1Data.nics = (int(mb[5]) & 0b00010000) >> 4
-	frame.Version1Data.nacp = (int(mb[5]) & 0b00001111)
-	frame.Version1Data.sil = ((int(mb[6]) & 0b00110000) >> 4)
+	frame.Version1Data.NICSupplement = (int(mb[5]) & 0b00010000) >> 4
+	frame.Version1Data.NACp = (int(mb[5]) & 0b00001111)
+	frame.Version1Data.SIL = ((int(mb[6]) & 0b00110000) >> 4)
 	frame.Version1Data.Hrd = ((int(mb[6]) & 0b00000100) >> 2)
 
 	if frame.St == 0 {
-		frame.Version1Data.baq = ((int(mb[6]) & 0b11000000) >> 6)
-		frame.Version1Data.bai = ((int(mb[6]) & 0b00001000) >> 3)
+		frame.Version1Data.BAQ = ((int(mb[6]) & 0b11000000) >> 6)
+		frame.Version1Data.NICbaro = ((int(mb[6]) & 0b00001000) >> 3)
 	} else {
 		frame.Version1Data.Trk = ((int(mb[6]) & 0b00001000) >> 3)
 	}
@@ -312,9 +312,9 @@ func decodeBDS07Version1(mb []byte) (frame BDS07Frame) {
 }
 
 func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
-	frame.tc = (int(mb[0]) & 0b11111000) >> 3
+	frame.Tc = (int(mb[0]) & 0b11111000) >> 3
 	frame.St = int(mb[0]) & 0b00000111
-	frame.Version2Data.cc = (int(mb[1]) << 8) + int(mb[2])
+	frame.Version2Data.CC = (int(mb[1]) << 8) + int(mb[2])
 
 	switch frame.St {
 	case 0: // airborne
@@ -328,28 +328,28 @@ func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
 		//     = 0: TCAS/ACAS is NOT Operational
 		//     = 1: TCAS/ACAS IS Operational
 		if (int(mb[1])&0b00100000)>>5 == 1 {
-			frame.Version2Data.airborneCapabilities.TcasAcasOperational = true
+			frame.Version2Data.AirborneCapabilities.TcasAcasOperational = true
 		}
 
 		// 2. 1090 ES IN (1090 MHz Extended Squitter)
 		//     = 0: Aircraft has NO 1 090 ES Receive capability
 		//     = 1: Aircraft has 1 090 ES Receive capability
 		if (int(mb[1])&0b00010000)>>4 == 1 {
-			frame.Version2Data.airborneCapabilities.ExtendedSquitter1090MhzReceive = true
+			frame.Version2Data.AirborneCapabilities.ExtendedSquitter1090MhzReceive = true
 		}
 
 		// 3. ARV (Air-Referenced Velocity Report Capability)
 		//     = 0: No capability for sending messages to support Air Referenced Velocity Reports
 		//     = 1: Capability of sending messages to support Air-Referenced Velocity Reports
 		if (int(mb[1])&0b00000010)>>1 == 1 {
-			frame.Version2Data.airborneCapabilities.AirReferencedVelocityReport = true
+			frame.Version2Data.AirborneCapabilities.AirReferencedVelocityReport = true
 		}
 
 		// 4. TS (Target State Report Capability)
 		//     = 0: No capability for sending messages to support Target State Reports
 		//     = 1: Capability of sending messages to support Target State Reports
 		if (int(mb[1]) & 0b00000001) == 1 {
-			frame.Version2Data.airborneCapabilities.TargetStateReport = true
+			frame.Version2Data.AirborneCapabilities.TargetStateReport = true
 		}
 
 		// 5. TC (Target Change Report Capability)
@@ -359,16 +359,16 @@ func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
 		//     = 3: Reserved
 		switch (int(mb[2]) & 0b11000000) >> 6 {
 		case 1:
-			frame.Version2Data.airborneCapabilities.SupportTargetChangePlus0ReportOnly = true
+			frame.Version2Data.AirborneCapabilities.SupportTargetChangePlus0ReportOnly = true
 		case 2:
-			frame.Version2Data.airborneCapabilities.SupportMultipleTargetChangeReports = true
+			frame.Version2Data.AirborneCapabilities.SupportMultipleTargetChangeReports = true
 		}
 
 		// 6. UAT IN (Universal Access Transceiver)
 		//     = 0: Aircraft has No UAT Receive capability
 		//     = 1: Aircraft has UAT Receive capability
 		if (int(mb[2])&0b00100000)>>5 == 1 {
-			frame.Version2Data.airborneCapabilities.UniversalAccessTransceiver = true
+			frame.Version2Data.AirborneCapabilities.UniversalAccessTransceiver = true
 		}
 
 	case 1: // surface
@@ -382,32 +382,32 @@ func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
 		//     = 0: Aircraft has NO 1 090 ES Receive capability
 		//     = 1: Aircraft has 1 090 ES Receive capability
 		if (int(mb[1])&0b00010000)>>4 == 1 {
-			frame.Version2Data.surfaceCapabilities.ExtendedSquitter1090MhzReceive = true
+			frame.Version2Data.SurfaceCapabilities.ExtendedSquitter1090MhzReceive = true
 		}
 
 		// 2. B2 Low (Class B2 Transmit Power Less Than 70 Watts)
 		//     = 0: Greater than or equal to 70 Watts Transmit Power
 		//     = 1: Less than 70 Watts Transmit Power
 		if (int(mb[1])&0b00000010)>>1 == 1 {
-			frame.Version2Data.surfaceCapabilities.ClassB2TransmitPower = true
+			frame.Version2Data.SurfaceCapabilities.ClassB2TransmitPower = true
 		}
 
 		// 3. UAT IN (Universal Access Transceiver)
 		//     = 0: Aircraft has NO UAT Receive capability
 		//     = 1: Aircraft has UAT Receive capability
 		if (int(mb[1]) & 0b00000001) == 1 {
-			frame.Version2Data.surfaceCapabilities.UniversalAccessTransceiver = true
+			frame.Version2Data.SurfaceCapabilities.UniversalAccessTransceiver = true
 		}
 
 		// 4. NACV (Navigation Accuracy Category for Velocity)
-		frame.Version2Data.surfaceCapabilities.NACv = (int(mb[2]) & 0b11100000) >> 5
+		frame.Version2Data.SurfaceCapabilities.NACv = (int(mb[2]) & 0b11100000) >> 5
 
 		// 5. NIC Supplement-C (NIC Supplement for use on the Surface)
-		frame.Version2Data.surfaceCapabilities.NicSupplementC = (int(mb[2]) & 0b00010000) >> 4
+		frame.Version2Data.SurfaceCapabilities.NicSupplementC = (int(mb[2]) & 0b00010000) >> 4
 
 	}
 
-	frame.Version2Data.om = (int(mb[3]) << 8) + int(mb[4])
+	frame.Version2Data.OM = (int(mb[3]) << 8) + int(mb[4])
 
 	switch frame.St {
 	case 0: // airborne
@@ -419,26 +419,26 @@ func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
 		//     = 0: TCAS II or ACAS RA not active
 		//     = 1: TCAS/ACAS RA is active
 		if (int(mb[3])&0b00100000)>>5 == 1 {
-			frame.Version2Data.airborneOperationalModes.TcasAcasResolutionAdvisoryActive = true
+			frame.Version2Data.AirborneOperationalModes.TcasAcasResolutionAdvisoryActive = true
 		}
 
 		// 2. IDENT Switch Active
 		//     = 0: Ident switch not active
 		//     = 1: Ident switch active – retained for 18 ±1 seconds
 		if (int(mb[3])&0b00010000)>>4 == 1 {
-			frame.Version2Data.airborneOperationalModes.IdentSwitchActive = true
+			frame.Version2Data.AirborneOperationalModes.IdentSwitchActive = true
 		}
 
 		// 3. Reserved for Receiving ATC Services
 		//     = 0: Set to ZERO for this edition of this manual
 		if (int(mb[3])&0b00000100)>>2 == 1 {
-			frame.Version2Data.airborneOperationalModes.SingleAntennaFlag = true
+			frame.Version2Data.AirborneOperationalModes.SingleAntennaFlag = true
 		}
 
 		// 4. Single Antenna Flag (SAF)
 		//     = 0: Systems with two functioning antennas
 		//     = 1: Systems that use only one antenna
-		frame.Version2Data.airborneOperationalModes.SystemDesignAssurance = (int(mb[3]) & 0b00000011)
+		frame.Version2Data.AirborneOperationalModes.SystemDesignAssurance = (int(mb[3]) & 0b00000011)
 
 		// 5. System Design Assurance (SDA)
 		// TODO
@@ -452,44 +452,44 @@ func decodeBDS07Version2(mb []byte) (frame BDS07Frame) {
 		//     = 0: TCAS II or ACAS RA not active
 		//     = 1: TCAS/ACAS RA is active
 		if (int(mb[3])&0b00100000)>>5 == 1 {
-			frame.Version2Data.surfaceOperationalModes.TcasAcasResolutionAdvisoryActive = true
+			frame.Version2Data.SurfaceOperationalModes.TcasAcasResolutionAdvisoryActive = true
 		}
 
 		// 2. IDENT Switch Active
 		//    = 0: Ident switch not active
 		//    = 1: Ident switch active – retained for 18 ±1 seconds
 		if (int(mb[3])&0b00010000)>>4 == 1 {
-			frame.Version2Data.surfaceOperationalModes.IdentSwitchActive = true
+			frame.Version2Data.SurfaceOperationalModes.IdentSwitchActive = true
 		}
 
 		// 3. Reserved for Receiving ATC Services
 		//    = 0: Set to ZERO for this edition of this manual
 		if (int(mb[3])&0b00000100)>>2 == 1 {
-			frame.Version2Data.surfaceOperationalModes.SingleAntennaFlag = true
+			frame.Version2Data.SurfaceOperationalModes.SingleAntennaFlag = true
 		}
 
 		// 4. Single Antenna Flag (SAF)
 		//    = 0: Systems with two functioning antennas
 		//    = 1: Systems that use only one antenna
-		frame.Version2Data.surfaceOperationalModes.SystemDesignAssurance = (int(mb[3]) & 0b00000011)
+		frame.Version2Data.SurfaceOperationalModes.SystemDesignAssurance = (int(mb[3]) & 0b00000011)
 
 		// 5. System Design Assurance (SDA)
-		frame.Version2Data.surfaceOperationalModes.GPSAntennaOffset = int(mb[4])
+		frame.Version2Data.SurfaceOperationalModes.GPSAntennaOffset = int(mb[4])
 
 		// 6. GPS Antenna Offset
 		// TODO
 
 	}
 
-	frame.Version2Data.nica = (int(mb[5]) & 0b00010000) >> 4
-	frame.Version2Data.nacp = (int(mb[5]) & 0b00001111)
-	frame.Version2Data.sil = (int(mb[6]) & 0b00110000) >> 4
+	frame.Version2Data.NICSupplementA = (int(mb[5]) & 0b00010000) >> 4
+	frame.Version2Data.NACp = (int(mb[5]) & 0b00001111)
+	frame.Version2Data.SIL = (int(mb[6]) & 0b00110000) >> 4
 	frame.Version2Data.Hrd = (int(mb[6]) & 0b00000100) >> 2 // 0= True heading, 1= Magnetic heading?
-	frame.Version2Data.sils = (int(mb[6]) & 0b00000010) >> 1
+	frame.Version2Data.SILSupplement = (int(mb[6]) & 0b00000010) >> 1
 
 	if frame.St == 0 {
-		frame.Version2Data.gva = (int(mb[6]) & 0b11000000) >> 6
-		frame.Version2Data.bai = (int(mb[6]) & 0b00001000) >> 3
+		frame.Version2Data.GVA = (int(mb[6]) & 0b11000000) >> 6
+		frame.Version2Data.NICbaro = (int(mb[6]) & 0b00001000) >> 3
 	} else {
 		frame.Version2Data.Trk = (int(mb[6]) & 0b00001000) >> 3
 	}
@@ -523,3 +523,86 @@ func DecodeBDS07(mb []byte) (frame BDS07Frame, err error) {
 
 	return
 }
+
+func (frame *BDS07Frame) NACp() (nacp int, ok bool) {
+	// returns Navigational accuracy category - position (version 1 & 2 only)
+	switch frame.Ver {
+	case 1:
+		return frame.Version1Data.NACp, true
+	case 2:
+		return frame.Version2Data.NACp, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) SIL() (sil int, ok bool) {
+	// returns Surveillance/Source integrity level (version 1 & 2 only)
+	switch frame.Ver {
+	case 1:
+		return frame.Version1Data.SIL, true
+	case 2:
+		return frame.Version2Data.SIL, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) SILSupplement() (sils int, ok bool) {
+	// returns SIL supplement (version 2 only)
+	//  0 = probability of exceeding the NIC containment radius is per hour
+	//  1 = probability of exceeding the NIC containment radius is per sample
+	if frame.Ver == 2 {
+		return frame.Version2Data.SILSupplement, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) NICSupplementA() (nica int, ok bool) {
+	// returns NIC supplement - A (version 1 & 2 only)
+	// version 1 has a single NIC supplement bit, which is used in the same way as version 2 NIC supplement - A
+	switch frame.Ver {
+	case 1:
+		return frame.Version1Data.NICSupplement, true
+	case 2:
+		return frame.Version2Data.NICSupplementA, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) NICSupplementC() (nicc int, ok bool) {
+	// returns NIC supplement - C (version 2 surface only)
+	if frame.Ver == 2 && frame.St == 1 {
+		return frame.Version2Data.SurfaceCapabilities.NicSupplementC, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) NACv() (nacv int, ok bool) {
+	// returns Navigation accuracy category - velocity (version 2 surface only)
+	// airborne aircraft report NACv in BDS 0,9
+	if frame.Ver == 2 && frame.St == 1 {
+		return frame.Version2Data.SurfaceCapabilities.NACv, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) NICbaro() (nicbaro int, ok bool) {
+	// returns Barometric altitude integrity code (version 1 & 2 airborne only)
+	if frame.St != 0 {
+		return 0, false
+	}
+	switch frame.Ver {
+	case 1:
+		return frame.Version1Data.NICbaro, true
+	case 2:
+		return frame.Version2Data.NICbaro, true
+	}
+	return 0, false
+}
+
+func (frame *BDS07Frame) GVA() (gva int, ok bool) {
+	// returns Geometric vertical accuracy (version 2 airborne only)
+	if frame.Ver == 2 && frame.St == 0 {
+		return frame.Version2Data.GVA, true
+	}
+	return 0, false
+}
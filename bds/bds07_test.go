@@ -24,19 +24,19 @@ func TestDecodeBDS07(t *testing.T) {
 			tc:   31,
 			ver:  2,
 			version2Data: BDS07FrameVersion2{
-				airborneCapabilities: BDS07FrameVersion2AirborneCapabilities{
+				AirborneCapabilities: BDS07FrameVersion2AirborneCapabilities{
 					ExtendedSquitter1090MhzReceive: true,
 					UniversalAccessTransceiver:     true,
 				},
-				airborneOperationalModes: BDS07FrameVersion2AirborneOperationalModes{
+				AirborneOperationalModes: BDS07FrameVersion2AirborneOperationalModes{
 					SingleAntennaFlag: true,
 				},
-				Hrd:  0, // true heading
-				nica: 0,
-				bai:  1,
-				nacp: 9,
-				gva:  2,
-				sil:  3,
+				Hrd:            0, // true heading
+				NICSupplementA: 0,
+				NICbaro:        1,
+				NACp:           9,
+				GVA:            2,
+				SIL:            3,
 			},
 		},
 		{
@@ -44,14 +44,14 @@ func TestDecodeBDS07(t *testing.T) {
 			tc:   31,
 			ver:  1,
 			version1Data: BDS07FrameVersion1{
-				airborneCapabilities: BDS07FrameVersion1AirborneCapabilities{
+				AirborneCapabilities: BDS07FrameVersion1AirborneCapabilities{
 					AcasOperationalOrUnknown: true,
 				},
-				Hrd:  0, // true heading
-				nics: 0, // nica?
-				bai:  1,
-				nacp: 9,
-				sil:  2,
+				Hrd:           0, // true heading
+				NICSupplement: 0, // nica?
+				NICbaro:       1,
+				NACp:          9,
+				SIL:           2,
 			},
 		},
 	}
@@ -61,27 +61,96 @@ func TestDecodeBDS07(t *testing.T) {
 		frame, err := DecodeBDS07(testData.data)
 		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
 		assert.NoError(err, testMsg+"decodeBDS07 error")
-		assert.Equal(testData.tc, frame.tc, testMsg+"tc")
+		assert.Equal(testData.tc, frame.Tc, testMsg+"tc")
 		assert.Equal(testData.ver, frame.Ver, testMsg+"ver")
 
 		// version 1 frames
-		assert.Equal(testData.version1Data.airborneCapabilities.AcasOperationalOrUnknown, frame.Version1Data.airborneCapabilities.AcasOperationalOrUnknown, testMsg+"version1Data.airborneCapabilities.AcasOperationalOrUnknown")
+		assert.Equal(testData.version1Data.AirborneCapabilities.AcasOperationalOrUnknown, frame.Version1Data.AirborneCapabilities.AcasOperationalOrUnknown, testMsg+"version1Data.AirborneCapabilities.AcasOperationalOrUnknown")
 		assert.Equal(testData.version1Data.Hrd, frame.Version1Data.Hrd, testMsg+"version1Data.hrd")
-		assert.Equal(testData.version1Data.nics, frame.Version1Data.nics, testMsg+"version1Data.nics")
-		assert.Equal(testData.version1Data.bai, frame.Version1Data.bai, testMsg+"version1Data.bai")
-		assert.Equal(testData.version1Data.nacp, frame.Version1Data.nacp, testMsg+"version1Data.nacp")
-		assert.Equal(testData.version1Data.sil, frame.Version1Data.sil, testMsg+"version1Data.sil")
+		assert.Equal(testData.version1Data.NICSupplement, frame.Version1Data.NICSupplement, testMsg+"version1Data.NICSupplement")
+		assert.Equal(testData.version1Data.NICbaro, frame.Version1Data.NICbaro, testMsg+"version1Data.NICbaro")
+		assert.Equal(testData.version1Data.NACp, frame.Version1Data.NACp, testMsg+"version1Data.NACp")
+		assert.Equal(testData.version1Data.SIL, frame.Version1Data.SIL, testMsg+"version1Data.SIL")
 
 		// version 2 frames
-		assert.Equal(testData.version2Data.airborneCapabilities.ExtendedSquitter1090MhzReceive, frame.Version2Data.airborneCapabilities.ExtendedSquitter1090MhzReceive, testMsg+"version2Data.airborneCapabilities.ExtendedSquitter1090MhzReceive")
-		assert.Equal(testData.version2Data.airborneCapabilities.UniversalAccessTransceiver, frame.Version2Data.airborneCapabilities.UniversalAccessTransceiver, testMsg+"version2Data.airborneCapabilities.UniversalAccessTransceiver")
-		assert.Equal(testData.version2Data.airborneOperationalModes.SingleAntennaFlag, frame.Version2Data.airborneOperationalModes.SingleAntennaFlag, testMsg+"version2Data.airborneOperationalModes.SingleAntennaFlag")
+		assert.Equal(testData.version2Data.AirborneCapabilities.ExtendedSquitter1090MhzReceive, frame.Version2Data.AirborneCapabilities.ExtendedSquitter1090MhzReceive, testMsg+"version2Data.AirborneCapabilities.ExtendedSquitter1090MhzReceive")
+		assert.Equal(testData.version2Data.AirborneCapabilities.UniversalAccessTransceiver, frame.Version2Data.AirborneCapabilities.UniversalAccessTransceiver, testMsg+"version2Data.AirborneCapabilities.UniversalAccessTransceiver")
+		assert.Equal(testData.version2Data.AirborneOperationalModes.SingleAntennaFlag, frame.Version2Data.AirborneOperationalModes.SingleAntennaFlag, testMsg+"version2Data.AirborneOperationalModes.SingleAntennaFlag")
 		assert.Equal(testData.version2Data.Hrd, frame.Version2Data.Hrd, testMsg+"version2Data.hrd")
-		assert.Equal(testData.version2Data.nica, frame.Version2Data.nica, testMsg+"version2Data.nica")
-		assert.Equal(testData.version2Data.bai, frame.Version2Data.bai, testMsg+"version2Data.bai")
-		assert.Equal(testData.version2Data.nacp, frame.Version2Data.nacp, testMsg+"version2Data.nacp")
-		assert.Equal(testData.version2Data.gva, frame.Version2Data.gva, testMsg+"version2Data.gva")
-		assert.Equal(testData.version2Data.sil, frame.Version2Data.sil, testMsg+"version2Data.sil")
+		assert.Equal(testData.version2Data.NICSupplementA, frame.Version2Data.NICSupplementA, testMsg+"version2Data.NICSupplementA")
+		assert.Equal(testData.version2Data.NICbaro, frame.Version2Data.NICbaro, testMsg+"version2Data.NICbaro")
+		assert.Equal(testData.version2Data.NACp, frame.Version2Data.NACp, testMsg+"version2Data.NACp")
+		assert.Equal(testData.version2Data.GVA, frame.Version2Data.GVA, testMsg+"version2Data.GVA")
+		assert.Equal(testData.version2Data.SIL, frame.Version2Data.SIL, testMsg+"version2Data.SIL")
+	}
+
+}
+
+func TestBDS07FrameQualityAccessors(t *testing.T) {
+
+	// define test data
+	var testTable = []struct {
+		data      []byte
+		nacp      int
+		nacpOk    bool
+		sil       int
+		silOk     bool
+		nica      int
+		nicaOk    bool
+		nicbaro   int
+		nicbaroOk bool
+		gva       int
+		gvaOk     bool
+	}{
+		{
+			// version 2 airborne
+			data: []byte{0xF8, 0x10, 0x20, 0x06, 0x00, 0x49, 0xB8},
+			nacp: 9, nacpOk: true,
+			sil: 3, silOk: true,
+			nica: 0, nicaOk: true,
+			nicbaro: 1, nicbaroOk: true,
+			gva: 2, gvaOk: true,
+		},
+		{
+			// version 1 airborne
+			data: []byte{0xF8, 0x00, 0x00, 0x00, 0x00, 0x29, 0x28},
+			nacp: 9, nacpOk: true,
+			sil: 2, silOk: true,
+			nica: 0, nicaOk: true,
+			nicbaro: 1, nicbaroOk: true,
+			gva: 0, gvaOk: false,
+		},
+		{
+			// version 0
+			data: []byte{0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		frame, err := DecodeBDS07(testData.data)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		assert.NoError(err, testMsg+"decodeBDS07 error")
+
+		nacp, ok := frame.NACp()
+		assert.Equal(testData.nacp, nacp, testMsg+"NACp")
+		assert.Equal(testData.nacpOk, ok, testMsg+"NACp ok")
+
+		sil, ok := frame.SIL()
+		assert.Equal(testData.sil, sil, testMsg+"SIL")
+		assert.Equal(testData.silOk, ok, testMsg+"SIL ok")
+
+		nica, ok := frame.NICSupplementA()
+		assert.Equal(testData.nica, nica, testMsg+"NICSupplementA")
+		assert.Equal(testData.nicaOk, ok, testMsg+"NICSupplementA ok")
+
+		nicbaro, ok := frame.NICbaro()
+		assert.Equal(testData.nicbaro, nicbaro, testMsg+"NICbaro")
+		assert.Equal(testData.nicbaroOk, ok, testMsg+"NICbaro ok")
+
+		gva, ok := frame.GVA()
+		assert.Equal(testData.gva, gva, testMsg+"GVA")
+		assert.Equal(testData.gvaOk, ok, testMsg+"GVA ok")
 	}
 
 }
@@ -12,10 +12,73 @@ import (
 
 type BDS08Frame struct {
 	// https://mode-s.org/decode/content/ads-b/2-identification.html#aircraft-identification-and-category
-	tc         int    // Type Code
-	ca         int    // Aircraft category
-	capability string // Aircraft category string
-	Callsign   string // Callsign
+	Tc       int    // Type Code
+	CA       int    // Aircraft category
+	Category string // Aircraft category string, eg: "A3"
+	Callsign string // Callsign
+}
+
+// Emitter class, derived from the aircraft category
+type EmitterClass uint8
+
+const EmitterClassUnknown = EmitterClass(0)        // No category information
+const EmitterClassAircraft = EmitterClass(1)       // Aircraft (category set A or B)
+const EmitterClassSurfaceVehicle = EmitterClass(2) // Surface emergency or service vehicle
+const EmitterClassObstacle = EmitterClass(3)       // Ground obstruction
+
+func (ec EmitterClass) String() string {
+	switch ec {
+	case EmitterClassAircraft:
+		return "aircraft"
+	case EmitterClassSurfaceVehicle:
+		return "surface vehicle"
+	case EmitterClassObstacle:
+		return "obstacle"
+	}
+	return "unknown"
+}
+
+// emitter type by aircraft category
+var emitterTypes = map[string]string{
+	"A1": "Light (< 7000 kg)",
+	"A2": "Medium 1 (7000 to 34000 kg)",
+	"A3": "Medium 2 (34000 to 136000 kg)",
+	"A4": "High vortex aircraft",
+	"A5": "Heavy (> 136000 kg)",
+	"A6": "High performance (> 5 g acceleration) and high speed (> 400 kt)",
+	"A7": "Rotorcraft",
+	"B1": "Glider / sailplane",
+	"B2": "Lighter-than-air",
+	"B3": "Parachutist / skydiver",
+	"B4": "Ultralight / hang-glider / paraglider",
+	"B6": "Unmanned aerial vehicle",
+	"B7": "Space / trans-atmospheric vehicle",
+	"C1": "Surface vehicle – emergency vehicle",
+	"C3": "Surface vehicle – service vehicle",
+	"C4": "Ground obstruction",
+	"C5": "Ground obstruction",
+	"C6": "Ground obstruction",
+	"C7": "Ground obstruction",
+}
+
+func (frame *BDS08Frame) EmitterType() string {
+	// returns a description of the emitter type, or "" if no category information or the category is reserved
+	return emitterTypes[frame.Category]
+}
+
+func (frame *BDS08Frame) EmitterClass() EmitterClass {
+	// returns whether the emitter is an aircraft, surface vehicle or obstacle
+	switch {
+	case frame.CA == 0:
+		return EmitterClassUnknown
+	case frame.Tc == 3 || frame.Tc == 4:
+		return EmitterClassAircraft
+	case frame.Tc == 2 && (frame.CA == 1 || frame.CA == 3):
+		return EmitterClassSurfaceVehicle
+	case frame.Tc == 2 && frame.CA >= 4:
+		return EmitterClassObstacle
+	}
+	return EmitterClassUnknown
 }
 
 func DecodeBDS08(mb []byte) (frame BDS08Frame, err error) {
@@ -24,14 +87,14 @@ func DecodeBDS08(mb []byte) (frame BDS08Frame, err error) {
 
 	frame = BDS08Frame{}
 
-	frame.tc = (int(mb[0]) & 0b11111000) >> 3
+	frame.Tc = (int(mb[0]) & 0b11111000) >> 3
 
-	if frame.tc < 1 || frame.tc > 5 {
+	if frame.Tc < 1 || frame.Tc > 4 {
 		err = errors.New("type code not from 1 to 4")
 		return
 	}
 
-	frame.ca = (int(mb[0]) & 0b00000111)
+	frame.CA = (int(mb[0]) & 0b00000111)
 
 	callsignIndexes := make([]int, 8)
 	callsignIndexes[0] = (int(mb[1]) & 0b11111100) >> 2
@@ -57,15 +120,15 @@ func DecodeBDS08(mb []byte) (frame BDS08Frame, err error) {
 	frame.Callsign = strings.TrimSpace(frame.Callsign)
 
 	// determine category code
-	switch frame.tc {
+	switch frame.Tc {
 	case 4:
-		frame.capability = fmt.Sprintf("A%d", frame.ca)
+		frame.Category = fmt.Sprintf("A%d", frame.CA)
 	case 3:
-		frame.capability = fmt.Sprintf("B%d", frame.ca)
+		frame.Category = fmt.Sprintf("B%d", frame.CA)
 	case 2:
-		frame.capability = fmt.Sprintf("C%d", frame.ca)
+		frame.Category = fmt.Sprintf("C%d", frame.CA)
 	case 1:
-		frame.capability = fmt.Sprintf("D%d", frame.ca)
+		frame.Category = fmt.Sprintf("D%d", frame.CA)
 	}
 
 	// sanity check
@@ -10,16 +10,48 @@ import (
 func TestDecodeBDS08(t *testing.T) {
 	// define test data
 	var testTable = []struct {
-		data       []byte
-		tc         int
-		ca         int
-		callsign   string
-		capability string
+		data        []byte
+		tc          int
+		ca          int
+		callsign    string
+		category    string
+		emitterType string
+		class       EmitterClass
 	}{
 		{
-			data:       []byte{0x20, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60},
-			callsign:   "NWK2931",
-			capability: "A0",
+			data:     []byte{0x20, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60},
+			tc:       4,
+			ca:       0,
+			callsign: "NWK2931",
+			category: "A0",
+			class:    EmitterClassUnknown,
+		},
+		{
+			data:        []byte{0x23, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60},
+			tc:          4,
+			ca:          3,
+			callsign:    "NWK2931",
+			category:    "A3",
+			emitterType: "Medium 2 (34000 to 136000 kg)",
+			class:       EmitterClassAircraft,
+		},
+		{
+			data:        []byte{0x13, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60},
+			tc:          2,
+			ca:          3,
+			callsign:    "NWK2931",
+			category:    "C3",
+			emitterType: "Surface vehicle – service vehicle",
+			class:       EmitterClassSurfaceVehicle,
+		},
+		{
+			data:        []byte{0x15, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60},
+			tc:          2,
+			ca:          5,
+			callsign:    "NWK2931",
+			category:    "C5",
+			emitterType: "Ground obstruction",
+			class:       EmitterClassObstacle,
 		},
 	}
 
@@ -28,7 +60,15 @@ func TestDecodeBDS08(t *testing.T) {
 		frame, err := DecodeBDS08(testData.data)
 		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
 		assert.NoError(err, testMsg+"decodeBDS08 error")
+		assert.Equal(testData.tc, frame.Tc, testMsg+"tc")
+		assert.Equal(testData.ca, frame.CA, testMsg+"ca")
 		assert.Equal(testData.callsign, frame.Callsign, testMsg+"callsign")
-		assert.Equal(testData.capability, frame.capability, testMsg+"capability")
+		assert.Equal(testData.category, frame.Category, testMsg+"category")
+		assert.Equal(testData.emitterType, frame.EmitterType(), testMsg+"emitterType")
+		assert.Equal(testData.class, frame.EmitterClass(), testMsg+"class")
 	}
+
+	// type code 5 is a surface position
+	_, err := DecodeBDS08([]byte{0x28, 0x39, 0x72, 0xF2, 0xE7, 0x3C, 0x60})
+	assert.Error(err)
 }
@@ -54,7 +54,7 @@ type BDS09Frame struct {
 	airTrack           float64
 	airSpeedTrackValid bool
 
-	nuc int // Navigation uncertainty category for velocity
+	NACv int // Navigation accuracy category for velocity (NUCr in version 0)
 
 	vrSrc verticalRateSource // Source bit for vertical rate
 	svr   verticalRateSign   // Sign bit for vertical rate
@@ -150,7 +150,7 @@ func DecodeBDS09(mb []byte) (frame BDS09Frame, err error) {
 		frame.ifr = true
 	}
 
-	frame.nuc = ((int(mb[1]) & 0b00111000) >> 3)
+	frame.NACv = ((int(mb[1]) & 0b00111000) >> 3)
 
 	subTypeBits := (int(mb[1]) & 0b00000111 << 19) + (int(mb[2]) << 11) + (int(mb[3]) << 3) + ((int(mb[4]) & 0b11100000) >> 5)
 
@@ -201,6 +201,23 @@ func DecodeBDS09(mb []byte) (frame BDS09Frame, err error) {
 	return
 }
 
+func (frame *BDS09Frame) GroundVelocity() (groundSpeed, groundTrack float64, ok bool) {
+	// returns ground speed (kt) and true track (degrees) for ground speed subtypes
+	// ok is false for airspeed subtypes, or if either velocity component is unavailable
+	if !frame.groundSpeedTrackValid || frame.groundSpeedFields.vew == 0 || frame.groundSpeedFields.vns == 0 {
+		return 0, 0, false
+	}
+	return frame.groundSpeed, frame.groundTrack, true
+}
+
+func (frame *BDS09Frame) VerticalRate() (verticalRate int, ok bool) {
+	// returns vertical rate (ft/min), ok is false if no vertical rate information is available
+	if (frame.svr == verticalRateClimb && frame.vr == -64) || (frame.svr == verticalRateDescent && frame.vr == 64) {
+		return 0, false
+	}
+	return frame.vr, true
+}
+
 func calcAirSpeedAndHeading(st AirborneVelocitySubType, m *BDS09FrameAirSpeed) (vas, mh float64) {
 	// https://mode-s.org/decode/content/ads-b/5-airborne-velocity.html#sub-type-3-and-4-airspeed-decoding
 
@@ -84,7 +84,7 @@ func TestDecodeBDS09(t *testing.T) {
 		assert.Equal(testData.st, frame.st, testMsg+"st")
 		assert.Equal(testData.ic, frame.ic, testMsg+"ic")
 		assert.Equal(testData.ifr, frame.ifr, testMsg+"ifr")
-		assert.Equal(testData.nuc, frame.nuc, testMsg+"nuc")
+		assert.Equal(testData.nuc, frame.NACv, testMsg+"NACv")
 		assert.Equal(testData.vrSrc, frame.vrSrc, testMsg+"vrSrc")
 		assert.Equal(testData.svr, frame.svr, testMsg+"svr")
 		assert.Equal(testData.vr, frame.vr, testMsg+"vr")
@@ -98,3 +98,23 @@ func TestDecodeBDS09(t *testing.T) {
 		assert.Equal(testData.airTrack, math.Round(frame.airTrack*10)/10, testMsg+"airTrack")
 	}
 }
+
+func TestBDS09Accessors(t *testing.T) {
+	assert := assert.New(t)
+
+	frame, err := DecodeBDS09([]byte{0x99, 0x44, 0xC2, 0x83, 0x68, 0x2C, 0x01})
+	assert.NoError(err)
+	gs, trk, ok := frame.GroundVelocity()
+	assert.True(ok)
+	assert.InDelta(194.7, gs, 0.1)
+	assert.InDelta(262.3, trk, 0.1)
+	vr, ok := frame.VerticalRate()
+	assert.True(ok)
+	assert.Equal(-640, vr)
+
+	// no vertical rate information
+	frame, err = DecodeBDS09([]byte{0x99, 0x44, 0xC2, 0x83, 0x60, 0x00, 0x01})
+	assert.NoError(err)
+	_, ok = frame.VerticalRate()
+	assert.False(ok)
+}
@@ -1,13 +1,90 @@
 package bds
 
+// BDS1,0 Data link capability report
+// See "Technical Provisions for Mode S Services and Extended Squitter", Doc 9871
+// Table A-2-16. BDS code 1,0 — Data link capability report
+
 import (
 	"errors"
 )
 
 type BDS10Frame struct {
+	// Continuation flag
+	//
+	// Indicates that the capability report continues in BDS 1,1
+	ContinuationFlag bool
+
+	// Overlay Command Capability (OCC)
+	OverlayCommandCapability bool
+
+	// ACAS status
+	//  false = ACAS failed or on standby
+	//  true = ACAS operating
+	AcasOperating bool
+
+	// Mode S subnetwork version number
+	//  0 = Mode S subnetwork not available
+	//  1 = Version 1 (1996)
+	//  2 = Version 2 (1998)
+	//  3 = Version 3 (2002)
+	//  4 = Version 4 (2007)
+	//  5 = Version 5 (2010)
+	ModeSSubnetworkVersion int
+
+	// Transponder enhanced protocol indicator
+	//  false = Level 2 to 4 transponder
+	//  true = Level 5 transponder
+	EnhancedProtocol bool
+
+	// Mode S specific services capability
+	SpecificServicesCapability bool
+
+	// Uplink ELM average throughput capability
+	UplinkELMThroughput int
+
+	// Downlink ELM throughput capability
+	DownlinkELMThroughput int
+
+	// Aircraft identification capability
+	AircraftIdentificationCapability bool
+
+	// Squitter capability subfield (SCS)
+	//
+	// Set when both BDS 0,5 and 0,6 have been updated within the last 5 and 10 seconds
+	SquitterCapability bool
+
+	// Surveillance identifier code (SIC) capability
+	SurveillanceIdentifierCapability bool
+
+	// Common usage GICB capability report
+	//
+	// Toggled each time BDS 1,7 changes
+	GICBCapabilityReportChanged bool
+
+	// ACAS hybrid surveillance capability
+	AcasHybridSurveillance bool
+
+	// ACAS resolution advisory capability
+	//  false = ACAS generating TAs only
+	//  true = ACAS generating both TAs and RAs
+	AcasResolutionAdvisory bool
+
+	// ACAS version
+	//  0 = RTCA DO-185 (pre-ACAS)
+	//  1 = RTCA DO-185A
+	//  2 = RTCA DO-185B & EUROCAE ED-143
+	//  3 = Reserved for future versions
+	AcasVersion int
+
+	// Data terminal equipment (DTE) status
+	//
+	// One bit per DTE sub-address (0-15), set if the DTE sub-address is supported
+	DTEStatus uint16
 }
 
 func DecodeBDS10(mb []byte) (frame BDS10Frame, err error) {
+	// decode Data link capability report (BDS 1,0)
+	// https://mode-s.org/decode/content/mode-s/6-els.html
 
 	// check the bds code matches
 	if int(mb[0]) != 0b00010000 {
@@ -21,9 +98,30 @@ func DecodeBDS10(mb []byte) (frame BDS10Frame, err error) {
 		return
 	}
 
-	// There's lots of other info that we probably don't care about
-	// See "Technical Provisions for Mode S Services and Extended Squitter", Doc 9871
-	// Table A-2-16. BDS code 1,0 — Data link capability report
+	frame.ContinuationFlag = (int(mb[1])&0b10000000)>>7 == 1
+	frame.OverlayCommandCapability = (int(mb[1])&0b00000010)>>1 == 1
+	frame.AcasOperating = int(mb[1])&0b00000001 == 1
+	frame.ModeSSubnetworkVersion = (int(mb[2]) & 0b11111110) >> 1
+	frame.EnhancedProtocol = int(mb[2])&0b00000001 == 1
+	frame.SpecificServicesCapability = (int(mb[3])&0b10000000)>>7 == 1
+	frame.UplinkELMThroughput = (int(mb[3]) & 0b01110000) >> 4
+	frame.DownlinkELMThroughput = int(mb[3]) & 0b00001111
+	frame.AircraftIdentificationCapability = (int(mb[4])&0b10000000)>>7 == 1
+	frame.SquitterCapability = (int(mb[4])&0b01000000)>>6 == 1
+	frame.SurveillanceIdentifierCapability = (int(mb[4])&0b00100000)>>5 == 1
+	frame.GICBCapabilityReportChanged = (int(mb[4])&0b00010000)>>4 == 1
+	frame.AcasHybridSurveillance = (int(mb[4])&0b00001000)>>3 == 1
+	frame.AcasResolutionAdvisory = (int(mb[4])&0b00000100)>>2 == 1
+	frame.AcasVersion = int(mb[4]) & 0b00000011
+	frame.DTEStatus = uint16((int(mb[5]) << 8) + int(mb[6]))
 
 	return
 }
+
+func (frame *BDS10Frame) TransponderLevel() string {
+	// returns the transponder level, as far as can be determined from the capability report
+	if frame.EnhancedProtocol {
+		return "Level 5"
+	}
+	return "Level 2-4"
+}
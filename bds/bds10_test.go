@@ -11,21 +11,79 @@ func TestDecodeBDS10(t *testing.T) {
 
 	// define test data
 	var testTable = []struct {
-		data          []byte
-		expectedError bool
+		data                   []byte
+		expectedError          bool
+		expectedOverlay        bool
+		expectedAcasOperating  bool
+		expectedSubnetwork     int
+		expectedLevel          string
+		expectedSquitter       bool
+		expectedSIC            bool
+		expectedAcasRA         bool
+		expectedAcasVersion    int
+		expectedDTEStatus      uint16
+		expectedContinuation   bool
+		expectedSpecificServes bool
 	}{
 		{
-			data:          []byte{0x10, 0x03, 0x0a, 0x80, 0xf5, 0x00, 0x00},
-			expectedError: false,
+			data:                   []byte{0x10, 0x03, 0x0a, 0x80, 0xf5, 0x00, 0x00},
+			expectedError:          false,
+			expectedOverlay:        true,
+			expectedAcasOperating:  true,
+			expectedSubnetwork:     5,
+			expectedLevel:          "Level 2-4",
+			expectedSquitter:       true,
+			expectedSIC:            true,
+			expectedAcasRA:         true,
+			expectedAcasVersion:    1,
+			expectedDTEStatus:      0,
+			expectedContinuation:   false,
+			expectedSpecificServes: true,
+		},
+		{
+			data:                   []byte{0x10, 0x80, 0x07, 0x00, 0x40, 0x80, 0x01},
+			expectedError:          false,
+			expectedOverlay:        false,
+			expectedAcasOperating:  false,
+			expectedSubnetwork:     3,
+			expectedLevel:          "Level 5",
+			expectedSquitter:       true,
+			expectedSIC:            false,
+			expectedAcasRA:         false,
+			expectedAcasVersion:    0,
+			expectedDTEStatus:      0x8001,
+			expectedContinuation:   true,
+			expectedSpecificServes: false,
+		},
+		{
+			// reserved bits set
+			data:          []byte{0x10, 0x43, 0x0a, 0x80, 0xf5, 0x00, 0x00},
+			expectedError: true,
+		},
+		{
+			// bds code mismatch
+			data:          []byte{0x20, 0x03, 0x0a, 0x80, 0xf5, 0x00, 0x00},
+			expectedError: true,
 		},
 	}
 
 	assert := assert.New(t)
 	for _, testData := range testTable {
-		_, err := DecodeBDS10(testData.data)
+		frame, err := DecodeBDS10(testData.data)
 		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
 		if !testData.expectedError {
 			assert.NoError(err, testMsg+"DecodeBDS10 error")
+			assert.Equal(testData.expectedContinuation, frame.ContinuationFlag, testMsg+"ContinuationFlag")
+			assert.Equal(testData.expectedOverlay, frame.OverlayCommandCapability, testMsg+"OverlayCommandCapability")
+			assert.Equal(testData.expectedAcasOperating, frame.AcasOperating, testMsg+"AcasOperating")
+			assert.Equal(testData.expectedSubnetwork, frame.ModeSSubnetworkVersion, testMsg+"ModeSSubnetworkVersion")
+			assert.Equal(testData.expectedLevel, frame.TransponderLevel(), testMsg+"TransponderLevel")
+			assert.Equal(testData.expectedSpecificServes, frame.SpecificServicesCapability, testMsg+"SpecificServicesCapability")
+			assert.Equal(testData.expectedSquitter, frame.SquitterCapability, testMsg+"SquitterCapability")
+			assert.Equal(testData.expectedSIC, frame.SurveillanceIdentifierCapability, testMsg+"SurveillanceIdentifierCapability")
+			assert.Equal(testData.expectedAcasRA, frame.AcasResolutionAdvisory, testMsg+"AcasResolutionAdvisory")
+			assert.Equal(testData.expectedAcasVersion, frame.AcasVersion, testMsg+"AcasVersion")
+			assert.Equal(testData.expectedDTEStatus, frame.DTEStatus, testMsg+"DTEStatus")
 		} else {
 			assert.Error(err, testMsg+"DecodeBDS10 no error")
 		}
@@ -0,0 +1,74 @@
+package bds
+
+// BDS1,7 Common usage GICB capability report
+// See "Technical Provisions for Mode S Services and Extended Squitter", Doc 9871
+// Table A-2-23. BDS code 1,7 — Common usage GICB capability report
+
+import (
+	"errors"
+)
+
+type BDS17Frame struct {
+	// Capability bitmap
+	//
+	// MB bit 1 (BDS 0,5) is the most significant of 24 bits
+	Capabilities uint32
+}
+
+// registers reported in BDS 1,7, in MB bit order
+var gicbRegisters = []string{
+	"0,5", "0,6", "0,7", "0,8", "0,9", "0,A",
+	"2,0", "2,1",
+	"4,0", "4,1", "4,2", "4,3", "4,4", "4,5", "4,8",
+	"5,0", "5,1", "5,2", "5,3", "5,4", "5,5", "5,6", "5,F",
+	"6,0",
+}
+
+// MB bit (from 1) for registers that have a BDScode
+var gicbRegisterBits = map[BDScode]int{
+	BDS05: 1, BDS06: 2, BDS07: 3, BDS08: 4, BDS09: 5,
+	BDS20: 7, BDS21: 8,
+	BDS40: 9, BDS41: 10, BDS42: 11, BDS43: 12, BDS44: 13, BDS45: 14, BDS48: 15,
+	BDS50: 16, BDS51: 17, BDS52: 18, BDS53: 19, BDS54: 20, BDS55: 21, BDS56: 22,
+	BDS60: 24,
+}
+
+func DecodeBDS17(mb []byte) (frame BDS17Frame, err error) {
+	// decode Common usage GICB capability report (BDS 1,7)
+	// https://mode-s.org/decode/content/mode-s/6-els.html
+
+	// an aircraft that reports GICB capability must support BDS 2,0
+	if int(mb[0])&0b00000010 != 0b00000010 {
+		err = errors.New("bds code mismatch")
+		return
+	}
+
+	// check reserved bits
+	if int(mb[3])&0b00001111 != 0 || int(mb[4]) != 0 || int(mb[5]) != 0 || int(mb[6]) != 0 {
+		err = errors.New("reserved bits not zero")
+		return
+	}
+
+	frame.Capabilities = uint32((int(mb[0]) << 16) + (int(mb[1]) << 8) + int(mb[2]))
+
+	return
+}
+
+func (frame *BDS17Frame) Supports(code BDScode) bool {
+	// returns true if the aircraft reports that the register is available
+	bit, ok := gicbRegisterBits[code]
+	if !ok {
+		return false
+	}
+	return frame.Capabilities&(1<<(24-bit)) != 0
+}
+
+func (frame *BDS17Frame) Registers() (registers []string) {
+	// returns the registers the aircraft reports as available, eg: "4,0"
+	for i, register := range gicbRegisters {
+		if frame.Capabilities&(1<<(23-i)) != 0 {
+			registers = append(registers, register)
+		}
+	}
+	return registers
+}
@@ -0,0 +1,65 @@
+package bds
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBDS17(t *testing.T) {
+
+	// define test data
+	var testTable = []struct {
+		data              []byte
+		expectedError     bool
+		expectedRegisters []string
+		supported         []BDScode
+		unsupported       []BDScode
+	}{
+		{
+			// A0000638FA81C10000000081A92F
+			data:              []byte{0xfa, 0x81, 0xc1, 0x00, 0x00, 0x00, 0x00},
+			expectedError:     false,
+			expectedRegisters: []string{"0,5", "0,6", "0,7", "0,8", "0,9", "2,0", "4,0", "5,0", "5,1", "5,2", "6,0"},
+			supported:         []BDScode{BDS05, BDS20, BDS40, BDS50, BDS60},
+			unsupported:       []BDScode{BDS21, BDS44, BDS45, BDS53, BDS10},
+		},
+		{
+			data:              []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expectedError:     false,
+			expectedRegisters: []string{"2,0"},
+			supported:         []BDScode{BDS20},
+			unsupported:       []BDScode{BDS05, BDS60},
+		},
+		{
+			// BDS 2,0 not supported
+			data:          []byte{0xf8, 0x81, 0xc1, 0x00, 0x00, 0x00, 0x00},
+			expectedError: true,
+		},
+		{
+			// reserved bits set
+			data:          []byte{0xfa, 0x81, 0xc1, 0x00, 0x00, 0x01, 0x00},
+			expectedError: true,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		frame, err := DecodeBDS17(testData.data)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		if !testData.expectedError {
+			assert.NoError(err, testMsg+"DecodeBDS17 error")
+			assert.Equal(testData.expectedRegisters, frame.Registers(), testMsg+"Registers")
+			for _, code := range testData.supported {
+				assert.True(frame.Supports(code), testMsg+fmt.Sprintf("Supports(%d)", code))
+			}
+			for _, code := range testData.unsupported {
+				assert.False(frame.Supports(code), testMsg+fmt.Sprintf("Supports(%d)", code))
+			}
+		} else {
+			assert.Error(err, testMsg+"DecodeBDS17 no error")
+		}
+	}
+
+}
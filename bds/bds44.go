@@ -1,10 +1,123 @@
 package bds
 
+// BDS4,4 Meteorological routine air report (MRAR)
+// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-routine-air-report-bds-44
+
 import (
 	"errors"
 	"math"
 )
 
+type BDS44Frame struct {
+	// Figure of Merit / Source
+	//
+	//  0 = Invalid
+	//  1 = INS
+	//  2 = GNSS
+	//  3 = DME/DME
+	//  4 = VOR/DME
+	FigureOfMerit int
+
+	WindValid     bool
+	WindSpeed     float64 // Wind speed (kt)
+	WindDirection float64 // Wind direction (degrees, true)
+
+	StaticAirTemperatureValid bool
+	StaticAirTemperature      float64 // Static air temperature (°C)
+
+	AverageStaticPressureValid bool
+	AverageStaticPressure      int // Average static pressure (hPa)
+
+	TurbulenceValid bool
+	Turbulence      HazardLevel // Turbulence
+
+	HumidityValid bool
+	Humidity      float64 // Humidity (%)
+}
+
+// Hazard level, used by turbulence, wind shear, microburst, icing and wake vortex
+type HazardLevel uint8
+
+const HazardNil = HazardLevel(0)      // Nil
+const HazardLight = HazardLevel(1)    // Light
+const HazardModerate = HazardLevel(2) // Moderate
+const HazardSevere = HazardLevel(3)   // Severe
+
+func (h HazardLevel) String() string {
+	switch h {
+	case HazardNil:
+		return "nil"
+	case HazardLight:
+		return "light"
+	case HazardModerate:
+		return "moderate"
+	case HazardSevere:
+		return "severe"
+	}
+	return "unknown"
+}
+
+func DecodeBDS44(mb []byte) (frame BDS44Frame, err error) {
+	// decode Meteorological routine air report (BDS 4,4)
+	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-routine-air-report-bds-44
+
+	// Figure of merit / source
+	frame.FigureOfMerit = decodeBDS44figureOfMerit(mb)
+	if frame.FigureOfMerit > 4 {
+		err = errors.New("figure of merit set to reserved value")
+		return
+	}
+
+	// Wind speed and direction
+	switch (int(mb[0]) & 0b00001000) >> 3 {
+	case 0:
+		frame.WindValid = false
+	case 1:
+		frame.WindValid = true
+		frame.WindSpeed, frame.WindDirection, err = decodeBDS44windSpeedDirection(mb)
+		if err != nil {
+			return
+		}
+	}
+
+	// Static air temperature
+	// there is no status bit, reports without a temperature have the sign & magnitude bits zeroed
+	frame.StaticAirTemperature, err = decodeBDS44staticAirTemperature(mb)
+	if err != nil {
+		return
+	}
+	frame.StaticAirTemperatureValid = isBDS44staticAirTemperaturePresent(mb)
+
+	// Average static pressure
+	switch (int(mb[4]) & 0b00100000) >> 5 {
+	case 0:
+		frame.AverageStaticPressureValid = false
+	case 1:
+		frame.AverageStaticPressureValid = true
+		frame.AverageStaticPressure = decodeBDS44averageStaticPressure(mb)
+	}
+
+	// Turbulence
+	switch (int(mb[5]) & 0b00000010) >> 1 {
+	case 0:
+		frame.TurbulenceValid = false
+	case 1:
+		frame.TurbulenceValid = true
+		frame.Turbulence = HazardLevel(((int(mb[5]) & 0b00000001) << 1) + ((int(mb[6]) & 0b10000000) >> 7))
+	}
+
+	// Humidity
+	switch (int(mb[6]) & 0b01000000) >> 6 {
+	case 0:
+		frame.HumidityValid = false
+	case 1:
+		frame.HumidityValid = true
+		frame.Humidity = float64(int(mb[6])&0b00111111) * (100.0 / 64.0)
+	}
+
+	return
+}
+
 func decodeBDS44figureOfMerit(mb []byte) (fom int) {
 	// decode figure of merit from BDS 4,4 message
 	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-routine-air-report-bds-44
@@ -42,10 +155,11 @@ func decodeBDS44staticAirTemperature(mb []byte) (staticAirTemperature float64, e
 	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-routine-air-report-bds-44
 
 	sign := int(mb[2]) & 0b00000001
-	staticAirTemperature = (float64(((int(mb[3]))<<2)+((int(mb[4])&0b11000000)>>6)) - math.Pow(2, 10)) * 0.25
+	staticAirTemperature = float64(((int(mb[3]))<<2)+((int(mb[4])&0b11000000)>>6)) * 0.25
 
-	if sign != 1 {
-		staticAirTemperature *= -1
+	// two's complement
+	if sign == 1 {
+		staticAirTemperature -= math.Pow(2, 10) * 0.25
 	}
 
 	if staticAirTemperature < -80 || staticAirTemperature > 60 {
@@ -53,3 +167,17 @@ func decodeBDS44staticAirTemperature(mb []byte) (staticAirTemperature float64, e
 	}
 	return
 }
+
+func isBDS44staticAirTemperaturePresent(mb []byte) bool {
+	// returns false if the static air temperature field (sign & magnitude) is all zero
+	// transponders without a temperature source send the field zeroed, which is also the encoding of 0.00 °C,
+	// so a reading of exactly 0.00 °C can't be told apart from no reading and is treated as missing
+	return int(mb[2])&0b00000001 != 0 || mb[3] != 0 || int(mb[4])&0b11000000 != 0
+}
+
+func decodeBDS44averageStaticPressure(mb []byte) (averageStaticPressure int) {
+	// decode average static pressure (hPa) from BDS 4,4 message
+	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-routine-air-report-bds-44
+	averageStaticPressure = ((int(mb[4]) & 0b00011111) << 6) + ((int(mb[5]) & 0b11111100) >> 2)
+	return
+}
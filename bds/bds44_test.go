@@ -1,22 +1,117 @@
 package bds
 
-// func TestDecodeBDS44windSpeedDirection(t *testing.T) {
-// 	data := []byte{0xA0, 0x00, 0x16, 0x92, 0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00, 0xDF, 0xC6, 0x96}
-// 	df := getDF(data)
-// 	assert.Equal(t, DF20, df)
-// 	msg := decodeDF20(data)
-// 	ws, wd, err := decodeBDS44windSpeedDirection(msg.mb)
-// 	assert.NoError(t, err)
-// 	assert.Equal(t, float64(22), ws)
-// 	assert.Equal(t, float64(344.53125), wd)
-// }
+import (
+	"fmt"
+	"testing"
 
-// func TestDecodeBDS44staticAirTemperature(t *testing.T) {
-// 	data := []byte{0xA0, 0x00, 0x16, 0x92, 0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00, 0xDF, 0xC6, 0x96}
-// 	df := getDF(data)
-// 	assert.Equal(t, DF20, df)
-// 	msg := decodeDF20(data)
-// 	sat, err := decodeBDS44staticAirTemperature(msg.mb)
-// 	assert.NoError(t, err)
-// 	assert.Equal(t, -48.75, sat)
-// }
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBDS44(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		data                              []byte
+		expectedFigureOfMerit             int
+		expectedWindValid                 bool
+		expectedWindSpeed                 float64
+		expectedWindDirection             float64
+		expectedStaticAirTemperatureValid bool
+		expectedStaticAirTemperature      float64
+		expectedAverageStaticPressure     bool
+		expectedTurbulenceValid           bool
+		expectedHumidityValid             bool
+	}{
+		{
+			// A0001692185BD5CF400000DFC696
+			data:                              []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00},
+			expectedFigureOfMerit:             1,
+			expectedWindValid:                 true,
+			expectedWindSpeed:                 22,
+			expectedWindDirection:             344.53125,
+			expectedStaticAirTemperatureValid: true,
+			expectedStaticAirTemperature:      -48.75,
+			expectedAverageStaticPressure:     false,
+			expectedTurbulenceValid:           false,
+			expectedHumidityValid:             false,
+		},
+		{
+			// wind only, no temperature
+			data:                              []byte{0x18, 0x5B, 0xD4, 0x00, 0x00, 0x00, 0x00},
+			expectedFigureOfMerit:             1,
+			expectedWindValid:                 true,
+			expectedWindSpeed:                 22,
+			expectedWindDirection:             344.53125,
+			expectedStaticAirTemperatureValid: false,
+			expectedStaticAirTemperature:      0,
+			expectedAverageStaticPressure:     false,
+			expectedTurbulenceValid:           false,
+			expectedHumidityValid:             false,
+		},
+		{
+			// wind & temperature just above freezing, only the lowest magnitude bit set
+			data:                              []byte{0x18, 0x5B, 0xD4, 0x00, 0x40, 0x00, 0x00},
+			expectedFigureOfMerit:             1,
+			expectedWindValid:                 true,
+			expectedWindSpeed:                 22,
+			expectedWindDirection:             344.53125,
+			expectedStaticAirTemperatureValid: true,
+			expectedStaticAirTemperature:      0.25,
+			expectedAverageStaticPressure:     false,
+			expectedTurbulenceValid:           false,
+			expectedHumidityValid:             false,
+		},
+		{
+			// wind & temperature just below freezing, sign bit set
+			data:                              []byte{0x18, 0x5B, 0xD5, 0xFF, 0xC0, 0x00, 0x00},
+			expectedFigureOfMerit:             1,
+			expectedWindValid:                 true,
+			expectedWindSpeed:                 22,
+			expectedWindDirection:             344.53125,
+			expectedStaticAirTemperatureValid: true,
+			expectedStaticAirTemperature:      -0.25,
+			expectedAverageStaticPressure:     false,
+			expectedTurbulenceValid:           false,
+			expectedHumidityValid:             false,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		frame, err := DecodeBDS44(testData.data)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		assert.NoError(err, testMsg+"DecodeBDS44 error")
+		assert.Equal(testData.expectedFigureOfMerit, frame.FigureOfMerit, testMsg+"FigureOfMerit")
+		assert.Equal(testData.expectedWindValid, frame.WindValid, testMsg+"WindValid")
+		assert.Equal(testData.expectedWindSpeed, frame.WindSpeed, testMsg+"WindSpeed")
+		assert.Equal(testData.expectedWindDirection, frame.WindDirection, testMsg+"WindDirection")
+		assert.Equal(testData.expectedStaticAirTemperatureValid, frame.StaticAirTemperatureValid, testMsg+"StaticAirTemperatureValid")
+		assert.Equal(testData.expectedStaticAirTemperature, frame.StaticAirTemperature, testMsg+"StaticAirTemperature")
+		assert.Equal(testData.expectedAverageStaticPressure, frame.AverageStaticPressureValid, testMsg+"AverageStaticPressureValid")
+		assert.Equal(testData.expectedTurbulenceValid, frame.TurbulenceValid, testMsg+"TurbulenceValid")
+		assert.Equal(testData.expectedHumidityValid, frame.HumidityValid, testMsg+"HumidityValid")
+	}
+}
+
+func TestDecodeBDS44staticAirTemperature(t *testing.T) {
+	var testTable = []struct {
+		mb  []byte
+		sat float64
+	}{
+		{
+			mb:  []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00},
+			sat: -48.75,
+		},
+		{
+			mb:  []byte{0x18, 0x5B, 0xD4, 0x0A, 0x40, 0x00, 0x00},
+			sat: 10.25,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		sat, err := decodeBDS44staticAirTemperature(testData.mb)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.mb)
+		assert.NoError(err, testMsg+"decodeBDS44staticAirTemperature error")
+		assert.Equal(testData.sat, sat, testMsg+"sat")
+	}
+}
@@ -1,10 +1,133 @@
 package bds
 
+// BDS4,5 Meteorological hazard report (MHR)
+// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-hazard-report-bds-45
+
 import (
 	"errors"
 	"math"
 )
 
+type BDS45Frame struct {
+	TurbulenceValid bool
+	Turbulence      HazardLevel // Turbulence
+
+	WindShearValid bool
+	WindShear      HazardLevel // Wind shear
+
+	MicroburstValid bool
+	Microburst      HazardLevel // Microburst
+
+	IcingValid bool
+	Icing      HazardLevel // Icing
+
+	WakeVortexValid bool
+	WakeVortex      HazardLevel // Wake vortex
+
+	StaticAirTemperatureValid bool
+	StaticAirTemperature      float64 // Static air temperature (°C)
+
+	AverageStaticPressureValid bool
+	AverageStaticPressure      int // Average static pressure (hPa)
+
+	RadioHeightValid bool
+	RadioHeight      int // Radio height (ft)
+}
+
+func DecodeBDS45(mb []byte) (frame BDS45Frame, err error) {
+	// decode Meteorological hazard report (BDS 4,5)
+	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-hazard-report-bds-45
+
+	// check reserved bits
+	if int(mb[6])&0b00011111 != 0 {
+		err = errors.New("reserved bits not zero")
+		return
+	}
+
+	// Turbulence
+	switch (int(mb[0]) & 0b10000000) >> 7 {
+	case 0:
+		frame.TurbulenceValid = false
+	case 1:
+		frame.TurbulenceValid = true
+		turbulence, _ := decodeBDS45turbulence(mb)
+		frame.Turbulence = HazardLevel(turbulence)
+	}
+
+	// Wind shear
+	switch (int(mb[0]) & 0b00010000) >> 4 {
+	case 0:
+		frame.WindShearValid = false
+	case 1:
+		frame.WindShearValid = true
+		windShear, _ := decodeBDS45windShear(mb)
+		frame.WindShear = HazardLevel(windShear)
+	}
+
+	// Microburst
+	switch (int(mb[0]) & 0b00000010) >> 1 {
+	case 0:
+		frame.MicroburstValid = false
+	case 1:
+		frame.MicroburstValid = true
+		frame.Microburst = HazardLevel(((int(mb[0]) & 0b00000001) << 1) + ((int(mb[1]) & 0b10000000) >> 7))
+	}
+
+	// Icing
+	switch (int(mb[1]) & 0b01000000) >> 6 {
+	case 0:
+		frame.IcingValid = false
+	case 1:
+		frame.IcingValid = true
+		frame.Icing = HazardLevel((int(mb[1]) & 0b00110000) >> 4)
+	}
+
+	// Wake vortex
+	switch (int(mb[1]) & 0b00001000) >> 3 {
+	case 0:
+		frame.WakeVortexValid = false
+	case 1:
+		frame.WakeVortexValid = true
+		frame.WakeVortex = HazardLevel((int(mb[1]) & 0b00000110) >> 1)
+	}
+
+	// Static air temperature
+	switch int(mb[1]) & 0b00000001 {
+	case 0:
+		frame.StaticAirTemperatureValid = false
+	case 1:
+		frame.StaticAirTemperatureValid = true
+		frame.StaticAirTemperature, err = decodeBDS45staticAirTemperature(mb)
+		if err != nil {
+			return
+		}
+		if frame.StaticAirTemperature < -80 || frame.StaticAirTemperature > 60 {
+			err = errors.New("static air temperature out of range [-80,60]")
+			return
+		}
+	}
+
+	// Average static pressure
+	switch (int(mb[3]) & 0b00100000) >> 5 {
+	case 0:
+		frame.AverageStaticPressureValid = false
+	case 1:
+		frame.AverageStaticPressureValid = true
+		frame.AverageStaticPressure = ((int(mb[3]) & 0b00011111) << 6) + ((int(mb[4]) & 0b11111100) >> 2)
+	}
+
+	// Radio height
+	switch (int(mb[4]) & 0b00000010) >> 1 {
+	case 0:
+		frame.RadioHeightValid = false
+	case 1:
+		frame.RadioHeightValid = true
+		frame.RadioHeight = (((int(mb[4]) & 0b00000001) << 11) + (int(mb[5]) << 3) + ((int(mb[6]) & 0b11100000) >> 5)) * 16
+	}
+
+	return
+}
+
 func decodeBDS45turbulence(mb []byte) (turbulence int, err error) {
 	// decode turbulence data from BDS4,5 message
 	// https://mode-s.org/decode/content/mode-s/8-meteo.html#meteorological-hazard-report-bds-45
@@ -43,10 +166,11 @@ func decodeBDS45staticAirTemperature(mb []byte) (staticAirTemperature float64, e
 
 	sign := (int(mb[2]) & 0b10000000) >> 7
 
-	staticAirTemperature = (float64(((int(mb[2])&0b01111111)<<2)+((int(mb[3])&0b11000000)>>6)) - math.Pow(2, 9)) * 0.25
+	staticAirTemperature = float64(((int(mb[2])&0b01111111)<<2)+((int(mb[3])&0b11000000)>>6)) * 0.25
 
-	if sign != 1 {
-		staticAirTemperature *= -1
+	// two's complement
+	if sign == 1 {
+		staticAirTemperature -= math.Pow(2, 9) * 0.25
 	}
 
 	return
@@ -26,3 +26,22 @@ func TestDecodeBDS45staticAirTemperature(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, -48.75, sat)
 }
+
+func TestDecodeBDS45(t *testing.T) {
+	mb := []byte{0xC0, 0x51, 0xCF, 0x40, 0x02, 0x0C, 0x80}
+	frame, err := DecodeBDS45(mb)
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.True(frame.TurbulenceValid)
+	assert.Equal(HazardModerate, frame.Turbulence)
+	assert.False(frame.WindShearValid)
+	assert.False(frame.MicroburstValid)
+	assert.True(frame.IcingValid)
+	assert.Equal(HazardLight, frame.Icing)
+	assert.False(frame.WakeVortexValid)
+	assert.True(frame.StaticAirTemperatureValid)
+	assert.Equal(-48.75, frame.StaticAirTemperature)
+	assert.False(frame.AverageStaticPressureValid)
+	assert.True(frame.RadioHeightValid)
+	assert.Equal(1600, frame.RadioHeight)
+}
@@ -0,0 +1,127 @@
+package bds
+
+// BDS5,3 Air-referenced state vector
+
+import (
+	"errors"
+)
+
+type BDS53Frame struct {
+	MagneticHeadingValid bool
+	MagneticHeading      float64
+
+	IndicatedAirspeedValid bool
+	IndicatedAirspeed      float64
+
+	MachNumberValid bool
+	MachNumber      float64
+
+	TrueAirspeedValid bool
+	TrueAirspeed      float64
+
+	AltitudeRateValid bool
+	AltitudeRate      float64
+}
+
+func DecodeBDS53(mb []byte) (frame BDS53Frame, err error) {
+
+	// magnetic heading
+	switch (int(mb[0]) & 0b10000000) >> 7 {
+	case 0:
+		frame.MagneticHeadingValid = false
+	case 1:
+		frame.MagneticHeadingValid = true
+		frame.MagneticHeading = decodeBDS53magneticHeading(mb)
+	}
+
+	// indicated airspeed
+	switch (int(mb[1]) & 0b00001000) >> 3 {
+	case 0:
+		frame.IndicatedAirspeedValid = false
+	case 1:
+		frame.IndicatedAirspeedValid = true
+		frame.IndicatedAirspeed = decodeBDS53indicatedAirspeed(mb)
+	}
+
+	// mach number
+	switch int(mb[2]) & 0b00000001 {
+	case 0:
+		frame.MachNumberValid = false
+	case 1:
+		frame.MachNumberValid = true
+		frame.MachNumber = decodeBDS53machNumber(mb)
+	}
+
+	// true airspeed
+	switch (int(mb[4]) & 0b01000000) >> 6 {
+	case 0:
+		frame.TrueAirspeedValid = false
+	case 1:
+		frame.TrueAirspeedValid = true
+		frame.TrueAirspeed = decodeBDS53trueAirspeed(mb)
+	}
+
+	// altitude rate
+	switch (int(mb[5]) & 0b00000010) >> 1 {
+	case 0:
+		frame.AltitudeRateValid = false
+	case 1:
+		frame.AltitudeRateValid = true
+		frame.AltitudeRate = decodeBDS53altitudeRate(mb)
+	}
+
+	if !frame.MagneticHeadingValid && !frame.IndicatedAirspeedValid && !frame.MachNumberValid && !frame.TrueAirspeedValid && !frame.AltitudeRateValid {
+		err = errors.New("no parameters available")
+	}
+
+	return
+}
+
+func decodeBDS53magneticHeading(mb []byte) (magneticHeading float64) {
+	// decode magnetic heading (degrees) from BDS 5,3 message
+	// MB bit 2 is the sign, bits 3-12 the value, LSB 90/512 degrees
+
+	sign := (int(mb[0]) & 0b01000000) >> 6
+	value := ((int(mb[0]) & 0b00111111) << 4) + ((int(mb[1]) & 0b11110000) >> 4)
+	if sign != 0 {
+		value -= 1024
+	}
+
+	magneticHeading = float64(value) * (90.0 / 512.0)
+	if magneticHeading < 0 {
+		magneticHeading += 360
+	}
+
+	return
+}
+
+func decodeBDS53indicatedAirspeed(mb []byte) (indicatedAirspeedKnots float64) {
+	// decode indicated airspeed (kt) from BDS 5,3 message
+	// MB bits 14-23, LSB 1 kt
+	return float64(((int(mb[1]) & 0b00000111) << 7) + ((int(mb[2]) & 0b11111110) >> 1))
+}
+
+func decodeBDS53machNumber(mb []byte) (machNumber float64) {
+	// decode mach number from BDS 5,3 message
+	// MB bits 25-33, LSB 0.008
+	return float64((int(mb[3])<<1)+((int(mb[4])&0b10000000)>>7)) * 0.008
+}
+
+func decodeBDS53trueAirspeed(mb []byte) (trueAirspeedKnots float64) {
+	// decode true airspeed (kt) from BDS 5,3 message
+	// MB bits 35-46, LSB 0.5 kt
+	return float64(((int(mb[4])&0b00111111)<<6)+((int(mb[5])&0b11111100)>>2)) * 0.5
+}
+
+func decodeBDS53altitudeRate(mb []byte) (altitudeRate float64) {
+	// decode altitude rate (ft/min) from BDS 5,3 message
+	// MB bit 48 is the sign, bits 49-56 the value, LSB 64 ft/min
+
+	sign := int(mb[5]) & 0b00000001
+	value := int(mb[6])
+	if sign != 0 {
+		value -= 256
+	}
+
+	return float64(value) * 64
+}
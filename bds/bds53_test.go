@@ -0,0 +1,67 @@
+package bds
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBDS53(t *testing.T) {
+
+	// define test data
+	var testTable = []struct {
+		data                   []byte
+		magneticHeadingValid   bool
+		magneticHeading        float64
+		indicatedAirspeedValid bool
+		indicatedAirspeed      float64
+		machNumberValid        bool
+		machNumber             float64
+		trueAirspeedValid      bool
+		trueAirspeed           float64
+		altitudeRateValid      bool
+		altitudeRate           float64
+	}{
+		{
+			data:                   []byte{0xA0, 0x09, 0xF5, 0x32, 0x4E, 0x13, 0xF0},
+			magneticHeadingValid:   true,
+			magneticHeading:        90,
+			indicatedAirspeedValid: true,
+			indicatedAirspeed:      250,
+			machNumberValid:        true,
+			machNumber:             0.8,
+			trueAirspeedValid:      true,
+			trueAirspeed:           450,
+			altitudeRateValid:      true,
+			altitudeRate:           -1024,
+		},
+		{
+			// negative heading, only heading available
+			data:                 []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			magneticHeadingValid: true,
+			magneticHeading:      357.1875,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		frame, err := DecodeBDS53(testData.data)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		assert.NoError(err, testMsg+"decodeBDS53 error")
+		assert.Equal(testData.magneticHeadingValid, frame.MagneticHeadingValid, testMsg+"magneticHeadingValid")
+		assert.InDelta(testData.magneticHeading, frame.MagneticHeading, 0.0001, testMsg+"magneticHeading")
+		assert.Equal(testData.indicatedAirspeedValid, frame.IndicatedAirspeedValid, testMsg+"indicatedAirspeedValid")
+		assert.Equal(testData.indicatedAirspeed, frame.IndicatedAirspeed, testMsg+"indicatedAirspeed")
+		assert.Equal(testData.machNumberValid, frame.MachNumberValid, testMsg+"machNumberValid")
+		assert.InDelta(testData.machNumber, frame.MachNumber, 0.0001, testMsg+"machNumber")
+		assert.Equal(testData.trueAirspeedValid, frame.TrueAirspeedValid, testMsg+"trueAirspeedValid")
+		assert.Equal(testData.trueAirspeed, frame.TrueAirspeed, testMsg+"trueAirspeed")
+		assert.Equal(testData.altitudeRateValid, frame.AltitudeRateValid, testMsg+"altitudeRateValid")
+		assert.Equal(testData.altitudeRate, frame.AltitudeRate, testMsg+"altitudeRate")
+	}
+
+	// no parameters available
+	_, err := DecodeBDS53([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	assert.Error(err)
+}
@@ -1,5 +1,7 @@
 package bds
 
+// BDS5,4 Waypoint 1
+
 import (
 	"errors"
 	"strings"
@@ -7,9 +9,9 @@ import (
 
 type BDS54Frame struct {
 	Waypoint string  // waypoint name
-	ETA      float64 // Estimated Time of Arrival (normal flight)
+	ETA      float64 // Estimated Time of Arrival (normal flight), minutes
 	EFL      int     // Estimated Flight Level (normal flight)
-	TTG      float64 // Time to Go (direct route)
+	TTG      float64 // Time to Go (direct route), minutes
 }
 
 func DecodeBDS54(mb []byte) (frame BDS54Frame, err error) {
@@ -50,6 +52,11 @@ func DecodeBDS54(mb []byte) (frame BDS54Frame, err error) {
 	// check waypoint
 	if !validWaypoint.Match([]byte(frame.Waypoint)) {
 		err = errors.New("waypoint contains invalid characters")
+		return
+	}
+	if frame.Waypoint == "" {
+		err = errors.New("waypoint name empty")
+		return
 	}
 
 	// estimated time of arrival
@@ -59,7 +66,7 @@ func DecodeBDS54(mb []byte) (frame BDS54Frame, err error) {
 		return
 	}
 
-	// estimated flight level (ft)
+	// estimated flight level
 	frame.EFL = ((int(mb[5]) & 0b11111100) >> 2) * 10
 
 	// time to go
@@ -0,0 +1,33 @@
+package bds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBDS545556(t *testing.T) {
+	assert := assert.New(t)
+
+	// waypoint ABCDE, ETA 15 min, FL350, time to go 7.5 min
+	mb := []byte{0x02, 0x10, 0x62, 0x0A, 0x80, 0x8C, 0x80}
+
+	frame, err := DecodeBDS54(mb)
+	assert.NoError(err)
+	assert.Equal("ABCDE", frame.Waypoint)
+	assert.Equal(15.0, frame.ETA)
+	assert.Equal(350, frame.EFL)
+	assert.Equal(7.5, frame.TTG)
+
+	frame55, err := DecodeBDS55(mb)
+	assert.NoError(err)
+	assert.Equal("ABCDE", frame55.Waypoint)
+
+	frame56, err := DecodeBDS56(mb)
+	assert.NoError(err)
+	assert.Equal(15.0, frame56.ETA)
+
+	// status bit set
+	_, err = DecodeBDS54([]byte{0x82, 0x10, 0x62, 0x0A, 0x80, 0x8C, 0x80})
+	assert.Error(err)
+}
@@ -0,0 +1,11 @@
+package bds
+
+// BDS5,5 Waypoint 2
+// Same format as BDS 5,4 (waypoint 1)
+
+type BDS55Frame BDS54Frame
+
+func DecodeBDS55(mb []byte) (frame BDS55Frame, err error) {
+	waypoint, err := DecodeBDS54(mb)
+	return BDS55Frame(waypoint), err
+}
@@ -0,0 +1,11 @@
+package bds
+
+// BDS5,6 Waypoint 3
+// Same format as BDS 5,4 (waypoint 1)
+
+type BDS56Frame BDS54Frame
+
+func DecodeBDS56(mb []byte) (frame BDS56Frame, err error) {
+	waypoint, err := DecodeBDS54(mb)
+	return BDS56Frame(waypoint), err
+}
@@ -96,7 +96,7 @@ func decodeBDS60magneticHeading(mb []byte) (magneticHeading float64, err error)
 		return
 	}
 
-	sign := int(mb[0] & 0b10000000 >> 7)
+	sign := (int(mb[0]) & 0b01000000) >> 6
 	magneticHeading = float64(((int(mb[0])&0b00111111)<<4)+((int(mb[1])&0b11110000)>>4)) * (90.0 / 512.0)
 
 	if sign != 0 {
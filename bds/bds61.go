@@ -1,13 +1,20 @@
 package bds
 
-import "errors"
+import (
+	"beastdecoder/common"
+	"errors"
+)
 
 // BDS code 6,1 — Aircraft status
 
 type BDS61Frame struct {
-	st int // Sub-type code
+	// Sub-type code
+	//  0 = No information
+	//  1 = Emergency/priority status and Mode A code
+	//  2 = 1090ES TCAS RA broadcast
+	St int
 
-	// Emergency / Priority Status
+	// Emergency / Priority Status (subtype 1)
 	//
 	// This subfield shall be used to provide additional information
 	// regarding aircraft status. The Emergency/Priority Status subfield shall be encoded as specified below.
@@ -20,7 +27,54 @@ type BDS61Frame struct {
 	//  EmergencyNoCommunications = No communications
 	//  EmergencyUnlawfulInterference = Unlawful interference
 	//  EmergencyDownedAircraft = Downed aircraft
-	eps EmergencyPriorityStatus
+	EmergencyStatus EmergencyPriorityStatus
+
+	// Mode A code (subtype 1)
+	Squawk int
+
+	// TCAS Resolution Advisory (subtype 2)
+	TcasRA BDS61FrameTcasRA
+}
+
+type BDS61FrameTcasRA struct {
+	// Active resolution advisories (ARA)
+	//
+	// 14 bits, MB bit 9 is the most significant
+	ARA int
+
+	// Resolution advisory complements (RAC)
+	//
+	// 4 bits:
+	//  bit 3 = Do not pass below
+	//  bit 2 = Do not pass above
+	//  bit 1 = Do not turn left
+	//  bit 0 = Do not turn right
+	RAC int
+
+	// RA terminated (RAT)
+	//
+	// Set for 18 ±1 seconds after ACAS stops reporting an RA
+	RATerminated bool
+
+	// Multiple threat encounter (MTE)
+	MultipleThreatEncounter bool
+
+	// Threat type indicator (TTI)
+	//  0 = No identity data in TID
+	//  1 = TID contains a Mode S transponder address
+	//  2 = TID contains altitude, range and bearing data
+	//  3 = Not assigned
+	ThreatType int
+
+	// Threat identity (TTI = 1)
+	ThreatICAO int
+
+	// Threat altitude, range & bearing (TTI = 2)
+	ThreatAltitudeCode int     // Mode C altitude code of the threat
+	ThreatRangeKnown   bool    // false if no range estimate available
+	ThreatRange        float64 // NM, 12.6 means greater than 12.55 NM
+	ThreatBearingKnown bool    // false if no bearing estimate available
+	ThreatBearing      int     // degrees relative to own aircraft heading, start of 6 degree sector
 }
 
 func DecodeBDS61(mb []byte) (frame BDS61Frame, err error) {
@@ -34,17 +88,73 @@ func DecodeBDS61(mb []byte) (frame BDS61Frame, err error) {
 	}
 
 	// SUBTYPE CODE
-	frame.st = (int(mb[0]) & 0b00000111)
+	frame.St = (int(mb[0]) & 0b00000111)
 
-	switch frame.st {
+	switch frame.St {
+
+	// Subtype code 0, No information
+	case 0:
 
 	// Subtype code 1, Emergency/priority status
 	case 1:
-		frame.eps, err = decodeEmergencyState((int(mb[1]) & 0b11100000) >> 5)
+		frame.EmergencyStatus, err = decodeEmergencyState((int(mb[1]) & 0b11100000) >> 5)
 		if err != nil {
 			return
 		}
+
+		// Mode A code
+		frame.Squawk, err = common.SquawkFromIdentityCode(((int(mb[1]) & 0b00011111) << 8) + int(mb[2]))
+		if err != nil {
+			return
+		}
+
+	// Subtype code 2, 1090ES TCAS RA broadcast
+	case 2:
+		frame.TcasRA = decodeBDS61TcasRA(mb)
+
+	default:
+		err = errors.New("subtype code reserved")
 	}
 
 	return
 }
+
+func decodeBDS61TcasRA(mb []byte) (ra BDS61FrameTcasRA) {
+	// decode TCAS RA broadcast, MB bits 9-56 have the same format as BDS 3,0
+
+	ra.ARA = (int(mb[1]) << 6) + ((int(mb[2]) & 0b11111100) >> 2)
+	ra.RAC = ((int(mb[2]) & 0b00000011) << 2) + ((int(mb[3]) & 0b11000000) >> 6)
+	ra.RATerminated = (int(mb[3])&0b00100000)>>5 == 1
+	ra.MultipleThreatEncounter = (int(mb[3])&0b00010000)>>4 == 1
+	ra.ThreatType = (int(mb[3]) & 0b00001100) >> 2
+
+	// Threat identity data, 26 bits
+	tid := ((int(mb[3]) & 0b00000011) << 24) + (int(mb[4]) << 16) + (int(mb[5]) << 8) + int(mb[6])
+
+	switch ra.ThreatType {
+	case 1:
+		ra.ThreatICAO = tid >> 2
+
+	case 2:
+		ra.ThreatAltitudeCode = tid >> 13
+
+		// range, 0 = no estimate, n = (n-1)/10 NM
+		tidr := (tid >> 6) & 0b1111111
+		if tidr > 0 {
+			ra.ThreatRangeKnown = true
+			ra.ThreatRange = float64(tidr-1) / 10
+			if tidr == 127 {
+				ra.ThreatRange = 12.6
+			}
+		}
+
+		// bearing, 0 = no estimate, n = 6 degree sector starting at (n-1)*6
+		tidb := tid & 0b111111
+		if tidb > 0 && tidb <= 60 {
+			ra.ThreatBearingKnown = true
+			ra.ThreatBearing = (tidb - 1) * 6
+		}
+	}
+
+	return ra
+}
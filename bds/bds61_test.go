@@ -0,0 +1,87 @@
+package bds
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBDS61(t *testing.T) {
+
+	// define test data
+	var testTable = []struct {
+		data                    []byte
+		expectedError           bool
+		expectedSt              int
+		expectedEmergencyStatus EmergencyPriorityStatus
+		expectedSquawk          int
+		expectedTcasRA          BDS61FrameTcasRA
+	}{
+		{
+			// 8DA2C1B6E112B600000000760759
+			data:                    []byte{0xE1, 0x12, 0xB6, 0x00, 0x00, 0x00, 0x00},
+			expectedSt:              1,
+			expectedEmergencyStatus: EmergencyNone,
+			expectedSquawk:          6513,
+		},
+		{
+			// general emergency, 7700
+			data:                    []byte{0xE1, 0x2A, 0xAA, 0x00, 0x00, 0x00, 0x00},
+			expectedSt:              1,
+			expectedEmergencyStatus: EmergencyGeneral,
+			expectedSquawk:          7700,
+		},
+		{
+			// TCAS RA, threat identified by Mode S address
+			data:       []byte{0xE2, 0x80, 0x00, 0x06, 0xAF, 0x37, 0xBC},
+			expectedSt: 2,
+			expectedTcasRA: BDS61FrameTcasRA{
+				ARA:        0x2000,
+				ThreatType: 1,
+				ThreatICAO: 0xABCDEF,
+			},
+		},
+		{
+			// TCAS RA terminated, threat identified by altitude/range/bearing
+			data:       []byte{0xE2, 0x00, 0x03, 0x6B, 0x00, 0x0A, 0xC5},
+			expectedSt: 2,
+			expectedTcasRA: BDS61FrameTcasRA{
+				RAC:                0b1101,
+				RATerminated:       true,
+				ThreatType:         2,
+				ThreatAltitudeCode: 0b1100000000000,
+				ThreatRangeKnown:   true,
+				ThreatRange:        4.2,
+				ThreatBearingKnown: true,
+				ThreatBearing:      24,
+			},
+		},
+		{
+			// reserved subtype
+			data:          []byte{0xE3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expectedError: true,
+		},
+		{
+			// reserved emergency state
+			data:          []byte{0xE1, 0xE0, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expectedError: true,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		frame, err := DecodeBDS61(testData.data)
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		if testData.expectedError {
+			assert.Error(err, testMsg+"DecodeBDS61 no error")
+			continue
+		}
+		assert.NoError(err, testMsg+"DecodeBDS61 error")
+		assert.Equal(testData.expectedSt, frame.St, testMsg+"St")
+		assert.Equal(testData.expectedEmergencyStatus, frame.EmergencyStatus, testMsg+"EmergencyStatus")
+		assert.Equal(testData.expectedSquawk, frame.Squawk, testMsg+"Squawk")
+		assert.Equal(testData.expectedTcasRA, frame.TcasRA, testMsg+"TcasRA")
+	}
+
+}
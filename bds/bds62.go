@@ -14,6 +14,16 @@ import (
 
 type BDS62Frame struct {
 
+	// Subtype
+	//
+	//  0 = Version 1 (DO-260A) target state and status
+	//  1 = Version 2 (DO-260B) target state and status
+	//
+	// NACp, NICbaro & SIL are present in both versions. Other fields are version specific.
+	St int
+
+	// ----- Version 1 (subtype 0) -----
+
 	// Vertical Data Available / Source Indicator:
 	//
 	// This subfield shall be used to identify whether aircraft vertical state information is available and present as well as the data source for the vertical data when present in the subsequent subfields.
@@ -23,7 +33,7 @@ type BDS62Frame struct {
 	//  VTSAutopilotSelectedValue = Autopilot control panel selected value, such as Mode Control Panel (MCP) or Flight Control Unit (FCU)
 	//  VTSHoldingAltitude = Holding altitude
 	//  VTSFmsRnavSystem = FMS/RNAV system
-	vdaSi BDS62VerticalTargetState
+	VerticalTargetState BDS62VerticalTargetState
 
 	// Target Altitude Type
 	//
@@ -31,7 +41,7 @@ type BDS62Frame struct {
 	// in the “Target Altitude” subfield is referenced to mean sea level (MSL) or to a flight level (FL).
 	//  TATReferencedToFL = Indicates target altitude referenced to pressure-altitude (FL).
 	//  TATReferencedToMSL = Indicates a target altitude referenced to barometric corrected altitude (MSL).
-	tat BDS62TargetAltitudeType
+	TargetAltitudeType BDS62TargetAltitudeType
 
 	// Target Altitude Compatibility
 	//
@@ -41,7 +51,7 @@ type BDS62Frame struct {
 	//  TACReportingHoldingOnly = Capability for reporting holding altitude only
 	//  TACReportingHoldingAutopilotSelected = Capability for reporting either holding altitude or autopilot control panel selected altitude
 	//  TACReportingHoldingAutopilotSelectedFmsRnavLevelOff = Capability for reporting either holding altitude, autopilot control panel selected altitude, or any FMS/RNAV level-off altitude
-	tac BDS62TargetAltitudeCapability
+	TargetAltitudeCapability BDS62TargetAltitudeCapability
 
 	// Vertical Mode Indicator
 	//
@@ -53,7 +63,7 @@ type BDS62Frame struct {
 	//  VMIUnknown = Unknown mode or information unavailable
 	//  VMIAcquiring = “Acquiring” Mode
 	//  VMICapturingOrMaintaining = “Capturing” or “Maintaining” Mode
-	vmi BDS62VerticalModeIndicator
+	VerticalModeIndicator BDS62VerticalModeIndicator
 
 	// Target Altitude
 	//
@@ -63,7 +73,7 @@ type BDS62Frame struct {
 	// The reported target altitude shall be the operational altitude recognized by the aircraft’s guidance system.
 	//
 	// The target altitude subfield units is in "ft".
-	ta int
+	TargetAltitude int
 
 	// Horizontal Data Available / Source Indicator
 	//
@@ -80,7 +90,7 @@ type BDS62Frame struct {
 	//  HTSAutopilotSelectedValue = Autopilot control panel selected value, such as Mode Control Panel (MCP) or Flight Control Unit (FCU)
 	//  HTSMaintainingHeading = Maintaining current heading or track angle (e.g. autopilot mode select)
 	//  HTSFmsRnavSystem = FMS/RNAV system (indicates track angle specified by leg type)
-	hdaSi BDS62HorizontalTargetState
+	HorizontalTargetState BDS62HorizontalTargetState
 
 	// Target Heading / Track Angle
 	//
@@ -88,7 +98,7 @@ type BDS62Frame struct {
 	// (i.e. target or selected) heading or track.
 	//
 	// The target heading / track angle is in degrees.
-	thta int
+	TargetHeading int
 
 	// Target Heading / Track Indicator
 	//
@@ -96,7 +106,7 @@ type BDS62Frame struct {
 	// track angle is being reported in the target heading/track angle subfield.
 	//  THTITargetHeadingAngle = Indicates the Target Heading Angle is being reported.
 	//  THTITrackAngle = Indicates the Track Angle is being reported.
-	thti BDS62TargetHeadingTrackIndicator
+	TargetHeadingTrackIndicator BDS62TargetHeadingTrackIndicator
 
 	// Horizontal Mode Indicator
 	//
@@ -107,7 +117,7 @@ type BDS62Frame struct {
 	//  HMIUnknown = Unknown mode or information unavailable
 	//  HMIAcquiring = “Acquiring” mode
 	//  HMICapturingOrMaintaining = “Capturing” or “Maintaining” mode
-	hmi BDS62HorizontalModeIndicator
+	HorizontalModeIndicator BDS62HorizontalModeIndicator
 
 	// Navigation Accuracy Category for Position (NACp)
 	//
@@ -128,7 +138,7 @@ type BDS62Frame struct {
 	//  NACpGPS = EPU *and* VEPU < 45m — e.g. GPS (SA off)
 	//  NACpWAAS = EPU *and* VEPU < 15m — e.g. WAAS
 	//  NACpLAAS = EPU *and* VEPU < 4m — e.g.LAAS
-	nacp BDS62NACp
+	NACp BDS62NACp
 
 	// Navigation Integrity Category for Baro (NICbaro)
 	//
@@ -141,7 +151,7 @@ type BDS62Frame struct {
 	//  NICBaroNotCrossChecked = The barometric altitude that is being reported in the Airborne Position Message is based on a Gilham coded input that has not been cross-checked against another source of pressure-altitude.
 	//
 	//  NICBaroCrossChecked = The barometric altitude that is being reported in the Airborne Position Message is either based on a Gilham code input that has been cross-checked against another source of pressure-altitude and verified as being consistent or is based on a non-Gilham coded source.
-	nicbaro BDS62NICBaro
+	NICbaro BDS62NICBaro
 
 	// Surveillance Integrity Level
 	//
@@ -151,7 +161,7 @@ type BDS62Frame struct {
 	// If an update has not been received from an on-board data source for SIL within the past 5 seconds,
 	// then the SIL subfield shall be encoded as a value indicating “Unknown.”
 	//
-	sil int
+	SIL int
 
 	// Capability / Mode Codes
 	//
@@ -161,7 +171,7 @@ type BDS62Frame struct {
 	// within the past 2 seconds, then that data element shall be encoded with a value of TcasAcasOperationalOrUnknown.
 	//  TcasAcasOperationalOrUnknown: TCAS/ACAS operational or unknown
 	//  TcasAcasNotOperational: TCAS/ACAS not operational
-	cmc BDS62CapabilityModeCodes
+	CapabilityModeCodes BDS62CapabilityModeCodes
 
 	// Capability / Mode Codes
 	//
@@ -169,7 +179,7 @@ type BDS62Frame struct {
 	// TCAS/ACAS systems/functions. This subfield shall be encoded as specified as below.
 	//  TcasAcasResolutionAdvisoryInactive: No TCAS/ACAS Resolution Advisory active
 	//  TcasAcasResolutionAdvisoryActive: TCAS/ACAS Resolution Advisory active
-	cmcRa BDS62CapabilityModeCodesRA
+	CapabilityModeCodesRA BDS62CapabilityModeCodesRA
 
 	// Emergency / Priority Status
 	//
@@ -184,19 +194,58 @@ type BDS62Frame struct {
 	//  EmergencyNoCommunications = No communications
 	//  EmergencyUnlawfulInterference = Unlawful interference
 	//  EmergencyDownedAircraft = Downed aircraft
-	eps EmergencyPriorityStatus
+	EmergencyStatus EmergencyPriorityStatus
+
+	// ----- Version 2 (subtype 1) -----
+
+	// SIL Supplement
+	//  0 = SIL probability is per hour
+	//  1 = SIL probability is per sample
+	SILSupplement int
+
+	// Selected Altitude Type
+	//  SelectedAltitudeMcpFcu = Selected altitude from the Mode Control Panel (MCP) / Flight Control Unit (FCU)
+	//  SelectedAltitudeFms = Selected altitude from the Flight Management System (FMS)
+	SelectedAltitudeType BDS62SelectedAltitudeType
+
+	// MCP/FCU or FMS Selected Altitude (ft)
+	SelectedAltitudeValid bool
+	SelectedAltitude      int
+
+	// Barometric Pressure Setting (QNH) (hPa)
+	BaroSettingValid bool
+	BaroSetting      float64
+
+	// Selected Heading (degrees)
+	SelectedHeadingValid bool
+	SelectedHeading      float64
+
+	// MCP/FCU Mode Bits
+	//
+	// Only valid if ModeValid is set
+	ModeValid       bool
+	Autopilot       bool // Autopilot engaged
+	VNAV            bool // Vertical navigation mode
+	AltitudeHold    bool // Altitude hold mode
+	Approach        bool // Approach mode
+	TcasOperational bool // TCAS/ACAS operational
+	LNAV            bool // Lateral navigation mode
 }
 
 func (frame *BDS62Frame) Sprint() string {
 	// Outputs a multi-line string representing the frame. Useful for debugging.
 	var output string
 
+	if frame.St == 1 {
+		return frame.sprintVersion2()
+	}
+
 	// Header
 	output += "BDS 6,2: Target State and Status (29)\n"
 
 	// VERTICAL DATA AVAILABLE/SOURCE INDICATOR
 	output += "  Vertical Data Available/Source Indicator: "
-	switch frame.vdaSi {
+	switch frame.VerticalTargetState {
 	case VTSUnavailable:
 		output += "No valid Vertical Target State data is available\n"
 	case VTSAutopilotSelectedValue:
@@ -209,7 +258,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// VERTICAL MODE INDICATOR
 	output += "  Vertical Mode Indicator: "
-	switch frame.vmi {
+	switch frame.VerticalModeIndicator {
 	case VMIUnknown:
 		output += "Unknown mode or information unavailable\n"
 	case VMIAcquiring:
@@ -220,7 +269,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// TARGET ALTITUDE TYPE
 	output += "  Target Altitude Type: "
-	switch frame.tat {
+	switch frame.TargetAltitudeType {
 	case TATReferencedToFL:
 		output += "Target altitude referenced to pressure-altitude (FL)\n"
 	case TATReferencedToMSL:
@@ -229,7 +278,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// TARGET ALTITUDE CAPABILITY
 	output += "  Target Altitude Capability: "
-	switch frame.tac {
+	switch frame.TargetAltitudeCapability {
 	case TACReportingHoldingOnly:
 		output += "Capability for reporting holding altitude only\n"
 	case TACReportingHoldingAutopilotSelected:
@@ -239,11 +288,11 @@ func (frame *BDS62Frame) Sprint() string {
 	}
 
 	// TARGET ALTITUDE
-	output += fmt.Sprintf("  Target Altitude: %d ft\n", frame.ta)
+	output += fmt.Sprintf("  Target Altitude: %d ft\n", frame.TargetAltitude)
 
 	// HORIZONTAL DATA AVAILABLE/SOURCE INDICATOR
 	output += "  Horizontal Data Available / Source Indicator: "
-	switch frame.hdaSi {
+	switch frame.HorizontalTargetState {
 	case HTSUnavailable:
 		output += "No valid horizontal target state data is available\n"
 	case HTSAutopilotSelectedValue:
@@ -256,7 +305,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// HORIZONTAL MODE INDICATOR
 	output += "  Horizontal Mode Indicator: "
-	switch frame.hmi {
+	switch frame.HorizontalModeIndicator {
 	case HMIUnknown:
 		output += "Unknown mode or information unavailable\n"
 	case HMIAcquiring:
@@ -266,11 +315,11 @@ func (frame *BDS62Frame) Sprint() string {
 	}
 
 	// TARGET HEADING/TRACK ANGLE
-	output += fmt.Sprintf("  Target Heading/Track Angle: %d°\n", frame.thta)
+	output += fmt.Sprintf("  Target Heading/Track Angle: %d°\n", frame.TargetHeading)
 
 	// TARGET HEADING/TRACK INDICATOR
 	output += "  Target Heading/Track Indicator: "
-	switch frame.thti {
+	switch frame.TargetHeadingTrackIndicator {
 	case THTITargetHeadingAngle:
 		output += "Target Heading Angle is being reported\n"
 	case THTITrackAngle:
@@ -279,7 +328,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// NAVIGATION ACCURACY CATEGORY FOR POSITION (NACP)
 	output += "  Navigation Accuracy Category for Position (NACp): "
-	switch frame.nacp {
+	switch frame.NACp {
 	case NACpUnknown:
 		output += "EPU ≥ 18.52 km (10 NM) — Unknown accuracy\n"
 	case NACp10NM:
@@ -308,7 +357,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// NAVIGATION INTEGRITY CATEGORY FOR BARO (NICBARO)
 	output += "  Navigation Integrity Category for Baro (NICbaro): "
-	switch frame.nicbaro {
+	switch frame.NICbaro {
 	case NICBaroNotCrossChecked:
 		output += "Barometric altitude reported has not been cross-checked against another source\n"
 	case NICBaroCrossChecked:
@@ -317,7 +366,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// SURVEILLANCE INTEGRITY LEVEL (SIL)
 	output += "  Surveillance Integrity Level (SIL), probability of exceeding Horizontal Containment Radius (Rc): "
-	switch frame.sil {
+	switch frame.SIL {
 	case 0:
 		output += "unknown\n"
 	case 1:
@@ -328,7 +377,7 @@ func (frame *BDS62Frame) Sprint() string {
 		output += "≤1 × 10^–7 per flight hour or per sample\n"
 	}
 	output += "  Surveillance Integrity Level (SIL), probability of exceeding Vertical Integrity Containment Region (VPL): "
-	switch frame.sil {
+	switch frame.SIL {
 	case 0:
 		output += "unknown\n"
 	case 1:
@@ -341,13 +390,13 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// CAPABILITY/MODE CODES
 	output += "  Capability/Mode Codes: "
-	switch frame.cmc {
+	switch frame.CapabilityModeCodes {
 	case TcasAcasOperationalOrUnknown:
 		output += "TCAS/ACAS operational or unknown; and "
 	case TcasAcasNotOperational:
 		output += "TCAS/ACAS not operational; and "
 	}
-	switch frame.cmcRa {
+	switch frame.CapabilityModeCodesRA {
 	case TcasAcasResolutionAdvisoryInactive:
 		output += "No TCAS/ACAS Resolution Advisory active\n"
 	case TcasAcasResolutionAdvisoryActive:
@@ -356,7 +405,7 @@ func (frame *BDS62Frame) Sprint() string {
 
 	// EMERGENCY/PRIORITY STATUS
 	output += "  Emergency / Priority Status: "
-	switch frame.eps {
+	switch frame.EmergencyStatus {
 	case EmergencyNone:
 		output += "No emergency\n"
 	case EmergencyGeneral:
@@ -378,6 +427,66 @@ func (frame *BDS62Frame) Sprint() string {
 	return output
 }
 
+func (frame *BDS62Frame) sprintVersion2() string {
+	// Outputs a multi-line string representing a version 2 frame.
+	var output string
+
+	// Header
+	output += "BDS 6,2: Target State and Status (29), Version 2\n"
+
+	// SELECTED ALTITUDE
+	output += "  Selected Altitude: "
+	if frame.SelectedAltitudeValid {
+		output += fmt.Sprintf("%d ft", frame.SelectedAltitude)
+		switch frame.SelectedAltitudeType {
+		case SelectedAltitudeMcpFcu:
+			output += " (MCP/FCU)\n"
+		case SelectedAltitudeFms:
+			output += " (FMS)\n"
+		}
+	} else {
+		output += "No data\n"
+	}
+
+	// BAROMETRIC PRESSURE SETTING
+	output += "  Barometric Pressure Setting: "
+	if frame.BaroSettingValid {
+		output += fmt.Sprintf("%.1f hPa\n", frame.BaroSetting)
+	} else {
+		output += "No data\n"
+	}
+
+	// SELECTED HEADING
+	output += "  Selected Heading: "
+	if frame.SelectedHeadingValid {
+		output += fmt.Sprintf("%.1f°\n", frame.SelectedHeading)
+	} else {
+		output += "No data\n"
+	}
+
+	// NACP, NICBARO, SIL
+	output += fmt.Sprintf("  Navigation Accuracy Category for Position (NACp): %d\n", frame.NACp)
+	output += fmt.Sprintf("  Navigation Integrity Category for Baro (NICbaro): %d\n", frame.NICbaro)
+	output += fmt.Sprintf("  Source Integrity Level (SIL): %d", frame.SIL)
+	if frame.SILSupplement == 1 {
+		output += " per sample\n"
+	} else {
+		output += " per hour\n"
+	}
+
+	// MCP/FCU MODE BITS
+	output += "  MCP/FCU Modes: "
+	if frame.ModeValid {
+		output += fmt.Sprintf("autopilot=%t vnav=%t alt_hold=%t approach=%t lnav=%t tcas=%t\n", frame.Autopilot, frame.VNAV, frame.AltitudeHold, frame.Approach, frame.LNAV, frame.TcasOperational)
+	} else {
+		output += "No data\n"
+	}
+
+	output += "\n"
+
+	return output
+}
+
 type BDS62VerticalTargetState uint8
 
 const VTSUnavailable = BDS62VerticalTargetState(0)            // No valid Vertical Target State data is available
@@ -385,6 +494,11 @@ const VTSAutopilotSelectedValue = BDS62VerticalTargetState(1) // Autopilot contr
 const VTSHoldingAltitude = BDS62VerticalTargetState(2)        // Holding altitude
 const VTSFmsRnavSystem = BDS62VerticalTargetState(3)          // FMS/RNAV system
 
+type BDS62SelectedAltitudeType uint8
+
+const SelectedAltitudeMcpFcu = BDS62SelectedAltitudeType(0) // Selected altitude from the Mode Control Panel (MCP) / Flight Control Unit (FCU)
+const SelectedAltitudeFms = BDS62SelectedAltitudeType(1)    // Selected altitude from the Flight Management System (FMS)
+
 type BDS62TargetAltitudeType uint8
 
 const TATReferencedToFL = BDS62TargetAltitudeType(0)  // Target altitude referenced to pressure-altitude (FL)
@@ -461,8 +575,14 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	}
 
 	// check subtype code
-	if (int(mb[0])&0b00000110)>>1 != 0 {
-		err = errors.New("subtype code not 0")
+	frame.St = (int(mb[0]) & 0b00000110) >> 1
+	switch frame.St {
+	case 0:
+	case 1:
+		err = decodeBDS62Version2(mb, &frame)
+		return
+	default:
+		err = errors.New("subtype code not 0 or 1")
 		return
 	}
 
@@ -473,34 +593,34 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	}
 
 	// VERTICAL DATA AVAILABLE/SOURCE INDICATOR
-	switch ((int(mb[0]) & 0b00000001) << 1) + ((int(mb[0]) & 0b10000000) >> 7) {
+	switch ((int(mb[0]) & 0b00000001) << 1) + ((int(mb[1]) & 0b10000000) >> 7) {
 	case 0:
-		frame.vdaSi = VTSUnavailable
+		frame.VerticalTargetState = VTSUnavailable
 	case 1:
-		frame.vdaSi = VTSAutopilotSelectedValue
+		frame.VerticalTargetState = VTSAutopilotSelectedValue
 	case 2:
-		frame.vdaSi = VTSHoldingAltitude
+		frame.VerticalTargetState = VTSHoldingAltitude
 	case 3:
-		frame.vdaSi = VTSFmsRnavSystem
+		frame.VerticalTargetState = VTSFmsRnavSystem
 	}
 
 	// TARGET ALTITUDE TYPE
 
 	switch (int(mb[1]) & 0b01000000) >> 6 {
 	case 0:
-		frame.tat = TATReferencedToFL
+		frame.TargetAltitudeType = TATReferencedToFL
 	case 1:
-		frame.tat = TATReferencedToMSL
+		frame.TargetAltitudeType = TATReferencedToMSL
 	}
 
 	// TARGET ALTITUDE CAPABILITY
 	switch (int(mb[1]) & 0b00011000) >> 3 {
 	case 0:
-		frame.tac = TACReportingHoldingOnly
+		frame.TargetAltitudeCapability = TACReportingHoldingOnly
 	case 1:
-		frame.tac = TACReportingHoldingAutopilotSelected
+		frame.TargetAltitudeCapability = TACReportingHoldingAutopilotSelected
 	case 2:
-		frame.tac = TACReportingHoldingAutopilotSelectedFmsRnavLevelOff
+		frame.TargetAltitudeCapability = TACReportingHoldingAutopilotSelectedFmsRnavLevelOff
 	case 3:
 		err = errors.New("target altitude capability set to reserved value")
 		return
@@ -509,33 +629,33 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	// VERTICAL MODE INDICATOR
 	switch (int(mb[1]) & 0b00000110) >> 1 {
 	case 0:
-		frame.vmi = VMIUnknown
+		frame.VerticalModeIndicator = VMIUnknown
 	case 1:
-		frame.vmi = VMIAcquiring
+		frame.VerticalModeIndicator = VMIAcquiring
 	case 2:
-		frame.vmi = VMICapturingOrMaintaining
+		frame.VerticalModeIndicator = VMICapturingOrMaintaining
 	case 3:
 		err = errors.New("vertical mode indicator set to reserved value")
 	}
 
 	// TARGET ALTITUDE
-	frame.ta = -1000 + (100 * (((int(mb[1]) & 0b00000001) << 9) + (int(mb[2]) << 1) + ((int(mb[3]) & 0b10000000) >> 7)))
+	frame.TargetAltitude = -1000 + (100 * (((int(mb[1]) & 0b00000001) << 9) + (int(mb[2]) << 1) + ((int(mb[3]) & 0b10000000) >> 7)))
 
 	// HORIZONTAL DATA AVAILABLE/SOURCE INDICATOR
 	switch (int(mb[3]) & 0b01100000) >> 5 {
 	case 0:
-		frame.hdaSi = HTSUnavailable
+		frame.HorizontalTargetState = HTSUnavailable
 	case 1:
-		frame.hdaSi = HTSAutopilotSelectedValue
+		frame.HorizontalTargetState = HTSAutopilotSelectedValue
 	case 2:
-		frame.hdaSi = HTSMaintainingHeading
+		frame.HorizontalTargetState = HTSMaintainingHeading
 	case 3:
-		frame.hdaSi = HTSFmsRnavSystem
+		frame.HorizontalTargetState = HTSFmsRnavSystem
 	}
 
 	// TARGET HEADING/TRACK ANGLE
-	frame.thta = ((int(mb[3]) & 0b00011111) << 4) + ((int(mb[4]) & 0b11110000) >> 4)
-	if frame.thta >= 360 {
+	frame.TargetHeading = ((int(mb[3]) & 0b00011111) << 4) + ((int(mb[4]) & 0b11110000) >> 4)
+	if frame.TargetHeading >= 360 {
 		err = errors.New("target heading / track angle invalid")
 		return
 	}
@@ -543,19 +663,19 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	// TARGET HEADING/TRACK INDICATOR
 	switch (int(mb[4]) & 0b00001000) >> 3 {
 	case 0:
-		frame.thti = THTITargetHeadingAngle
+		frame.TargetHeadingTrackIndicator = THTITargetHeadingAngle
 	case 1:
-		frame.thti = THTITrackAngle
+		frame.TargetHeadingTrackIndicator = THTITrackAngle
 	}
 
 	// HORIZONTAL MODE INDICATOR
 	switch (int(mb[4]) & 0b00000110) >> 1 {
 	case 0:
-		frame.hmi = HMIUnknown
+		frame.HorizontalModeIndicator = HMIUnknown
 	case 1:
-		frame.hmi = HMIAcquiring
+		frame.HorizontalModeIndicator = HMIAcquiring
 	case 2:
-		frame.hmi = HMICapturingOrMaintaining
+		frame.HorizontalModeIndicator = HMICapturingOrMaintaining
 	case 3:
 		err = errors.New("horizontal mode indicator set to reserved value")
 		return
@@ -564,29 +684,29 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	// NAVIGATION ACCURACY CATEGORY FOR POSITION (NACP)
 	switch ((int(mb[4]) & 0b00000001) << 3) + ((int(mb[5]) & 0b11100000) >> 5) {
 	case 0:
-		frame.nacp = NACpUnknown
+		frame.NACp = NACpUnknown
 	case 1:
-		frame.nacp = NACp10NM
+		frame.NACp = NACp10NM
 	case 2:
-		frame.nacp = NACp4NM
+		frame.NACp = NACp4NM
 	case 3:
-		frame.nacp = NACp2NM
+		frame.NACp = NACp2NM
 	case 4:
-		frame.nacp = NACp1NM
+		frame.NACp = NACp1NM
 	case 5:
-		frame.nacp = NACp0NM5
+		frame.NACp = NACp0NM5
 	case 6:
-		frame.nacp = NACp0NM3
+		frame.NACp = NACp0NM3
 	case 7:
-		frame.nacp = NACp0NM1
+		frame.NACp = NACp0NM1
 	case 8:
-		frame.nacp = NACp0NM05
+		frame.NACp = NACp0NM05
 	case 9:
-		frame.nacp = NACpGPS
+		frame.NACp = NACpGPS
 	case 10:
-		frame.nacp = NACpWAAS
+		frame.NACp = NACpWAAS
 	case 11:
-		frame.nacp = NACpLAAS
+		frame.NACp = NACpLAAS
 	default:
 		err = errors.New("NACp set to reserved value")
 		return
@@ -595,30 +715,96 @@ func DecodeBDS62(mb []byte) (frame BDS62Frame, err error) {
 	// NAVIGATION INTEGRITY CATEGORY FOR BARO (NICBARO)
 	switch (int(mb[5]) & 0b00010000) >> 4 {
 	case 0:
-		frame.nicbaro = NICBaroNotCrossChecked
+		frame.NICbaro = NICBaroNotCrossChecked
 	case 1:
-		frame.nicbaro = NICBaroCrossChecked
+		frame.NICbaro = NICBaroCrossChecked
 	}
 
 	// SURVEILLANCE INTEGRITY LEVEL (SIL)
-	frame.sil = (int(mb[5]) & 0b00001100) >> 2
+	frame.SIL = (int(mb[5]) & 0b00001100) >> 2
 
 	// CAPABILITY/MODE CODES
 	switch (int(mb[6]) & 0b00010000) >> 4 {
 	case 0:
-		frame.cmc = TcasAcasOperationalOrUnknown
+		frame.CapabilityModeCodes = TcasAcasOperationalOrUnknown
 	case 1:
-		frame.cmc = TcasAcasNotOperational
+		frame.CapabilityModeCodes = TcasAcasNotOperational
 	}
 	switch (int(mb[6]) & 0b00001000) >> 3 {
 	case 0:
-		frame.cmcRa = TcasAcasResolutionAdvisoryInactive
+		frame.CapabilityModeCodesRA = TcasAcasResolutionAdvisoryInactive
 	case 1:
-		frame.cmcRa = TcasAcasResolutionAdvisoryActive
+		frame.CapabilityModeCodesRA = TcasAcasResolutionAdvisoryActive
 	}
 
 	// EMERGENCY/PRIORITY STATUS
-	frame.eps, err = decodeEmergencyState(int(mb[6]) & 0b00000111)
+	frame.EmergencyStatus, err = decodeEmergencyState(int(mb[6]) & 0b00000111)
+
+	return
+}
+
+func decodeBDS62Version2(mb []byte, frame *BDS62Frame) (err error) {
+	// Decode version 2 (subtype 1) target state and status
+
+	// check reserved bits
+	if (int(mb[6]) & 0b00000011) != 0 {
+		err = errors.New("reserved bits not 0")
+		return
+	}
+
+	// SIL SUPPLEMENT
+	frame.SILSupplement = int(mb[0]) & 0b00000001
+
+	// SELECTED ALTITUDE TYPE
+	frame.SelectedAltitudeType = BDS62SelectedAltitudeType((int(mb[1]) & 0b10000000) >> 7)
+
+	// MCP/FCU OR FMS SELECTED ALTITUDE
+	// 0 = no data, otherwise (n-1) * 32 ft
+	alt := ((int(mb[1]) & 0b01111111) << 4) + ((int(mb[2]) & 0b11110000) >> 4)
+	if alt > 0 {
+		frame.SelectedAltitudeValid = true
+		frame.SelectedAltitude = (alt - 1) * 32
+	}
+
+	// BAROMETRIC PRESSURE SETTING (MINUS 800 MILLIBARS)
+	// 0 = no data, otherwise 800 + (n-1) * 0.8 mb
+	baro := ((int(mb[2]) & 0b00001111) << 5) + ((int(mb[3]) & 0b11111000) >> 3)
+	if baro > 0 {
+		frame.BaroSettingValid = true
+		frame.BaroSetting = 800 + float64(baro-1)*0.8
+	}
+
+	// SELECTED HEADING
+	frame.SelectedHeadingValid = (int(mb[3])&0b00000100)>>2 == 1
+	if frame.SelectedHeadingValid {
+		frame.SelectedHeading = float64(((int(mb[3])&0b00000011)<<7)+((int(mb[4])&0b11111110)>>1)) * 180 / 256
+	}
+
+	// NAVIGATION ACCURACY CATEGORY FOR POSITION (NACP)
+	frame.NACp = BDS62NACp(((int(mb[4]) & 0b00000001) << 3) + ((int(mb[5]) & 0b11100000) >> 5))
+	if frame.NACp > NACpLAAS {
+		err = errors.New("NACp set to reserved value")
+		return
+	}
+
+	// NAVIGATION INTEGRITY CATEGORY FOR BARO (NICBARO)
+	frame.NICbaro = BDS62NICBaro((int(mb[5]) & 0b00010000) >> 4)
+
+	// SOURCE INTEGRITY LEVEL (SIL)
+	frame.SIL = (int(mb[5]) & 0b00001100) >> 2
+
+	// MCP/FCU MODE BITS
+	frame.ModeValid = (int(mb[5])&0b00000010)>>1 == 1
+	if frame.ModeValid {
+		frame.Autopilot = int(mb[5])&0b00000001 == 1
+		frame.VNAV = (int(mb[6])&0b10000000)>>7 == 1
+		frame.AltitudeHold = (int(mb[6])&0b01000000)>>6 == 1
+		frame.Approach = (int(mb[6])&0b00010000)>>4 == 1
+		frame.LNAV = (int(mb[6])&0b00000100)>>2 == 1
+	}
+
+	// TCAS/ACAS OPERATIONAL
+	frame.TcasOperational = (int(mb[6])&0b00001000)>>3 == 1
 
 	return
 }
@@ -45,3 +45,53 @@ func TestDecodeBDS62(t *testing.T) {
 	}
 
 }
+
+func TestDecodeBDS62Version1(t *testing.T) {
+	assert := assert.New(t)
+
+	frame, err := DecodeBDS62([]byte{0xe9, 0x08, 0x32, 0x00, 0x09, 0x38, 0x10})
+	assert.NoError(err)
+	assert.Equal(0, frame.St)
+	assert.Equal(VTSHoldingAltitude, frame.VerticalTargetState)
+	assert.Equal(9000, frame.TargetAltitude)
+	assert.Equal(NACpGPS, frame.NACp)
+	assert.Equal(NICBaroCrossChecked, frame.NICbaro)
+	assert.Equal(2, frame.SIL)
+	assert.Equal(EmergencyNone, frame.EmergencyStatus)
+}
+
+func TestDecodeBDS62Version2(t *testing.T) {
+	assert := assert.New(t)
+
+	// 8DA05629EA21485CBF3F8CADAEEB
+	frame, err := DecodeBDS62([]byte{0xea, 0x21, 0x48, 0x5c, 0xbf, 0x3f, 0x8c})
+	assert.NoError(err)
+	assert.Equal(1, frame.St)
+	assert.Equal(0, frame.SILSupplement)
+
+	assert.True(frame.SelectedAltitudeValid)
+	assert.Equal(SelectedAltitudeMcpFcu, frame.SelectedAltitudeType)
+	assert.Equal(16992, frame.SelectedAltitude)
+
+	assert.True(frame.BaroSettingValid)
+	assert.InDelta(1012.8, frame.BaroSetting, 0.001)
+
+	assert.True(frame.SelectedHeadingValid)
+	assert.InDelta(66.796875, frame.SelectedHeading, 0.001)
+
+	assert.Equal(NACpGPS, frame.NACp)
+	assert.Equal(NICBaroCrossChecked, frame.NICbaro)
+	assert.Equal(3, frame.SIL)
+
+	assert.True(frame.ModeValid)
+	assert.True(frame.Autopilot)
+	assert.True(frame.VNAV)
+	assert.False(frame.AltitudeHold)
+	assert.False(frame.Approach)
+	assert.True(frame.LNAV)
+	assert.True(frame.TcasOperational)
+
+	// reserved subtype
+	_, err = DecodeBDS62([]byte{0xec, 0x21, 0x48, 0x5c, 0xbf, 0x3f, 0x8c})
+	assert.Error(err)
+}
@@ -3,639 +3,592 @@ package bds
 import (
 	"beastdecoder/df"
 	"errors"
-
-	"github.com/rs/zerolog/log"
+	"fmt"
 )
 
 const DF17 = df.DF17
 const DF18 = df.DF18
 
-func isBDS10(mb []byte) bool {
-	// returns true if message is likely to be BDS 1,0
+func isBDS10(mb []byte) error {
+	// returns nil if message is likely to be BDS 1,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// MB bits 1-8: equal to 0001 0000
 	if int(mb[0]) != 0b00010000 {
-		log.Debug().Str("reason", "bds code mismatch").Msg("not BDS10")
-		return false
+		return errors.New("bds code mismatch")
 	}
 
 	// MB bits 10-14: equal to all zeroes (reserved bits)
 	if int(mb[1])&0b01111100 != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS10")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 
 	// Overlay capability conflict
 	if (int(mb[1])&0b00000010)>>1 == 1 {
 		if (int(mb[2])&0b11111110)>>1 < 5 {
-			log.Debug().Str("reason", "overlay capability conflict").Msg("not BDS10")
-			return false
+			return errors.New("overlay capability conflict")
 		}
 	}
 
 	if (int(mb[1])&0b00000010)>>1 == 0 {
 		if (int(mb[2])&0b11111110)>>1 > 4 {
-			log.Debug().Str("reason", "overlay capability conflict").Msg("not BDS10")
-			return false
+			return errors.New("overlay capability conflict")
 		}
 	}
 
-	return true
+	return nil
 }
 
-func isBDS17(mb []byte) bool {
-	// returns true if message is likely to be BDS 1,7
+func isBDS17(mb []byte) error {
+	// returns nil if message is likely to be BDS 1,7
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// MB bit 7 should be equal to 1
 	if int(mb[0])&0b10 != 0b10 {
-		log.Debug().Str("reason", "bds code mismatch").Msg("not BDS17")
-		return false
+		return errors.New("bds code mismatch")
 	}
 
 	// MB bits 29-56 all zeroes (reserved bits)
 	if int(mb[3])&0b00001111 != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS17")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 	if int(mb[4]) != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS17")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 	if int(mb[5]) != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS17")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 	if int(mb[6]) != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS17")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 
-	return true
+	return nil
 }
 
-func isBDS20(mb []byte) bool {
-	// returns true if message is likely to be BDS 2,0
+func isBDS20(mb []byte) error {
+	// returns nil if message is likely to be BDS 2,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// MB bits 1-8 equal to 0010 0000
 	if int(mb[0]) != 0b00100000 {
-		log.Debug().Str("reason", "bds code mismatch").Msg("not BDS20")
-		return false
+		return errors.New("bds code mismatch")
 	}
 
 	// Callsign	only contains 0–9, A–Z
 	frame, err := DecodeBDS20(mb)
 	if err != nil {
-		log.Debug().AnErr("reason", err).Str("callsign", frame.Callsign).Msg("not BDS20")
-		return false
+		return fmt.Errorf("%w (callsign: %v)", err, frame.Callsign)
 	}
 
-	return true
+	return nil
 }
 
-func isBDS30(mb []byte) bool {
-	// returns true if message is likely to be BDS 3,0
+func isBDS30(mb []byte) error {
+	// returns nil if message is likely to be BDS 3,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// MB bits 1-8 equal to 0011 0000
 	if int(mb[0]) != 0b00110000 {
-		log.Debug().Str("reason", "bds code mismatch").Msg("not BDS30")
-		return false
+		return errors.New("bds code mismatch")
 	}
 
 	// MB bits 29-39 not equal to 11
 	if (int(mb[3])&0b00001100)>>2 == 0b11 {
-		log.Debug().Str("reason", "threat type mismatch").Msg("not BDS30")
-		return false
+		return errors.New("threat type mismatch")
 	}
 
 	// MB bits 16-22 less than decimal 48
 	if (((int(mb[1]) & 0b00000001) << 6) + ((int(mb[2]) & 0b11111100) >> 2)) >= 48 {
-		log.Debug().Str("reason", "acas mismatch").Msg("not BDS30")
-		return false
+		return errors.New("acas mismatch")
 	}
 
-	return true
+	return nil
 }
 
-func isBDS40(mb []byte) bool {
-	// returns true if message is likely to be BDS 4,0
+func isBDS40(mb []byte) error {
+	// returns nil if message is likely to be BDS 4,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// if MB bit 1 == 0, then bits 2-13 must be zero
 	if int(mb[0])&0b10000000 == 0 {
 		if int(mb[0])&0b01111111 != 0 {
-			log.Debug().Str("reason", "MCP/FCU selected altitude not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("MCP/FCU selected altitude not status consistent")
 		}
 		if int(mb[1])&0b11111000 != 0 {
-			log.Debug().Str("reason", "MCP/FCU selected altitude not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("MCP/FCU selected altitude not status consistent")
 		}
 	}
 
 	// if MB bit 14 == 0, then bits 15-26 must be zero
 	if int(mb[1])&0b00000100 == 0 {
 		if int(mb[1])&0b00000011 != 0 {
-			log.Debug().Str("reason", "FMS selected altitude not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("FMS selected altitude not status consistent")
 		}
 		if int(mb[2])&0b11111111 != 0 {
-			log.Debug().Str("reason", "FMS selected altitude not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("FMS selected altitude not status consistent")
 		}
 		if int(mb[3])&0b11000000 != 0 {
-			log.Debug().Str("reason", "FMS selected altitude not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("FMS selected altitude not status consistent")
 		}
 	}
 
 	// if MB bit 27 == 0, then bits 28-39 must be zero
 	if int(mb[3])&0b00100000 == 0 {
 		if int(mb[3])&0b00011111 != 0 {
-			log.Debug().Str("reason", "Barometric pressure not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("barometric pressure not status consistent")
 		}
 		if int(mb[4])&0b11111110 != 0 {
-			log.Debug().Str("reason", "Barometric pressure not status consistent").Msg("not BDS40")
-			return false
+			return errors.New("barometric pressure not status consistent")
 		}
 	}
 
 	// MB bits 40-47 should all be zero (reserved bits)
 	if int(mb[4])&0b00000001 != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS40")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 	if int(mb[5])&0b11111110 != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS40")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 
 	// MB bits 52-53 should all be zero (reserved bits)
 	if int(mb[6])&0b00011000 != 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS40")
-		return false
+		return errors.New("reserved bits not zero")
 	}
 
-	return true
+	return nil
 }
 
-func isBDS44(mb []byte) bool {
-	// returns true if message is likely to be BDS 4,4
+func isBDS44(mb []byte) error {
+	// returns nil if message is likely to be BDS 4,4
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// figure of merit must be less than 5
 	fom := decodeBDS44figureOfMerit(mb)
 	if fom >= 5 {
-		log.Debug().Str("reason", "reserved bits in fom/source not zero").Msg("not BDS44")
-		return false
+		return errors.New("reserved bits in fom/source not zero")
 	}
 
 	// wind speed / direction bits must be status consistent, with speed less than 250 kt
 	if int(mb[0])&0b00001000 == 0 {
 		if int(mb[0])&0b00000111 != 0 {
-			log.Debug().Str("reason", "wind speed not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("wind speed not status consistent")
 		}
 		if int(mb[1]) != 0 {
-			log.Debug().Str("reason", "wind speed not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("wind speed not status consistent")
 		}
 		if int(mb[2])&0b11111110 != 0 {
-			log.Debug().Str("reason", "wind speed not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("wind speed not status consistent")
 		}
 	} else {
 		ws, _, err := decodeBDS44windSpeedDirection(mb)
 		if err != nil {
-			log.Debug().AnErr("err", err).Str("reason", "wind speed").Msg("not BDS44")
-			return false
+			return fmt.Errorf("wind speed: %w", err)
 		}
 		if ws > 250 {
-			log.Debug().Str("reason", "wind speed out of range").Msg("not BDS44")
-			return false
+			return errors.New("wind speed out of range")
 		}
 	}
 
 	// static air temperature must be between -80 and 60 deg C
 	sat, err := decodeBDS44staticAirTemperature(mb)
 	if err != nil {
-		return false
+		return err
 	}
 	if sat < -80 || sat > 60 {
-		return false
+		return fmt.Errorf("static air temperature out of range (sat: %v)", sat)
 	}
 
 	// average static pressure bits must be status consistent
 	if int(mb[4])&0b00100000 == 0 {
 		if int(mb[4])&0b00011111 != 0 {
-			log.Debug().Str("reason", "static pressure not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("static pressure not status consistent")
 		}
 		if int(mb[5])&0b11111100 != 0 {
-			log.Debug().Str("reason", "static pressure not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("static pressure not status consistent")
 		}
 	}
 
 	// turbulence bits must be status consistent
 	if int(mb[5])&0b00000010 == 0 {
 		if int(mb[5])&0b00000001 != 0 {
-			log.Debug().Str("reason", "turbulence not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("turbulence not status consistent")
 		}
 		if int(mb[6])&0b10000000 != 0 {
-			log.Debug().Str("reason", "turbulence not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("turbulence not status consistent")
 		}
 	}
 
 	// humidity bits must be status consistent
 	if int(mb[6])&0b01000000 == 0 {
 		if int(mb[6])&0b00111111 != 0 {
-			log.Debug().Str("reason", "humidity not status consistent").Msg("not BDS44")
-			return false
+			return errors.New("humidity not status consistent")
 		}
 	}
 
-	return true
+	return nil
 }
 
-func isBDS45(mb []byte) bool {
+func isBDS45(mb []byte) error {
+	// returns nil if message is likely to be BDS 4,5
+	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// turbulence bits must be status consistent
 	if int(mb[0])&0b10000000 == 0 {
 		if int(mb[0])&0b01100000 != 0 {
-			log.Debug().Str("reason", "turbulence not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("turbulence not status consistent")
 		}
 	}
 
 	// wind shear bits must be status consistent
 	if int(mb[0])&0b00010000 == 0 {
 		if int(mb[0])&0b00001100 != 0 {
-			log.Debug().Str("reason", "wind shear not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("wind shear not status consistent")
 		}
 	}
 
 	// microburst bits must be status consistent
 	if int(mb[0])&0b00000010 == 0 {
 		if int(mb[0])&0b00000001 != 0 {
-			log.Debug().Str("reason", "microburst not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("microburst not status consistent")
 		}
 		if int(mb[1])&0b10000000 != 0 {
-			log.Debug().Str("reason", "microburst not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("microburst not status consistent")
 		}
 	}
 
 	// icing bits must be status consistent
 	if int(mb[1])&0b01000000 == 0 {
 		if int(mb[1])&0b00110000 != 0 {
-			log.Debug().Str("reason", "icing not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("icing not status consistent")
 		}
 	}
 
 	// wake vortex bits must be status consistent
 	if int(mb[1])&0b00001000 == 0 {
 		if int(mb[1])&0b00000110 != 0 {
-			log.Debug().Str("reason", "wake vortex not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("wake vortex not status consistent")
 		}
 	}
 
 	// static air temp bits must be status consistent
 	if int(mb[1])&0b00000001 == 0 {
 		if int(mb[2])&0b01111111 != 0 {
-			log.Debug().Str("reason", "static air temp not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("static air temp not status consistent")
 		}
 		if int(mb[3])&0b11000000 != 0 {
-			log.Debug().Str("reason", "static air temp not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("static air temp not status consistent")
 		}
 	}
 
 	// average static pressure bits must be status consistent
 	if int(mb[3])&0b00100000 == 0 {
 		if int(mb[3])&0b00011111 != 0 {
-			log.Debug().Str("reason", "average static pressure not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("average static pressure not status consistent")
 		}
 		if int(mb[4])&0b11111100 != 0 {
-			log.Debug().Str("reason", "average static pressure not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("average static pressure not status consistent")
 		}
 	}
 
 	// radio height bits must be status consistent
 	if int(mb[4])&0b00000010 == 0 {
 		if int(mb[4])&0b00000001 != 0 {
-			log.Debug().Str("reason", "radio height not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("radio height not status consistent")
 		}
 		if int(mb[5]) != 0 {
-			log.Debug().Str("reason", "radio height not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("radio height not status consistent")
 		}
 		if int(mb[6])&0b11100000 != 0 {
-			log.Debug().Str("reason", "radio height not status consistent").Msg("not BDS45")
-			return false
+			return errors.New("radio height not status consistent")
 		}
 	}
 
 	// reserved bits must be zero
-	if int(mb[6])&0b00011111 == 0 {
-		log.Debug().Str("reason", "reserved bits not zero").Msg("not BDS45")
-		return false
+	if int(mb[6])&0b00011111 != 0 {
+		return errors.New("reserved bits not zero")
 	}
 
-	// TODO: if temp > 60 or temp < -80: return false
+	// static air temperature must be between -80 and 60 deg C
+	if int(mb[1])&0b00000001 != 0 {
+		sat, err := decodeBDS45staticAirTemperature(mb)
+		if err != nil {
+			return err
+		}
+		if sat < -80 || sat > 60 {
+			return fmt.Errorf("static air temperature out of range (sat: %v)", sat)
+		}
+	}
 
-	return true
+	return nil
 }
 
-func isBDS50(mb []byte) bool {
-	// returns true if message is likely to be BDS 5,0
+func isBDS50(mb []byte) error {
+	// returns nil if message is likely to be BDS 5,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// roll angle must have status consistent bits, and be between -50 and 50 degrees
 	if int(mb[0])&0b10000000 == 0 {
 		if int(mb[0])&0b00111111 != 0 {
-			log.Debug().Str("reason", "roll angle bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("roll angle bits not status consistent")
 		}
 		if int(mb[1])&0b01100000 != 0 {
-			log.Debug().Str("reason", "roll angle bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("roll angle bits not status consistent")
 		}
 	} else {
 		rollAngle, err := decodeBDS50roll(mb)
 		if err != nil {
-			log.Debug().AnErr("err", err).Msg("not BDS50")
-			return false
+			return err
 		}
 		if rollAngle < -50 || rollAngle > 50 {
-			log.Debug().Str("reason", "roll angle out of range").Float64("rollAngle", rollAngle).Msg("not BDS50")
-			return false
+			return fmt.Errorf("roll angle out of range (rollAngle: %v)", rollAngle)
 		}
 	}
 
 	// if MB bit 12 == 0, then bits 13-23 must be 0
 	if int(mb[1])&0b00010000 == 0 {
 		if int(mb[1])&0b00001111 != 0 {
-			log.Debug().Str("reason", "true track angle bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("true track angle bits not status consistent")
 		}
 		if int(mb[2])&0b11111110 != 0 {
-			log.Debug().Str("reason", "true track angle bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("true track angle bits not status consistent")
 		}
 	}
 
 	// check ground speed is valid and between 0 and 600 knots
 	if int(mb[2])&0b00000001 == 0 {
 		if int(mb[3]) != 0 {
-			log.Debug().Str("reason", "ground speed bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("ground speed bits not status consistent")
 		}
 		if int(mb[4])&0b11000000 != 0 {
-			log.Debug().Str("reason", "ground speed bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("ground speed bits not status consistent")
 		}
 	} else {
 		gs, err := decodeBDS50groundSpeed(mb)
 		if err != nil {
-			log.Debug().AnErr("err", err).Msg("not BDS50")
-			return false
+			return err
 		}
 		if gs < 0 || gs > 500 {
-			log.Debug().Str("reason", "ground speed out of range").Float64("gs", gs).Msg("not BDS50")
-			return false
+			return fmt.Errorf("ground speed out of range (gs: %v)", gs)
 		}
 	}
 
 	// if MB bit 35 == 0, then bits 36-45 must be 0
 	if int(mb[4])&0b00100000 == 0 {
 		if int(mb[4])&0b00011111 != 0 {
-			log.Debug().Str("reason", "track angle rate bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("track angle rate bits not status consistent")
 		}
 		if int(mb[5])&0b11111000 != 0 {
-			log.Debug().Str("reason", "track angle rate bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("track angle rate bits not status consistent")
 		}
 	}
 
 	// check true airspeed status consistent & is valid and between 0 and 500 knots
 	if (int(mb[5])&0b00000100)>>2 == 0 {
 		if int(mb[5])&0b00000011 != 0 {
-			log.Debug().Str("reason", "true airspeed bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("true airspeed bits not status consistent")
 		}
 		if int(mb[6]) != 0 {
-			log.Debug().Str("reason", "true airspeed bits not status consistent").Msg("not BDS50")
-			return false
+			return errors.New("true airspeed bits not status consistent")
 		}
 	} else {
 		tas, err := decodeBDS50trueAirspeed(mb)
 		if err != nil {
-			log.Debug().AnErr("err", err).Msg("not BDS50")
-			return false
+			return err
 		}
 		if tas < 0 || tas > 500 {
-			log.Debug().Str("reason", "true airspeed bits out of range").Float64("tas", tas).Msg("not BDS50")
-			return false
+			return fmt.Errorf("true airspeed bits out of range (tas: %v)", tas)
 		}
 	}
 
 	// fmt.Println(reason)
-	return true
+	return nil
 
 }
 
-func isBDS53(mb []byte) bool {
-	// returns true if the message is likely to be BDS 5,3
+func isBDS53(mb []byte) error {
+	// returns nil if the message is likely to be BDS 5,3
 
 	// check magnetic heading bits are status consistent
 	if (int(mb[0]) & 0b10000000) == 0 {
-		if (int(mb[0]) & 0b00111111) != 0 {
-			log.Debug().Str("reason", "magnetic heading bits not status consistent").Msg("not BDS53")
-			return false
+		if (int(mb[0]) & 0b01111111) != 0 {
+			return errors.New("magnetic heading bits not status consistent")
 		}
 		if (int(mb[1]) & 0b11110000) != 0 {
-			log.Debug().Str("reason", "magnetic heading bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("magnetic heading bits not status consistent")
 		}
 	}
 
 	// check indicated airspeed bits are status consistent
 	if (int(mb[1]) & 0b00001000) == 0 {
 		if (int(mb[1]) & 0b00000111) != 0 {
-			log.Debug().Str("reason", "indicated airspeed bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("indicated airspeed bits not status consistent")
 		}
 		if (int(mb[2]) & 0b11111110) != 0 {
-			log.Debug().Str("reason", "indicated airspeed bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("indicated airspeed bits not status consistent")
 		}
 	}
 
 	// check mach number bits are status consistent
 	if (int(mb[2]) & 0b00000001) == 0 {
 		if int(mb[3]) != 0 {
-			log.Debug().Str("reason", "mach number bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("mach number bits not status consistent")
 		}
 		if (int(mb[4]) & 0b10000000) != 0 {
-			log.Debug().Str("reason", "mach number bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("mach number bits not status consistent")
 		}
 	}
 
 	// check true airspeed bits are status consistent
 	if (int(mb[4]) & 0b01000000) == 0 {
 		if (int(mb[4]) & 0b00111111) != 0 {
-			log.Debug().Str("reason", "true airspeed bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("true airspeed bits not status consistent")
 		}
 		if (int(mb[5]) & 0b11111100) != 0 {
-			log.Debug().Str("reason", "true airspeed bits not status consistent").Msg("not BDS53")
-			return false
+			return errors.New("true airspeed bits not status consistent")
 		}
 	}
 
 	// check altitude rate bits are status consistent
 	if (int(mb[5]) & 0b00000010) == 0 {
-		if int(mb[6]) != 0 {
-			log.Debug().Str("reason", "altitude rate bits not status consistent").Msg("not BDS53")
-			return false
+		if (int(mb[5])&0b00000001) != 0 || int(mb[6]) != 0 {
+			return errors.New("altitude rate bits not status consistent")
 		}
 	}
 
-	return true
+	frame, err := DecodeBDS53(mb)
+	if err != nil {
+		return err
+	}
+
+	// indicated airspeed must be between 0 and 500 knots
+	if frame.IndicatedAirspeedValid && frame.IndicatedAirspeed > 500 {
+		return fmt.Errorf("indicated airspeed out of range (ias: %v)", frame.IndicatedAirspeed)
+	}
+
+	// mach number must be between 0 and 1
+	if frame.MachNumberValid && frame.MachNumber > 1 {
+		return fmt.Errorf("mach number out of range (mach: %v)", frame.MachNumber)
+	}
+
+	// true airspeed must be between 0 and 600 knots
+	if frame.TrueAirspeedValid && frame.TrueAirspeed > 600 {
+		return fmt.Errorf("true airspeed out of range (tas: %v)", frame.TrueAirspeed)
+	}
+
+	// altitude rate must be between -6000 and 6000 ft/min
+	if frame.AltitudeRateValid && (frame.AltitudeRate < -6000 || frame.AltitudeRate > 6000) {
+		return fmt.Errorf("altitude rate out of range (altitudeRate: %v)", frame.AltitudeRate)
+	}
+
+	return nil
 }
 
-func isBDS545556(mb []byte) bool {
-	// returns true if the message is likely to be BDS 5,4, 5,5, 5,6
-	_, err := DecodeBDS54(mb)
+func isBDS545556(mb []byte) error {
+	// returns nil if the message is likely to be BDS 5,4, 5,5, 5,6
+	frame, err := DecodeBDS54(mb)
 	if err != nil {
-		log.Debug().AnErr("reason", err).Msg("not BDS54")
-		return false
+		return err
 	}
-	return true
+
+	// waypoint names are 2-5 characters starting with a letter, eg: "WP1", "ABCDE"
+	if !plausibleWaypoint.MatchString(frame.Waypoint) {
+		return fmt.Errorf("implausible waypoint name (waypoint: %v)", frame.Waypoint)
+	}
+
+	// direct route can't take longer than normal flight
+	if frame.TTG > frame.ETA {
+		return fmt.Errorf("time to go later than ETA (ttg: %v, eta: %v)", frame.TTG, frame.ETA)
+	}
+
+	return nil
 }
 
-func isBDS60(mb []byte) bool {
-	// returns true if message is likely to be BDS 6,0
+func isBDS60(mb []byte) error {
+	// returns nil if message is likely to be BDS 6,0
 	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
 	// if MB bit 1 == 0, then bits 2-12 must be 0
 	if int(mb[0])&0b10000000 == 0 {
 		if int(mb[0])&0b01111111 != 0 {
-			log.Debug().Str("reason", "Magnetic heading bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("magnetic heading bits not status consistent")
 		}
 		if int(mb[1])&0b11110000 != 0 {
-			log.Debug().Str("reason", "Magnetic heading bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("magnetic heading bits not status consistent")
 		}
 	}
 
 	// indicated airspeed must have status consistent bits, and be between 0 and 500 knots
 	if int(mb[1])&0b00001000 == 0 {
 		if int(mb[1])&0b00000111 != 0 {
-			log.Debug().Str("reason", "Indicated airspeed bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("indicated airspeed bits not status consistent")
 		}
 		if int(mb[2])&0b11111110 != 0 {
-			log.Debug().Str("reason", "Indicated airspeed bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("indicated airspeed bits not status consistent")
 		}
 	} else {
 		ias, err := decodeBDS60indicatedAirspeed(mb)
 		if err != nil {
-			log.Debug().AnErr("reason", err).Msg("not BDS60")
-			return false
+			return err
 		}
 		if ias < 0 || ias > 500 {
-			log.Debug().Float64("ias", ias).Str("reason", "Indicated airspeed out of range").Msg("not BDS60")
-			return false
+			return fmt.Errorf("indicated airspeed out of range (ias: %v)", ias)
 		}
 	}
 
 	// mach number must have status consistent bits, and be between 0 and 1
 	if int(mb[2])&0b00000001 == 0 {
 		if int(mb[3]) != 0 {
-			log.Debug().Str("reason", "Mach number bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("mach number bits not status consistent")
 		}
 		if int(mb[4])&0b11000000 != 0 {
-			log.Debug().Str("reason", "Mach number bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("mach number bits not status consistent")
 		}
 	} else {
 		mach, err := decodeBDS60machNumber(mb)
 		if err != nil {
-			log.Debug().AnErr("err", err).Msg("not BDS60")
-			return false
+			return err
 		}
 		if mach < 0 || mach > 1 {
-			log.Debug().Float64("mach", mach).Str("reason", "Mach number out of range").Msg("not BDS60")
-			return false
+			return fmt.Errorf("mach number out of range (mach: %v)", mach)
 		}
 	}
 
 	// Barometric vertical rate must have status consistent bits, and be between -6000 and 6000 fpm
 	if int(mb[4])&0b00100000 == 0 {
 		if int(mb[4])&0b00011111 != 0 {
-			log.Debug().Str("reason", "Barometric vertical rate bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("barometric vertical rate bits not status consistent")
 		}
 		if int(mb[5])&0b11111000 != 0 {
-			log.Debug().Str("reason", "Barometric vertical rate bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("barometric vertical rate bits not status consistent")
 		}
 	} else {
 		bar, err := decodeBDS60barometricAltitudeRate(mb)
 		if err != nil {
-			log.Debug().AnErr("reason", err).Msg("not BDS60")
-			return false
+			return err
 		}
 		if bar < -6000 || bar > 6000 {
-			log.Debug().Float64("bar", bar).Str("reason", "Barometric vertical rate out of range").Msg("not BDS60")
-			return false
+			return fmt.Errorf("barometric vertical rate out of range (bar: %v)", bar)
 		}
 	}
 
 	// Inertial vertical velocity must have status consistent bits, and be between -6000 and 6000 fpm
 	if int(mb[5])&0b00000100 == 0 {
 		if int(mb[5])&0b00000011 != 0 {
-			log.Debug().Str("reason", "Inertial vertical rate bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("inertial vertical rate bits not status consistent")
 		}
 		if int(mb[6]) != 0 {
-			log.Debug().Str("reason", "Inertial vertical rate bits not status consistent").Msg("not BDS60")
-			return false
+			return errors.New("inertial vertical rate bits not status consistent")
 		}
 	} else {
 		ivv, err := decodeBDS60GNSSAltitudeRate(mb)
 		if err != nil {
-			log.Debug().AnErr("reason", err).Msg("not BDS60")
-			return false
+			return err
 		}
 		if ivv < -6000 || ivv > 6000 {
-			log.Debug().Str("reason", "Inertial vertical (GNSS altitude) rate out of range").Msg("not BDS60")
-			return false
+			return errors.New("inertial vertical (GNSS altitude) rate out of range")
 		}
 	}
-	return true
+	return nil
 }
 
 // func isBDS61(mb []byte) bool {
@@ -677,134 +630,186 @@ func isBDS60(mb []byte) bool {
 // 	return true
 // }
 
-func InferBDS(df df.DownlinkFormat, mb []byte) (possibleBDScodes []BDScode, err error) {
-	// BDS codes identification
-	// https://mode-s.org/decode/content/mode-s/9-inference.html
+// errors returned by InferBDS
+var ErrNoBDS = errors.New("could not infer bds")
+var ErrMultipleBDS = errors.New("multiple bds match")
 
-	// For ADS-B / Mode-S extended squitter
-	if df == DF17 || df == DF18 {
+// Result of checking a message against a BDS register
+type CandidateReport struct {
+	BDS      BDScode
+	Accepted bool
+	Reason   string // why the register was rejected, "" if accepted
+	Frame    any    // decoded frame if accepted, eg: BDS50Frame. nil for registers without a decoder (BDS 3,0)
+}
 
-		// Check BDS05
-		_, e := DecodeBDS05(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS05")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS05)
-			return
-		}
+// Result of checking a message against each BDS register
+//
+// Candidates are listed in the order checked. Extended squitter checks stop at the first match,
+// Comm-B MRAR/MHR (BDS 4,4 & 4,5) and BDS 5,3-5,6 are only checked if no other register matched.
+type InferenceReport struct {
+	Candidates []CandidateReport
+}
 
-		// Check BDS06
-		_, e = DecodeBDS06(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS06")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS06)
-			return
+func (r *InferenceReport) Accepted() (codes []BDScode) {
+	// returns the accepted BDS codes, in the order checked
+	for _, candidate := range r.Candidates {
+		if candidate.Accepted {
+			codes = append(codes, candidate.BDS)
 		}
+	}
+	return codes
+}
 
-		// Check BDS08
-		_, e = DecodeBDS08(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS08")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS08)
-			return
+func (r *InferenceReport) Candidate(code BDScode) (candidate CandidateReport, ok bool) {
+	// returns the result of checking the message against a register, ok is false if it wasn't checked
+	for _, candidate := range r.Candidates {
+		if candidate.BDS == code {
+			return candidate, true
 		}
+	}
+	return candidate, false
+}
 
-		// Check BDS09
-		_, e = DecodeBDS09(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS09")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS09)
-			return
-		}
+func (r *InferenceReport) add(code BDScode, frame any, reason error) bool {
+	// records the result of checking a register, returns true if accepted
+	candidate := CandidateReport{BDS: code, Accepted: reason == nil}
+	if reason != nil {
+		candidate.Reason = reason.Error()
+	} else {
+		candidate.Frame = frame
+	}
+	r.Candidates = append(r.Candidates, candidate)
+	return candidate.Accepted
+}
 
-		// Check BDS61
-		_, e = DecodeBDS61(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS61")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS61)
-			return
-		}
+func (r *InferenceReport) addCommB(code BDScode, mb []byte, reason error) {
+	// records the result of checking a Comm-B register, decoding the frame if accepted
+	var frame any
+	if reason == nil {
+		frame, reason = decodeCommB(code, mb)
+	}
+	r.add(code, frame, reason)
+}
 
-		// Check BDS62
-		_, e = DecodeBDS62(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS62")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS62)
-			return
-		}
+func decodeCommB(code BDScode, mb []byte) (frame any, err error) {
+	// decodes a Comm-B register
+	switch code {
+	case BDS10:
+		return DecodeBDS10(mb)
+	case BDS17:
+		return DecodeBDS17(mb)
+	case BDS20:
+		return DecodeBDS20(mb)
+	case BDS40:
+		return DecodeBDS40(mb)
+	case BDS44:
+		return DecodeBDS44(mb)
+	case BDS45:
+		return DecodeBDS45(mb)
+	case BDS50:
+		return DecodeBDS50(mb)
+	case BDS53:
+		return DecodeBDS53(mb)
+	case BDS54:
+		return DecodeBDS54(mb)
+	case BDS55:
+		return DecodeBDS55(mb)
+	case BDS56:
+		return DecodeBDS56(mb)
+	case BDS60:
+		return DecodeBDS60(mb)
+	}
+	return nil, nil
+}
 
-		// Check BDS65
-		_, e = DecodeBDS65(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS65")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS65)
-		}
+func InferBDS(df df.DownlinkFormat, mb []byte) (report InferenceReport, err error) {
+	// BDS codes identification
+	// https://mode-s.org/decode/content/mode-s/9-inference.html
 
-		// Check BDS07
-		_, e = DecodeBDS07(mb)
-		if e != nil {
-			log.Debug().AnErr("reason", e).Msg("not BDS07")
-		} else {
-			possibleBDScodes = append(possibleBDScodes, BDS07)
+	// For ADS-B / Mode-S extended squitter
+	if df == DF17 || df == DF18 {
+		if report.checkExtendedSquitter(mb) {
+			return report, nil
 		}
+		return report, ErrNoBDS
+	}
 
-		// Sanity checks
-		if len(possibleBDScodes) == 0 {
-			if log.Debug().Enabled() {
-				err = errors.New("could not infer extended squitter bds for DF17")
-			}
-		}
-		if len(possibleBDScodes) > 1 {
-			err = errors.New("multiple bds match")
-		}
+	report.addCommB(BDS10, mb, isBDS10(mb))
+	report.addCommB(BDS17, mb, isBDS17(mb))
+	report.addCommB(BDS20, mb, isBDS20(mb))
+	report.addCommB(BDS30, mb, isBDS30(mb))
+	report.addCommB(BDS40, mb, isBDS40(mb))
+	report.addCommB(BDS50, mb, isBDS50(mb))
+	report.addCommB(BDS60, mb, isBDS60(mb))
 
-		return
-	}
+	// MRAR & MHR registers have weak status-bit structure and would otherwise
+	// collide with EHS replies, so only consider them if nothing else matched
+	if len(report.Accepted()) == 0 {
+		report.addCommB(BDS44, mb, isBDS44(mb))
+		report.addCommB(BDS45, mb, isBDS45(mb))
+		report.addCommB(BDS53, mb, isBDS53(mb))
 
-	if isBDS10(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS10)
+		// waypoint registers share a format, so all three are candidates
+		reason := isBDS545556(mb)
+		report.addCommB(BDS54, mb, reason)
+		report.addCommB(BDS55, mb, reason)
+		report.addCommB(BDS56, mb, reason)
 	}
 
-	if isBDS17(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS17)
+	accepted := report.Accepted()
+	if len(accepted) == 0 {
+		err = ErrNoBDS
 	}
-
-	if isBDS20(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS20)
+	if len(accepted) > 1 && !AllWaypoints(accepted) {
+		err = ErrMultipleBDS
 	}
 
-	if isBDS30(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS30)
-	}
+	return report, err
+}
 
-	if isBDS40(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS40)
-	}
+func (r *InferenceReport) checkExtendedSquitter(mb []byte) bool {
+	// checks extended squitter registers in turn, returns true at the first match
 
-	if isBDS50(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS50)
+	if frame, err := DecodeBDS05(mb); r.add(BDS05, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS06(mb); r.add(BDS06, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS08(mb); r.add(BDS08, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS09(mb); r.add(BDS09, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS61(mb); r.add(BDS61, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS62(mb); r.add(BDS62, frame, err) {
+		return true
 	}
 
-	if isBDS60(mb) {
-		possibleBDScodes = append(possibleBDScodes, BDS60)
+	// version 0 operational status decodes as both BDS65 and BDS07, so stop here
+	if frame, err := DecodeBDS65(mb); r.add(BDS65, frame, err) {
+		return true
+	}
+	if frame, err := DecodeBDS07(mb); r.add(BDS07, frame, err) {
+		return true
 	}
 
-	// TODO: BDS44/45
+	return false
+}
 
-	if len(possibleBDScodes) == 0 {
-		if log.Debug().Enabled() {
-			err = errors.New("could not infer bds")
-		}
+func AllWaypoints(codes []BDScode) bool {
+	// returns true if every code is a waypoint register (BDS 5,4, 5,5, 5,6)
+	// waypoint registers share a format, so any of them can be used to decode the message
+	if len(codes) == 0 {
+		return false
 	}
-	if len(possibleBDScodes) > 1 {
-		err = errors.New("multiple bds match")
+	for _, code := range codes {
+		if code != BDS54 && code != BDS55 && code != BDS56 {
+			return false
+		}
 	}
-
-	return possibleBDScodes, err
+	return true
 }
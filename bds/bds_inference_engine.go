@@ -0,0 +1,285 @@
+package bds
+
+// Stateful BDS inference.
+//
+// Comm-B replies (DF20/21) don't identify their register, and several registers can share a structure
+// (eg: BDS 5,0 & 6,0). InferBDS only checks whether a reply is structurally valid for each register.
+// InferenceEngine scores each candidate against what is already known about the aircraft:
+//  - ADS-B ground speed, track, vertical rate & altitude
+//  - registers the aircraft reports as available in its GICB capability report (BDS 1,7)
+//  - registers recently inferred for the aircraft
+// and returns the most plausible candidate with a confidence.
+// See: https://mode-s.org/decode/content/mode-s/9-inference.html
+
+import (
+	"beastdecoder/df"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// how long an inferred register counts towards the aircraft's history
+const inferenceHistoryWindow = time.Minute * 2
+
+// plausibility multipliers
+const scoreImplausible = 0.1  // value contradicts known aircraft state
+const scoreUnlikely = 0.5     // value is a poor match for known aircraft state
+const scoreNotInGICB = 0.2    // aircraft reports the register is not available
+const scoreNotRecent = 0.8    // register not seen recently, while another candidate was
+const scoreNotQuantised = 0.7 // selected altitude not a multiple of 100 ft
+
+// Known state of an aircraft, used to score candidates
+type AircraftContext struct {
+	// When the message was received, zero for now
+	Time time.Time
+
+	// Ground speed (kt) & true track (degrees), from ADS-B airborne velocity
+	GroundSpeedKnown bool
+	GroundSpeed      float64
+	TrackKnown       bool
+	Track            float64
+
+	// Vertical rate (ft/min), from ADS-B airborne velocity
+	VerticalRateKnown bool
+	VerticalRate      int
+
+	// Altitude (ft)
+	AltitudeKnown bool
+	Altitude      int
+
+	// GICB capability report (BDS 1,7)
+	GICBKnown bool
+	GICB      BDS17Frame
+}
+
+// A candidate BDS code & its plausibility (0-1)
+type ScoredCandidate struct {
+	BDS   BDScode
+	Score float64
+}
+
+type InferenceResult struct {
+	BDS        BDScode           // best guess
+	Confidence float64           // 0-1
+	Candidates []ScoredCandidate // accepted candidates, best first
+	Report     InferenceReport   // every register checked, including rejection reasons
+}
+
+type InferenceEngine struct {
+	mu      sync.Mutex
+	history map[int]map[BDScode]time.Time // key is ICAO, value is last time each register was inferred
+}
+
+func (e *InferenceEngine) Init() {
+	// run once to init the engine
+	e.history = make(map[int]map[BDScode]time.Time)
+}
+
+func (e *InferenceEngine) Forget(icao int) {
+	// removes an aircraft's history, eg: when it is no longer tracked
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.history, icao)
+}
+
+func (e *InferenceEngine) Infer(icao int, downlinkFormat df.DownlinkFormat, mb []byte, ctx AircraftContext) (result InferenceResult, err error) {
+	// returns the most plausible BDS code for the message, given what is known about the aircraft
+
+	result.Report, err = InferBDS(downlinkFormat, mb)
+	candidates := result.Report.Accepted()
+	if len(candidates) == 0 {
+		return
+	}
+	err = nil
+
+	// extended squitters identify their register by type code, so aren't scored
+	if downlinkFormat == df.DF17 || downlinkFormat == df.DF18 {
+		result.BDS = candidates[0]
+		result.Confidence = 1
+		result.Candidates = []ScoredCandidate{{BDS: result.BDS, Score: 1}}
+		return
+	}
+
+	now := ctx.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	seen := e.history[icao]
+
+	// is any candidate in the aircraft's recent history
+	anyRecent := false
+	for _, code := range candidates {
+		if now.Sub(seen[code]) < inferenceHistoryWindow {
+			anyRecent = true
+		}
+	}
+
+	// score candidates
+	var total float64
+	for _, code := range candidates {
+		candidate, _ := result.Report.Candidate(code)
+		score := scoreCandidate(candidate, ctx)
+		if anyRecent && now.Sub(seen[code]) >= inferenceHistoryWindow {
+			score *= scoreNotRecent
+		}
+		result.Candidates = append(result.Candidates, ScoredCandidate{BDS: code, Score: score})
+		total += score
+	}
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Score > result.Candidates[j].Score
+	})
+
+	// best guess, scaled by its share of the total
+	// waypoint registers share a format and decode the same, so are counted as one candidate
+	best := result.Candidates[0]
+	result.BDS = best.BDS
+	waypoints := AllWaypoints(candidates)
+	if waypoints {
+		total = best.Score
+	}
+	if total > 0 {
+		result.Confidence = best.Score * (best.Score / total)
+	}
+
+	// waypoint registers can't be told apart
+	if len(result.Candidates) > 1 && result.Candidates[1].Score == best.Score && !waypoints {
+		err = errors.New("multiple bds equally plausible")
+		return
+	}
+
+	// record in history
+	if seen == nil {
+		seen = make(map[BDScode]time.Time)
+		e.history[icao] = seen
+	}
+	seen[result.BDS] = now
+
+	return
+}
+
+func scoreCandidate(candidate CandidateReport, ctx AircraftContext) (score float64) {
+	// returns the plausibility (0-1) of the message being the register, given the aircraft's known state
+	score = 1
+
+	// register not available according to the aircraft's GICB capability report
+	if _, reported := gicbRegisterBits[candidate.BDS]; reported && ctx.GICBKnown && !ctx.GICB.Supports(candidate.BDS) {
+		score *= scoreNotInGICB
+	}
+
+	switch frame := candidate.Frame.(type) {
+	case BDS40Frame:
+		if frame.McpFcuSelectedAltitudeValid && !isQuantisedAltitude(frame.McpFcuSelectedAltitude) {
+			score *= scoreNotQuantised
+		}
+		if frame.FmsSelectedAltitudeValid && !isQuantisedAltitude(frame.FmsSelectedAltitude) {
+			score *= scoreNotQuantised
+		}
+
+	case BDS50Frame:
+		if frame.GroundSpeedValid {
+			score *= scoreSpeed(frame.GroundSpeed, ctx, 20, 50)
+		}
+		if frame.TrueTrackAngleValid {
+			score *= scoreTrack(frame.TrueTrackAngle, ctx, 8, 20)
+		}
+		if frame.TrueAirspeedValid {
+			score *= scoreSpeed(frame.TrueAirspeed, ctx, 80, 150)
+		}
+
+	case BDS53Frame:
+		if frame.MagneticHeadingValid {
+			score *= scoreTrack(frame.MagneticHeading, ctx, 30, 60)
+		}
+		if frame.TrueAirspeedValid {
+			score *= scoreSpeed(frame.TrueAirspeed, ctx, 80, 150)
+		}
+		if frame.AltitudeRateValid {
+			score *= scoreVerticalRate(frame.AltitudeRate, ctx)
+		}
+
+	case BDS60Frame:
+		if frame.MagneticHeadingValid {
+			score *= scoreTrack(frame.MagneticHeading, ctx, 30, 60)
+		}
+		if frame.IndicatedAirspeedValid && ctx.AltitudeKnown {
+			score *= scoreSpeed(approximateTAS(frame.IndicatedAirspeed, ctx.Altitude), ctx, 80, 150)
+		}
+		if frame.BarometricAltitudeRateValid {
+			score *= scoreVerticalRate(frame.BarometricAltitudeRate, ctx)
+		} else if frame.GNSSAltitudeRateValid {
+			score *= scoreVerticalRate(frame.GNSSAltitudeRate, ctx)
+		}
+	}
+
+	return score
+}
+
+func scoreSpeed(speed float64, ctx AircraftContext, unlikely, implausible float64) float64 {
+	// compares a speed (kt) to the aircraft's ground speed
+	if !ctx.GroundSpeedKnown {
+		return 1
+	}
+	diff := math.Abs(speed - ctx.GroundSpeed)
+	switch {
+	case diff > implausible:
+		return scoreImplausible
+	case diff > unlikely:
+		return scoreUnlikely
+	}
+	return 1
+}
+
+func scoreTrack(angle float64, ctx AircraftContext, unlikely, implausible float64) float64 {
+	// compares an angle (degrees) to the aircraft's track
+	if !ctx.TrackKnown {
+		return 1
+	}
+	diff := angleDifference(angle, ctx.Track)
+	switch {
+	case diff > implausible:
+		return scoreImplausible
+	case diff > unlikely:
+		return scoreUnlikely
+	}
+	return 1
+}
+
+func scoreVerticalRate(rate float64, ctx AircraftContext) float64 {
+	// compares a vertical rate (ft/min) to the aircraft's vertical rate
+	if !ctx.VerticalRateKnown {
+		return 1
+	}
+	diff := math.Abs(rate - float64(ctx.VerticalRate))
+	switch {
+	case diff > 2000:
+		return scoreImplausible
+	case diff > 1000:
+		return scoreUnlikely
+	}
+	return 1
+}
+
+func angleDifference(a, b float64) float64 {
+	// returns the absolute difference between two angles (degrees), 0-180
+	diff := math.Mod(math.Abs(a-b), 360)
+	if diff > 180 {
+		diff = 360 - diff
+	}
+	return diff
+}
+
+func approximateTAS(ias float64, altitude int) float64 {
+	// rule of thumb true airspeed (kt) from indicated airspeed & altitude (ft), +2% per 1000 ft
+	return ias * (1 + 0.02*float64(altitude)/1000)
+}
+
+func isQuantisedAltitude(altitude int) bool {
+	// selected altitudes are set in 100 ft steps, within the 16 ft resolution of BDS 4,0
+	rem := altitude % 100
+	return rem < 16 || rem > 100-16
+}
@@ -0,0 +1,132 @@
+package bds
+
+import (
+	"beastdecoder/df"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// structurally valid as both BDS 5,0 & 6,0
+//
+//	BDS 5,0: track 214.6°, ground speed 106 kt
+//	BDS 6,0: heading 349.3°, IAS 197 kt, altitude rate 3040 ft/min
+var ambiguousBDS5060 = []byte{0xfc, 0x39, 0x8b, 0x0d, 0x6d, 0x08, 0x00}
+
+func TestInferenceEngineADSB(t *testing.T) {
+	assert := assert.New(t)
+
+	var e InferenceEngine
+	e.Init()
+
+	// matches track & turn report
+	result, err := e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{
+		GroundSpeedKnown: true,
+		GroundSpeed:      110,
+		TrackKnown:       true,
+		Track:            215,
+	})
+	assert.NoError(err)
+	assert.Equal(BDS50, result.BDS)
+	assert.Greater(result.Confidence, 0.8)
+	assert.Len(result.Candidates, 2)
+	assert.Equal(BDS60, result.Candidates[1].BDS)
+
+	// matches heading & speed report
+	result, err = e.Infer(0x7c5678, df.DF20, ambiguousBDS5060, AircraftContext{
+		GroundSpeedKnown:  true,
+		GroundSpeed:       205,
+		TrackKnown:        true,
+		Track:             355,
+		VerticalRateKnown: true,
+		VerticalRate:      2900,
+		AltitudeKnown:     true,
+		Altitude:          3000,
+	})
+	assert.NoError(err)
+	assert.Equal(BDS60, result.BDS)
+	assert.Greater(result.Confidence, 0.8)
+}
+
+func TestInferenceEngineGICB(t *testing.T) {
+	assert := assert.New(t)
+
+	var e InferenceEngine
+	e.Init()
+
+	// only BDS 6,0 reported as available
+	gicb := BDS17Frame{Capabilities: 1 << (24 - gicbRegisterBits[BDS60])}
+	result, err := e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{GICBKnown: true, GICB: gicb})
+	assert.NoError(err)
+	assert.Equal(BDS60, result.BDS)
+}
+
+func TestInferenceEngineExtendedSquitter(t *testing.T) {
+	assert := assert.New(t)
+
+	var e InferenceEngine
+	e.Init()
+
+	// airborne position, from 8D40621D58C382D690C8AC2863A7
+	// not reported as available, but the type code identifies the register
+	me := []byte{0x58, 0xC3, 0x82, 0xD6, 0x90, 0xC8, 0xAC}
+	gicb := BDS17Frame{Capabilities: 1<<(24-gicbRegisterBits[BDS20]) | 1<<(24-gicbRegisterBits[BDS40])}
+	for _, downlinkFormat := range []df.DownlinkFormat{df.DF17, df.DF18} {
+		result, err := e.Infer(0x40621d, downlinkFormat, me, AircraftContext{GICBKnown: true, GICB: gicb})
+		assert.NoError(err)
+		assert.Equal(BDS05, result.BDS)
+		assert.Equal(1.0, result.Confidence)
+	}
+}
+
+func TestInferenceEngineHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	var e InferenceEngine
+	e.Init()
+
+	// nothing known, can't decide
+	_, err := e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{})
+	assert.Error(err)
+
+	// aircraft recently replied with BDS 6,0
+	_, err = e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{
+		GroundSpeedKnown: true,
+		GroundSpeed:      205,
+		TrackKnown:       true,
+		Track:            355,
+	})
+	assert.NoError(err)
+	result, err := e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{})
+	assert.NoError(err)
+	assert.Equal(BDS60, result.BDS)
+
+	// history is per aircraft
+	_, err = e.Infer(0x7c5678, df.DF20, ambiguousBDS5060, AircraftContext{})
+	assert.Error(err)
+
+	// forgotten
+	e.Forget(0x7c1234)
+	_, err = e.Infer(0x7c1234, df.DF20, ambiguousBDS5060, AircraftContext{})
+	assert.Error(err)
+}
+
+func TestInferenceEngineUnambiguous(t *testing.T) {
+	assert := assert.New(t)
+
+	var e InferenceEngine
+	e.Init()
+
+	result, err := e.Infer(0x7c1234, df.DF20, []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00}, AircraftContext{})
+	assert.NoError(err)
+	assert.Equal(BDS44, result.BDS)
+	assert.Equal(1.0, result.Confidence)
+
+	// waypoint registers can't be told apart, but decode the same
+	for i := 0; i < 3; i++ {
+		result, err = e.Infer(0x7c1234, df.DF20, []byte{0x02, 0x10, 0x62, 0x0A, 0x80, 0x8C, 0x80}, AircraftContext{})
+		assert.NoError(err)
+		assert.Equal(BDS54, result.BDS)
+		assert.Equal(1.0, result.Confidence)
+	}
+}
@@ -1,5 +1,96 @@
 package bds
 
+import (
+	"beastdecoder/df"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferBDS44(t *testing.T) {
+	// A0001692185BD5CF400000DFC696
+	mb := []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00}
+	report, err := InferBDS(df.DF20, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS44}, report.Accepted())
+}
+
+func TestInferBDS45(t *testing.T) {
+	mb := []byte{0xC0, 0x51, 0xCF, 0x40, 0x02, 0x0C, 0x80}
+	report, err := InferBDS(df.DF20, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS45}, report.Accepted())
+}
+
+func TestInferBDS65(t *testing.T) {
+	// version 0 operational status
+	mb := []byte{0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	report, err := InferBDS(df.DF17, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS65}, report.Accepted())
+}
+
+func TestInferBDS07(t *testing.T) {
+	// version 2 operational status
+	mb := []byte{0xF8, 0x10, 0x20, 0x06, 0x00, 0x49, 0xB8}
+	report, err := InferBDS(df.DF17, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS07}, report.Accepted())
+}
+
+func TestInferBDS53(t *testing.T) {
+	mb := []byte{0xA0, 0x09, 0xF5, 0x32, 0x4E, 0x13, 0xF0}
+	report, err := InferBDS(df.DF20, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS53}, report.Accepted())
+}
+
+func TestInferBDS545556(t *testing.T) {
+	mb := []byte{0x02, 0x10, 0x62, 0x0A, 0x80, 0x8C, 0x80}
+	report, err := InferBDS(df.DF20, mb)
+	assert.NoError(t, err)
+	assert.Equal(t, []BDScode{BDS54, BDS55, BDS56}, report.Accepted())
+	assert.True(t, AllWaypoints(report.Accepted()))
+	assert.False(t, AllWaypoints([]BDScode{BDS54, BDS60}))
+}
+
+func TestInferBDSReport(t *testing.T) {
+	assert := assert.New(t)
+
+	mb := []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00}
+	report, err := InferBDS(df.DF20, mb)
+	assert.NoError(err)
+
+	// rejected, with reason
+	candidate, ok := report.Candidate(BDS10)
+	assert.True(ok)
+	assert.False(candidate.Accepted)
+	assert.Equal("bds code mismatch", candidate.Reason)
+	assert.Nil(candidate.Frame)
+
+	// accepted, with decoded frame
+	candidate, ok = report.Candidate(BDS44)
+	assert.True(ok)
+	assert.True(candidate.Accepted)
+	assert.Equal("", candidate.Reason)
+	assert.IsType(BDS44Frame{}, candidate.Frame)
+
+	// extended squitter checks stop at the first match
+	report, err = InferBDS(df.DF17, []byte{0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	assert.NoError(err)
+	_, ok = report.Candidate(BDS07)
+	assert.False(ok)
+	candidate, ok = report.Candidate(BDS05)
+	assert.True(ok)
+	assert.Equal("type code not 9-18 or 20-22", candidate.Reason)
+
+	// nothing matches
+	report, err = InferBDS(df.DF20, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	assert.ErrorIs(err, ErrNoBDS)
+	assert.Empty(report.Accepted())
+	assert.NotEmpty(report.Candidates)
+}
+
 // func TestNotInferrable(t *testing.T) {
 // 	// a921109446da704cd0690dffe93e
 // 	// a800091000800081c081f052a261
@@ -37,6 +128,6 @@ package bds
 // 	msg := decodeDF20(data)
 // 	bc, err := inferBDS(msg.mb)
 // 	assert.NoError(t, err)
-// 	assert.Equal(t, []BDScode{BDS60}, bc)
+// 	assert.Equal(t, []BDScode{BDS60}, report.Accepted())
 
 // }
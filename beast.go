@@ -2,10 +2,9 @@ package main
 
 import (
 	"beastdecoder/df"
+	"beastdecoder/vesselstate"
 	"errors"
-	"net"
-	"os"
-	"time"
+	"io"
 
 	"github.com/rs/zerolog/log"
 )
@@ -17,169 +16,7 @@ const syncStateNot1a = syncState(1)
 const syncState1a = syncState(2)
 const syncStateFrameType = syncState(3)
 
-func beastDial(addr *net.TCPAddr) {
-
-	// set up logger
-	log := log.With().Str("src", addr.String()).Logger()
-
-	var frameType byte
-	buf := make([]byte, 2)
-
-	for {
-
-		log.Info().Msg("connecting")
-		conn, err := net.DialTCP("tcp", nil, addr)
-		if err != nil {
-			log.Info().Err(err).Msg("connection error")
-			continue
-		}
-		defer conn.Close()
-		log.Info().Msg("connected, synchronising")
-
-		synced := false
-		for {
-			if !synced {
-				frameType, err = beastSync(conn)
-				if err != nil {
-					log.Err(err).Msg("synchronisation error")
-					break
-				} else {
-					synced = true
-					log.Info().Msg("synchronised, receiving")
-				}
-
-			} else {
-				_, err := conn.Read(buf)
-				if err != nil {
-					log.Err(err).Msg("receive error")
-					break
-				}
-				if buf[0] == 0x1a {
-					frameType = buf[1]
-				} else {
-					synced = false
-				}
-			}
-
-			if synced {
-
-				frameData, err := beastReadFrame(conn, frameType)
-				if err != nil {
-					log.Err(err).Msg("frame read error")
-					break
-				}
-
-				var data []byte
-				var DF df.DownlinkFormat
-
-				switch frameType {
-
-				// Mode-AC frame
-				case 0x31:
-					// ignore this frame type
-					continue
-
-				// Mode-S short frame
-				case 0x32:
-					_, _, data = handleFrameData(frameType, frameData)
-					DF = df.GetDF(data)
-
-				// Mode-S long frame
-				case 0x33:
-					_, _, data = handleFrameData(frameType, frameData)
-					DF = df.GetDF(data)
-
-				default:
-					log.Warn().Hex("frameType", []byte{frameType}).Hex("frameData", frameData).Msg("unknown frameType")
-				}
-
-				log.Debug().Msg("START OF FRAME")
-
-				log.Debug().Uint8("DF", uint8(DF)).Hex("data", data).Msg("received")
-
-				switch DF {
-				case df.DF0:
-					msg, err := df.DecodeDF0(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF0")
-					} else {
-						vdb.UpdateFromDF0(msg)
-					}
-
-				case df.DF4:
-					msg, err := df.DecodeDF4(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF4")
-					} else {
-						vdb.UpdateFromDF4(msg)
-					}
-
-				case df.DF5:
-					msg, err := df.DecodeDF5(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF5")
-					} else {
-						vdb.UpdateFromDF5(msg)
-					}
-
-				case df.DF11:
-					msg := df.DecodeDF11(data)
-					vdb.UpdateFromDF11(msg)
-
-				case df.DF16:
-					msg, err := df.DecodeDF16(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF16")
-					} else {
-						vdb.UpdateFromDF16(msg)
-					}
-
-				case df.DF17:
-					msg := df.DecodeDF17(data)
-					vdb.UpdateFromDF17(msg, data)
-
-				case df.DF18:
-					msg := df.DecodeDF18(data)
-					vdb.UpdateFromDF18(msg, data)
-
-				case df.DF19:
-					// military stuff, can't decode
-					break
-
-				case df.DF20:
-					msg, err := df.DecodeDF20(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF20")
-					} else {
-						vdb.UpdateFromDF20(msg, data)
-					}
-
-				case df.DF21:
-					msg, err := df.DecodeDF21(data)
-					if err != nil {
-						log.Err(err).Hex("data", data).Msg("error decoding DF21")
-					} else {
-						vdb.UpdateFromDF21(msg, data)
-					}
-
-				case df.DF24:
-					// extended length messages
-					// TODO: decode these
-					break
-
-				default:
-					log.Error().Hex("data", data).Msg("unsupported data")
-					os.Exit(1)
-				}
-
-				log.Debug().Msg("END OF FRAME")
-			}
-		}
-		time.Sleep(time.Second * 30)
-	}
-}
-
-func beastSync(conn *net.TCPConn) (frameType byte, err error) {
+func beastSync(conn io.Reader) (frameType byte, err error) {
 
 	state := syncStateUnknown
 
@@ -228,7 +65,7 @@ func beastSync(conn *net.TCPConn) (frameType byte, err error) {
 	return frameType, err
 }
 
-func beastReadFrame(conn *net.TCPConn, frameType byte) (frameData []byte, err error) {
+func beastReadFrame(conn io.Reader, frameType byte) (frameData []byte, err error) {
 
 	buf := make([]byte, 1)
 	bytesToRead := 0
@@ -290,10 +127,149 @@ func beastReadFrame(conn *net.TCPConn, frameType byte) (frameData []byte, err er
 
 func handleFrameData(frameType byte, frameData []byte) (mlatTimestamp []byte, rssi byte, data []byte) {
 
-	mlatTimestamp = frameData[0:5]
+	mlatTimestamp = frameData[0:6]
 	rssi = frameData[6]
 	data = frameData[7:]
 
 	return mlatTimestamp, rssi, data
 
 }
+
+func beastMessage(frameType byte, frameData []byte) capturedMessage {
+	// returns the timestamp, signal level & Mode-S data of a frame
+	mlatTimestamp, rssi, data := handleFrameData(frameType, frameData)
+	msg := capturedMessage{
+		timestampKnown: true,
+		timestamp:      mlatSeconds(mlatTimestamp),
+		data:           data,
+	}
+	msg.rssi, msg.rssiKnown = rssiDBFS(rssi)
+	return msg
+}
+
+// A frame decoded by a worker, ready to apply to the vessel db
+type decodedFrame struct {
+	df   df.DownlinkFormat
+	msg  any // eg: df.DF17message, nil if the frame couldn't be decoded or isn't used
+	data []byte
+
+	receivers []string // inputs that heard the frame, if known
+}
+
+func updateVessels(vdb *vesselstate.Vessels, data []byte) {
+	// decodes a Mode-S message and updates the vessel db
+	applyFrame(vdb, decodeFrame(data))
+}
+
+func decodeFrame(data []byte) (frame decodedFrame) {
+	// decodes a Mode-S message, independently of the vessel db so frames can be decoded in parallel
+
+	DF := df.GetDF(data)
+	frame.df = DF
+	frame.data = data
+
+	log.Debug().Uint8("DF", uint8(DF)).Hex("data", data).Msg("received")
+
+	// short formats are 56 bits, long formats (DF16 onwards) are 112 bits
+	if (DF >= df.DF16) != (len(data) == 14) {
+		log.Warn().Uint8("DF", uint8(DF)).Hex("data", data).Msg("message length doesn't match downlink format")
+		return frame
+	}
+
+	switch DF {
+	case df.DF0:
+		msg, err := df.DecodeDF0(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF0")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF4:
+		msg, err := df.DecodeDF4(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF4")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF5:
+		msg, err := df.DecodeDF5(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF5")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF11:
+		frame.msg = df.DecodeDF11(data)
+
+	case df.DF16:
+		msg, err := df.DecodeDF16(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF16")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF17:
+		frame.msg = df.DecodeDF17(data)
+
+	case df.DF18:
+		frame.msg = df.DecodeDF18(data)
+
+	case df.DF19:
+		// military stuff, can't decode
+		break
+
+	case df.DF20:
+		msg, err := df.DecodeDF20(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF20")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF21:
+		msg, err := df.DecodeDF21(data)
+		if err != nil {
+			log.Err(err).Hex("data", data).Msg("error decoding DF21")
+		} else {
+			frame.msg = msg
+		}
+
+	case df.DF24:
+		// extended length messages
+		// TODO: decode these
+		break
+
+	default:
+		log.Warn().Hex("data", data).Msg("unsupported data")
+	}
+
+	return frame
+}
+
+func applyFrame(vdb *vesselstate.Vessels, frame decodedFrame) {
+	// updates the vessel db from a decoded frame
+	switch msg := frame.msg.(type) {
+	case df.DF0message:
+		vdb.UpdateFromDF0(msg)
+	case df.DF4message:
+		vdb.UpdateFromDF4(msg)
+	case df.DF5message:
+		vdb.UpdateFromDF5(msg)
+	case df.DF11message:
+		vdb.UpdateFromDF11(msg)
+	case df.DF16message:
+		vdb.UpdateFromDF16(msg)
+	case df.DF17message:
+		vdb.UpdateFromDF17(msg, frame.data)
+	case df.DF18message:
+		vdb.UpdateFromDF18(msg, frame.data)
+	case df.DF20message:
+		vdb.UpdateFromDF20(msg, frame.data)
+	case df.DF21message:
+		vdb.UpdateFromDF21(msg, frame.data)
+	}
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+func SquawkFromIdentityCode(id int) (squawk int, err error) {
+	// returns a squawk code from an Identity code (DF5, DF21, BDS 6,1)
+	// https://mode-s.org/decode/content/mode-s/3-surveillance.html#sec:id_code
+
+	// The 13-bit identity code encodes the 4 octal digit squawk code (from 0000 to 7777). The structure of this field is shown as follows:
+
+	// +----+----+----+----+----+----+---+----+----+----+----+----+----+
+	// | C1 | A1 | C2 | A2 | C4 | A4 | X | B1 | D1 | B2 | D2 | B4 | D4 |
+	// +----+----+----+----+----+----+---+----+----+----+----+----+----+
+	// The binary representation of the octal digit is:
+
+	// A4 A2 A1 | B4 B2 B1 | C4 C2 C1 | D4 D2 D1
+
+	a := (((id & 0b0000010000000) >> 5) + ((id & 0b0001000000000) >> 8) + ((id & 0b0100000000000) >> 11)) * 1000
+	b := (((id & 0b0000000000010) << 1) + ((id & 0b0000000001000) >> 2) + ((id & 0b0000000100000) >> 5)) * 100
+	c := (((id & 0b0000100000000) >> 6) + ((id & 0b0010000000000) >> 9) + ((id & 0b1000000000000) >> 12)) * 10
+	d := (((id & 0b0000000000001) << 2) + ((id & 0b0000000000100) >> 1) + ((id & 0b0000000010000) >> 4))
+
+	squawk = a + b + c + d
+	if squawk < 0 || squawk >= 10000 {
+		err = errors.New(fmt.Sprintf("invalid squawk code: %d", squawk))
+	}
+
+	return
+}
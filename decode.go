@@ -0,0 +1,330 @@
+package main
+
+import (
+	"beastdecoder/bds"
+	"beastdecoder/df"
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Parity check result
+type crcStatus string
+
+const crcOK = crcStatus("ok")                        // parity matches
+const crcBad = crcStatus("bad")                      // parity doesn't match, message is corrupt
+const crcAddressParity = crcStatus("address parity") // ICAO address is overlaid on parity, so it can't be checked
+
+// Breakdown of a single message
+type decodedMessage struct {
+	Hex        string             `json:"hex"`
+	DF         df.DownlinkFormat  `json:"df"`
+	CRC        crcStatus          `json:"crc"`
+	ICAO       string             `json:"icao,omitempty"`
+	Message    any                `json:"message,omitempty"` // decoded downlink format
+	BDS        string             `json:"bds,omitempty"`     // inferred register, if unambiguous
+	Candidates []decodedCandidate `json:"candidates,omitempty"`
+	Errors     []string           `json:"errors,omitempty"`
+}
+
+// A register checked during BDS inference
+type decodedCandidate struct {
+	BDS      string `json:"bds"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"` // why the register was rejected
+	Frame    any    `json:"frame,omitempty"`  // decoded register, if accepted
+}
+
+func decode(ctx *cli.Context) error {
+	// decodes hex messages given as arguments, or one per line from stdin
+
+	initLogger(ctx)
+
+	out := ctx.App.Writer
+	decodeOne := func(s string) error {
+		msg := decodeHexMessage(s)
+		if ctx.Bool("json") {
+			return json.NewEncoder(out).Encode(msg)
+		}
+		msg.print(out)
+		return nil
+	}
+
+	if ctx.NArg() > 0 {
+		for _, s := range ctx.Args().Slice() {
+			if err := decodeOne(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		if err := decodeOne(s); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func parseHexMessage(s string) (data []byte, err error) {
+	// parses a hex message, with or without AVR framing (eg: *8D4840D6202CC371C32CE0576098;)
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "*")
+	s = strings.TrimSuffix(s, ";")
+	data, err = hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 7 && len(data) != 14 {
+		return nil, fmt.Errorf("message must be 7 or 14 bytes (length: %d)", len(data))
+	}
+	return data, nil
+}
+
+func decodeHexMessage(s string) (msg decodedMessage) {
+	// decodes a hex message into a breakdown of its fields
+	data, err := parseHexMessage(s)
+	if err != nil {
+		msg.Hex = strings.ToUpper(strings.TrimSpace(s))
+		msg.Errors = append(msg.Errors, err.Error())
+		return msg
+	}
+	return decodeMessage(data)
+}
+
+func decodeMessage(data []byte) (msg decodedMessage) {
+	// decodes a message into a breakdown of its fields
+	msg.Hex = fmt.Sprintf("%X", data)
+	msg.DF = df.GetDF(data)
+
+	// short formats are 56 bits, long formats (DF16 onwards) are 112 bits
+	if (msg.DF >= df.DF16) != (len(data) == 14) {
+		msg.Errors = append(msg.Errors, fmt.Sprintf("message length doesn't match downlink format (length: %d)", len(data)))
+		return msg
+	}
+
+	// parity
+	remainder := df.CRCRemainder(data)
+	switch msg.DF {
+	case df.DF11:
+		// interrogator identifier is overlaid on the lower 7 bits of parity
+		msg.CRC = crcBad
+		if remainder&^0x7f == 0 {
+			msg.CRC = crcOK
+		}
+	case df.DF17, df.DF18:
+		msg.CRC = crcBad
+		if remainder == 0 {
+			msg.CRC = crcOK
+		}
+	default:
+		msg.CRC = crcAddressParity
+	}
+
+	// downlink format
+	var icao int
+	var mb []byte
+	switch msg.DF {
+	case df.DF0:
+		m, err := df.DecodeDF0(data)
+		msg.Message, icao = m, m.ICAO
+		msg.addError(err)
+	case df.DF4:
+		m, err := df.DecodeDF4(data)
+		msg.Message, icao = m, m.ICAO
+		msg.addError(err)
+	case df.DF5:
+		m, err := df.DecodeDF5(data)
+		msg.Message, icao = m, m.ICAO
+		msg.addError(err)
+	case df.DF11:
+		m := df.DecodeDF11(data)
+		msg.Message, icao = m, m.ICAO
+	case df.DF16:
+		m, err := df.DecodeDF16(data)
+		msg.Message, icao = m, m.ICAO
+		msg.addError(err)
+	case df.DF17:
+		m := df.DecodeDF17(data)
+		msg.Message, icao, mb = m, m.ICAO, m.ME
+	case df.DF18:
+		m := df.DecodeDF18(data)
+		msg.Message, icao, mb = m, m.ICAO, m.ME
+	case df.DF20:
+		m, err := df.DecodeDF20(data)
+		msg.Message, icao, mb = m, m.ICAO, m.MB
+		msg.addError(err)
+	case df.DF21:
+		m, err := df.DecodeDF21(data)
+		msg.Message, icao, mb = m, m.ICAO, m.MB
+		msg.addError(err)
+	default:
+		msg.Errors = append(msg.Errors, fmt.Sprintf("unsupported downlink format (df: %d)", msg.DF))
+		return msg
+	}
+	msg.ICAO = fmt.Sprintf("%06X", icao)
+
+	// register
+	if mb == nil {
+		return msg
+	}
+	report, err := bds.InferBDS(msg.DF, mb)
+	for _, candidate := range report.Candidates {
+		msg.Candidates = append(msg.Candidates, decodedCandidate{
+			BDS:      sprintBDS(candidate.BDS),
+			Accepted: candidate.Accepted,
+			Reason:   candidate.Reason,
+			Frame:    candidate.Frame,
+		})
+	}
+	if err != nil {
+		msg.addError(err)
+	} else if accepted := report.Accepted(); len(accepted) > 0 {
+		msg.BDS = sprintBDS(accepted[0])
+	}
+
+	return msg
+}
+
+func (msg *decodedMessage) flatten() map[string]any {
+	// returns the exported fields of the downlink format and inferred register, keyed by name
+	fields := make(map[string]any)
+	addFields(fields, msg.Message)
+	for _, candidate := range msg.Candidates {
+		if candidate.Accepted && candidate.BDS == msg.BDS {
+			addFields(fields, candidate.Frame)
+		}
+	}
+
+	// already included, or raw message contents
+	delete(fields, "ICAO")
+	delete(fields, "ME")
+	delete(fields, "MB")
+
+	return fields
+}
+
+func (msg decodedMessage) MarshalJSON() ([]byte, error) {
+	// encodes the message with raw message contents & ICAO address in hex, as printed
+	type plain decodedMessage
+	out := plain(msg)
+	if msg.Message != nil {
+		out.Message = hexFields(msg.Message)
+	}
+	return json.Marshal(out)
+}
+
+func (msg *decodedMessage) addError(err error) {
+	if err != nil {
+		msg.Errors = append(msg.Errors, err.Error())
+	}
+}
+
+func (msg *decodedMessage) print(w io.Writer) {
+	// prints a human-readable breakdown of the message
+	fmt.Fprintln(w, msg.Hex)
+	if msg.Message != nil {
+		fmt.Fprintf(w, "  DF:   %d\n", msg.DF)
+		fmt.Fprintf(w, "  CRC:  %s\n", msg.CRC)
+		fmt.Fprintf(w, "  ICAO: %s\n", msg.ICAO)
+		if msg.BDS != "" {
+			fmt.Fprintf(w, "  BDS:  %s\n", msg.BDS)
+		}
+		fmt.Fprintf(w, "  %T:\n", msg.Message)
+		printFields(w, "    ", msg.Message)
+	}
+	if len(msg.Candidates) > 0 {
+		fmt.Fprintln(w, "  BDS candidates:")
+		for _, candidate := range msg.Candidates {
+			if !candidate.Accepted {
+				fmt.Fprintf(w, "    BDS %s rejected: %s\n", candidate.BDS, candidate.Reason)
+				continue
+			}
+			fmt.Fprintf(w, "    BDS %s accepted:\n", candidate.BDS)
+			if candidate.Frame != nil {
+				printFields(w, "      ", candidate.Frame)
+			}
+		}
+	}
+	for _, err := range msg.Errors {
+		fmt.Fprintf(w, "  error: %s\n", err)
+	}
+	fmt.Fprintln(w)
+}
+
+func printFields(w io.Writer, indent string, v any) {
+	// prints each field of a struct on its own line, including unexported fields
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		fmt.Fprintf(w, "%s%v\n", indent, v)
+		return
+	}
+
+	width := 0
+	for i := 0; i < rv.NumField(); i++ {
+		if len(rv.Type().Field(i).Name) > width {
+			width = len(rv.Type().Field(i).Name)
+		}
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		name := rv.Type().Field(i).Name
+		field := rv.Field(i)
+		switch {
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+			fmt.Fprintf(w, "%s%-*s  %X\n", indent, width, name, field)
+		case name == "ICAO" && field.Kind() == reflect.Int:
+			fmt.Fprintf(w, "%s%-*s  %06X\n", indent, width, name, field.Int())
+		default:
+			fmt.Fprintf(w, "%s%-*s  %v\n", indent, width, name, field)
+		}
+	}
+}
+
+func addFields(fields map[string]any, v any) {
+	// adds the exported fields of a struct to fields
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).IsExported() {
+			fields[rv.Type().Field(i).Name] = rv.Field(i).Interface()
+		}
+	}
+}
+
+func hexFields(v any) map[string]any {
+	// returns the exported fields of a struct keyed by name, with byte slices & ICAO address in hex
+	fields := make(map[string]any)
+	addFields(fields, v)
+	for name, value := range fields {
+		switch value := value.(type) {
+		case []byte:
+			fields[name] = fmt.Sprintf("%X", value)
+		case int:
+			if name == "ICAO" {
+				fields[name] = fmt.Sprintf("%06X", value)
+			}
+		}
+	}
+	return fields
+}
+
+func sprintBDS(code bds.BDScode) string {
+	// formats a BDS code as it is usually written, eg: 4,0
+	return fmt.Sprintf("%d,%d", code/10, code%10)
+}
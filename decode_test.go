@@ -0,0 +1,155 @@
+package main
+
+import (
+	"beastdecoder/df"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHexMessage(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name           string
+		msg            string
+		expectedDF     df.DownlinkFormat
+		expectedCRC    crcStatus
+		expectedICAO   string
+		expectedBDS    string
+		expectedErrors bool
+	}{
+		{
+			name:         "DF17 identification",
+			msg:          "8D4840D6202CC371C32CE0576098",
+			expectedDF:   df.DF17,
+			expectedCRC:  crcOK,
+			expectedICAO: "4840D6",
+			expectedBDS:  "0,8",
+		},
+		{
+			name:         "DF17 parity corrupted",
+			msg:          "8D4840D6202CC371C32CE0576099",
+			expectedDF:   df.DF17,
+			expectedCRC:  crcBad,
+			expectedICAO: "4840D6",
+			expectedBDS:  "0,8",
+		},
+		{
+			name:         "DF17 AVR framing",
+			msg:          "*8D4840D6202CC371C32CE0576098;",
+			expectedDF:   df.DF17,
+			expectedCRC:  crcOK,
+			expectedICAO: "4840D6",
+			expectedBDS:  "0,8",
+		},
+		{
+			name:         "DF11 with interrogator identifier",
+			msg:          "5D484FDEA248F5",
+			expectedDF:   df.DF11,
+			expectedCRC:  crcOK,
+			expectedICAO: "484FDE",
+		},
+		{
+			name:         "DF11 corrupted",
+			msg:          "5D484FDFA248F5",
+			expectedDF:   df.DF11,
+			expectedCRC:  crcBad,
+			expectedICAO: "484FDF",
+		},
+		{
+			name:         "DF20 meteorological routine air report",
+			msg:          "A0001692185BD5CF400000DFC696",
+			expectedDF:   df.DF20,
+			expectedCRC:  crcAddressParity,
+			expectedICAO: "3C4DD7",
+			expectedBDS:  "4,4",
+		},
+		{
+			name:         "DF0 address parity",
+			msg:          "02E197B00179C3",
+			expectedDF:   df.DF0,
+			expectedCRC:  crcAddressParity,
+			expectedICAO: "4B18FE",
+		},
+		{
+			name:           "long format, short message",
+			msg:            "8D4840D6202CC3",
+			expectedDF:     df.DF17,
+			expectedErrors: true,
+		},
+		{
+			name:           "short format, long message",
+			msg:            "5D484FDEA248F55D484FDEA248F5",
+			expectedDF:     df.DF11,
+			expectedErrors: true,
+		},
+		{
+			name:           "bad length",
+			msg:            "8D4840D6",
+			expectedErrors: true,
+		},
+		{
+			name:           "not hex",
+			msg:            "8D4840D6202CC371C32CE05760ZZ",
+			expectedErrors: true,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("msg: %s, ", testData.name)
+		msg := decodeHexMessage(testData.msg)
+		assert.Equal(testData.expectedDF, msg.DF, testMsg+"DF")
+		assert.Equal(testData.expectedCRC, msg.CRC, testMsg+"CRC")
+		assert.Equal(testData.expectedICAO, msg.ICAO, testMsg+"ICAO")
+		if testData.expectedBDS != "" {
+			assert.Equal(testData.expectedBDS, msg.BDS, testMsg+"BDS")
+		}
+		if testData.expectedErrors {
+			assert.NotEmpty(msg.Errors, testMsg+"errors")
+			assert.Nil(msg.Message, testMsg+"message")
+		} else {
+			assert.Empty(msg.Errors, testMsg+"errors")
+			assert.NotNil(msg.Message, testMsg+"message")
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		msg          string
+		expectedICAO string
+		expectedRaw  map[string]string // raw message contents
+	}{
+		{msg: "8D4840D6202CC371C32CE0576098", expectedICAO: "4840D6", expectedRaw: map[string]string{"ME": "202CC371C32CE0"}},
+		{msg: "A0001692185BD5CF400000DFC696", expectedICAO: "3C4DD7", expectedRaw: map[string]string{"MB": "185BD5CF400000"}},
+		{msg: "5D484FDEA248F5", expectedICAO: "484FDE"},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("msg: %s, ", testData.msg)
+		var buf bytes.Buffer
+		app := newApp()
+		app.Writer = &buf
+		err := app.Run([]string{"beastdecoder", "decode", "--json", testData.msg})
+		if !assert.NoError(err, testMsg+"decode error") {
+			continue
+		}
+
+		var decoded struct {
+			ICAO    string         `json:"icao"`
+			Message map[string]any `json:"message"`
+		}
+		assert.NoError(json.Unmarshal(buf.Bytes(), &decoded), testMsg+"json")
+		assert.Equal(testData.expectedICAO, decoded.ICAO, testMsg+"icao")
+		assert.Equal(testData.expectedICAO, decoded.Message["ICAO"], testMsg+"message ICAO")
+		for name, expected := range testData.expectedRaw {
+			assert.Equal(expected, decoded.Message[name], testMsg+name)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package dedup
+
+// Duplicate suppression for frames merged from multiple receivers.
+//
+// Receivers in range of the same aircraft hear the same transmissions, so identical frames
+// arrive from each of them within a short time of each other. Frames are held for a window,
+// keyed on their payload, and copies arriving within the window are merged into the first:
+// the copy with the strongest signal is kept, and every receiver that heard the frame is recorded.
+// Counting which receivers hear each frame gives each receiver's coverage, and how much it
+// overlaps with the others.
+
+import (
+	"sync"
+	"time"
+)
+
+// Default time frames are held waiting for copies from other receivers.
+// Aircraft repeat some messages (eg: airborne velocity) every 0.4-0.6 s, so this must be shorter.
+const DefaultWindow = time.Millisecond * 200
+
+type Deduplicator struct {
+	mu sync.Mutex // sync mutex
+
+	window time.Duration // frames are held this long
+
+	pending map[string]*Frame // frames being held, keyed on payload
+	order   []*Frame          // frames being held, in order first received
+
+	frames     uint64 // unique frames flushed
+	duplicates uint64 // copies suppressed, of frames flushed
+	receivers  map[string]*receiverCounts
+}
+
+// A frame heard by one or more receivers
+type Frame struct {
+	Data      []byte
+	Receiver  string // receiver of the best copy
+	RSSIKnown bool
+	RSSI      float64   // dBFS, of the best copy
+	Receivers []string  // every receiver that heard the frame, in order heard
+	Received  time.Time // when the first copy was received
+}
+
+type receiverCounts struct {
+	frames    uint64            // heard by this receiver
+	best      uint64            // best copy was from this receiver
+	exclusive uint64            // heard by no other receiver
+	overlap   map[string]uint64 // also heard by another receiver
+}
+
+// Coverage of each receiver, from the frames flushed so far
+type Coverage struct {
+	Frames     uint64                      `json:"frames"`     // unique frames
+	Duplicates uint64                      `json:"duplicates"` // copies suppressed
+	Receivers  map[string]ReceiverCoverage `json:"receivers"`
+}
+
+type ReceiverCoverage struct {
+	Frames    uint64             `json:"frames"`    // heard by this receiver
+	Best      uint64             `json:"best"`      // best copy was from this receiver
+	Exclusive uint64             `json:"exclusive"` // heard by no other receiver
+	Overlap   map[string]float64 `json:"overlap"`   // fraction of this receiver's frames also heard by each other receiver
+}
+
+func (d *Deduplicator) Init(window time.Duration) {
+	// run once before use
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.window = window
+	d.pending = make(map[string]*Frame)
+	d.receivers = make(map[string]*receiverCounts)
+}
+
+func (d *Deduplicator) Add(f Frame) (duplicate bool) {
+	// holds a frame received at f.Received, or merges it into a copy already held
+	// returns true if the frame is a copy of one already held
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := string(f.Data)
+	held, ok := d.pending[key]
+	if ok {
+		for _, receiver := range held.Receivers {
+			if receiver == f.Receiver {
+				// receivers don't repeat frames, so this is a new transmission with the same payload
+				ok = false
+				break
+			}
+		}
+	}
+
+	if !ok {
+		f.Receivers = []string{f.Receiver}
+		d.pending[key] = &f
+		d.order = append(d.order, &f)
+		return false
+	}
+
+	held.Receivers = append(held.Receivers, f.Receiver)
+	if f.RSSIKnown && (!held.RSSIKnown || f.RSSI > held.RSSI) {
+		held.Receiver = f.Receiver
+		held.RSSIKnown = true
+		held.RSSI = f.RSSI
+	}
+	return true
+}
+
+func (d *Deduplicator) Flush(now time.Time) (frames []Frame) {
+	// returns frames held for the window as of now, in order first received
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := 0
+	for _, f := range d.order {
+		if now.Sub(f.Received) < d.window {
+			break
+		}
+		if d.pending[string(f.Data)] == f {
+			delete(d.pending, string(f.Data))
+		}
+		d.count(f)
+		frames = append(frames, *f)
+		n++
+	}
+	d.order = d.order[n:]
+
+	return frames
+}
+
+func (d *Deduplicator) count(f *Frame) {
+	// counts which receivers heard a frame
+	d.frames++
+	d.duplicates += uint64(len(f.Receivers) - 1)
+	for _, receiver := range f.Receivers {
+		c := d.counts(receiver)
+		c.frames++
+		if len(f.Receivers) == 1 {
+			c.exclusive++
+		}
+		for _, other := range f.Receivers {
+			if other != receiver {
+				c.overlap[other]++
+			}
+		}
+	}
+	d.counts(f.Receiver).best++
+}
+
+func (d *Deduplicator) counts(receiver string) *receiverCounts {
+	c, ok := d.receivers[receiver]
+	if !ok {
+		c = &receiverCounts{
+			overlap: make(map[string]uint64),
+		}
+		d.receivers[receiver] = c
+	}
+	return c
+}
+
+func (d *Deduplicator) Coverage() Coverage {
+	// returns each receiver's coverage, from the frames flushed so far
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	coverage := Coverage{
+		Frames:     d.frames,
+		Duplicates: d.duplicates,
+		Receivers:  make(map[string]ReceiverCoverage),
+	}
+	for receiver, c := range d.receivers {
+		rc := ReceiverCoverage{
+			Frames:    c.frames,
+			Best:      c.best,
+			Exclusive: c.exclusive,
+			Overlap:   make(map[string]float64),
+		}
+		for other, n := range c.overlap {
+			rc.Overlap[other] = float64(n) / float64(c.frames)
+		}
+		coverage.Receivers[receiver] = rc
+	}
+	return coverage
+}
@@ -0,0 +1,145 @@
+package dedup
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testVelocity = []byte{0x8d, 0x48, 0x50, 0x20, 0x99, 0x44, 0x09, 0x94, 0x08, 0x38, 0x17, 0x5b, 0x28, 0x4f}
+var testIdentification = []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe0, 0x57, 0x60, 0x98}
+
+func TestDeduplicator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// define test data
+	var testTable = []struct {
+		name              string
+		frames            []Frame
+		expectedFrames    int
+		expectedReceiver  string   // of the first frame
+		expectedRSSI      float64  // of the first frame
+		expectedReceivers []string // of the first frame
+	}{
+		{
+			name: "single receiver",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now},
+			},
+			expectedFrames:    1,
+			expectedReceiver:  "a",
+			expectedRSSI:      -20,
+			expectedReceivers: []string{"a"},
+		},
+		{
+			name: "copies keep best RSSI",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now},
+				{Data: testVelocity, Receiver: "b", RSSIKnown: true, RSSI: -10, Received: now.Add(time.Millisecond * 50)},
+				{Data: testVelocity, Receiver: "c", RSSIKnown: true, RSSI: -30, Received: now.Add(time.Millisecond * 100)},
+			},
+			expectedFrames:    1,
+			expectedReceiver:  "b",
+			expectedRSSI:      -10,
+			expectedReceivers: []string{"a", "b", "c"},
+		},
+		{
+			name: "copy with unknown RSSI",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", Received: now},
+				{Data: testVelocity, Receiver: "b", RSSIKnown: true, RSSI: -30, Received: now.Add(time.Millisecond * 50)},
+			},
+			expectedFrames:    1,
+			expectedReceiver:  "b",
+			expectedRSSI:      -30,
+			expectedReceivers: []string{"a", "b"},
+		},
+		{
+			name: "different payloads",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now},
+				{Data: testIdentification, Receiver: "b", RSSIKnown: true, RSSI: -10, Received: now},
+			},
+			expectedFrames:    2,
+			expectedReceiver:  "a",
+			expectedRSSI:      -20,
+			expectedReceivers: []string{"a"},
+		},
+		{
+			name: "repeated by the same receiver",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now},
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -10, Received: now.Add(time.Millisecond * 100)},
+			},
+			expectedFrames:    2,
+			expectedReceiver:  "a",
+			expectedRSSI:      -20,
+			expectedReceivers: []string{"a"},
+		},
+		{
+			name: "outside window",
+			frames: []Frame{
+				{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now},
+				{Data: testVelocity, Receiver: "b", RSSIKnown: true, RSSI: -10, Received: now.Add(DefaultWindow)},
+			},
+			expectedFrames:    2,
+			expectedReceiver:  "a",
+			expectedRSSI:      -20,
+			expectedReceivers: []string{"a"},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("test: %s, ", testData.name)
+
+		var d Deduplicator
+		d.Init(DefaultWindow)
+		var frames []Frame
+		for _, f := range testData.frames {
+			frames = append(frames, d.Flush(f.Received)...)
+			d.Add(f)
+		}
+		assert.Empty(d.Flush(testData.frames[len(testData.frames)-1].Received.Add(-time.Millisecond)), testMsg+"flushed early")
+		frames = append(frames, d.Flush(now.Add(time.Second))...)
+
+		if assert.Len(frames, testData.expectedFrames, testMsg+"frames") {
+			assert.Equal(testData.expectedReceiver, frames[0].Receiver, testMsg+"Receiver")
+			assert.Equal(testData.expectedRSSI, frames[0].RSSI, testMsg+"RSSI")
+			assert.Equal(testData.expectedReceivers, frames[0].Receivers, testMsg+"Receivers")
+		}
+		assert.Empty(d.Flush(now.Add(time.Second)), testMsg+"flushed twice")
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var d Deduplicator
+	d.Init(DefaultWindow)
+
+	// heard by a & b, then by a only
+	assert.False(d.Add(Frame{Data: testVelocity, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now}))
+	assert.True(d.Add(Frame{Data: testVelocity, Receiver: "b", RSSIKnown: true, RSSI: -10, Received: now}))
+	assert.False(d.Add(Frame{Data: testIdentification, Receiver: "a", RSSIKnown: true, RSSI: -20, Received: now}))
+	d.Flush(now.Add(time.Second))
+
+	coverage := d.Coverage()
+	assert.Equal(uint64(2), coverage.Frames)
+	assert.Equal(uint64(1), coverage.Duplicates)
+
+	a := coverage.Receivers["a"]
+	assert.Equal(uint64(2), a.Frames)
+	assert.Equal(uint64(1), a.Best)
+	assert.Equal(uint64(1), a.Exclusive)
+	assert.Equal(0.5, a.Overlap["b"])
+
+	b := coverage.Receivers["b"]
+	assert.Equal(uint64(1), b.Frames)
+	assert.Equal(uint64(1), b.Best)
+	assert.Equal(uint64(0), b.Exclusive)
+	assert.Equal(1.0, b.Overlap["a"])
+}
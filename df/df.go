@@ -3,7 +3,6 @@ package df
 import (
 	"beastdecoder/common"
 	"errors"
-	"fmt"
 
 	"github.com/rs/zerolog/log"
 )
@@ -26,20 +25,20 @@ const DF20 = DownlinkFormat(20)
 const DF21 = DownlinkFormat(21)
 const DF24 = DownlinkFormat(24)
 
-func airborneFromFlightStatus(fs int) (airborne bool, err error) {
+func airborneFromFlightStatus(fs int) (airborne bool, known bool, err error) {
+	// returns airborne status, known is false when the flight status doesn't show it
+	//  4, 5 = alert & SPI, airborne or on-ground
 	switch fs {
 	case 0b000:
-		airborne = true
+		airborne, known = true, true
 	case 0b001:
-		airborne = false
+		airborne, known = false, true
 	case 0b010:
-		airborne = true
+		airborne, known = true, true
 	case 0b011:
-		airborne = false
+		airborne, known = false, true
 	case 0b100:
-		airborne = false
 	case 0b101:
-		airborne = false
 	case 0b110:
 		err = errors.New("flight status set to reserved")
 	case 0b111:
@@ -207,28 +206,13 @@ func icaoFromCRC(data []byte) (icao int) {
 	return icao
 }
 
+func CRCRemainder(data []byte) int {
+	// Returns the parity remainder of a message: zero for an intact message with plain parity (DF17/18),
+	// the interrogator identifier for DF11, or the ICAO address for messages with address parity
+	return icaoFromCRC(data)
+}
+
 func squawkFromIdentityCode(id int) (squawk int, err error) {
 	// returns a squawk code from an Identity code (DF5)
-	// https://mode-s.org/decode/content/mode-s/3-surveillance.html#sec:id_code
-
-	// The 13-bit identity code encodes the 4 octal digit squawk code (from 0000 to 7777). The structure of this field is shown as follows:
-
-	// +----+----+----+----+----+----+---+----+----+----+----+----+----+
-	// | C1 | A1 | C2 | A2 | C4 | A4 | X | B1 | D1 | B2 | D2 | B4 | D4 |
-	// +----+----+----+----+----+----+---+----+----+----+----+----+----+
-	// The binary representation of the octal digit is:
-
-	// A4 A2 A1 | B4 B2 B1 | C4 C2 C1 | D4 D2 D1
-
-	a := (((id & 0b0000010000000) >> 5) + ((id & 0b0001000000000) >> 8) + ((id & 0b0100000000000) >> 11)) * 1000
-	b := (((id & 0b0000000000010) << 1) + ((id & 0b0000000001000) >> 2) + ((id & 0b0000000100000) >> 5)) * 100
-	c := (((id & 0b0000100000000) >> 6) + ((id & 0b0010000000000) >> 9) + ((id & 0b1000000000000) >> 12)) * 10
-	d := (((id & 0b0000000000001) << 2) + ((id & 0b0000000000100) >> 1) + ((id & 0b0000000010000) >> 4))
-
-	squawk = a + b + c + d
-	if squawk < 0 || squawk >= 10000 {
-		err = errors.New(fmt.Sprintf("invalid squawk code: %d", squawk))
-	}
-
-	return
+	return common.SquawkFromIdentityCode(id)
 }
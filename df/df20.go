@@ -8,10 +8,11 @@ type DF20message struct {
 	ac int    // Altitude code
 	p  []byte // Parity
 
-	Airborne bool
-	Altitude float64
-	ICAO     int    // Address announced: The address refers to the 24-bit transponder address (icao).
-	MB       []byte // Message, Comm-B
+	Airborne      bool
+	AirborneKnown bool // false when the flight status doesn't show airborne status
+	Altitude      float64
+	ICAO          int    // Address announced: The address refers to the 24-bit transponder address (icao).
+	MB            []byte // Message, Comm-B
 }
 
 func DecodeDF20(data []byte) (msg DF20message, err error) {
@@ -29,7 +30,7 @@ func DecodeDF20(data []byte) (msg DF20message, err error) {
 	msg.MB = []byte{data[4], data[5], data[6], data[7], data[8], data[9], data[10]}
 	msg.p = []byte{data[11], data[12], data[13]}
 	msg.ICAO = icaoFromCRC(data)
-	msg.Airborne, err = airborneFromFlightStatus(msg.fs)
+	msg.Airborne, msg.AirborneKnown, err = airborneFromFlightStatus(msg.fs)
 	if msg.ac != 0 {
 		msg.Altitude, err = altitudeFromAltitudeCode13bit(msg.ac)
 	}
@@ -8,10 +8,11 @@ type DF21message struct {
 	id int    // Identity code
 	p  []byte // Parity
 
-	Airborne bool
-	ICAO     int // Address announced: The address refers to the 24-bit transponder address (icao).
-	Squawk   int
-	MB       []byte // Message, Comm-B
+	Airborne      bool
+	AirborneKnown bool // false when the flight status doesn't show airborne status
+	ICAO          int  // Address announced: The address refers to the 24-bit transponder address (icao).
+	Squawk        int
+	MB            []byte // Message, Comm-B
 }
 
 func DecodeDF21(data []byte) (msg DF21message, err error) {
@@ -31,7 +32,7 @@ func DecodeDF21(data []byte) (msg DF21message, err error) {
 	msg.p = []byte{data[11], data[12], data[13]}
 	msg.ICAO = icaoFromCRC(data)
 
-	msg.Airborne, err = airborneFromFlightStatus(msg.fs)
+	msg.Airborne, msg.AirborneKnown, err = airborneFromFlightStatus(msg.fs)
 	if err != nil {
 		return
 	}
@@ -11,9 +11,10 @@ type DF4message struct {
 	ac int    // Altitude Code (AC): Encodes the altitude of the aircraft.
 	ap []byte // Address parity bytes
 
-	Airborne bool    // airborne status
-	Altitude float64 // decoded Altitude
-	ICAO     int     // ICAO aircraft address
+	Airborne      bool    // airborne status
+	AirborneKnown bool    // false when the flight status doesn't show airborne status
+	Altitude      float64 // decoded Altitude
+	ICAO          int     // ICAO aircraft address
 }
 
 func DecodeDF4(data []byte) (msg DF4message, err error) {
@@ -49,7 +50,7 @@ func DecodeDF4(data []byte) (msg DF4message, err error) {
 	msg.ICAO = icaoFromCRC(data)
 
 	// set airborne based on Flight Status bits
-	msg.Airborne, err = airborneFromFlightStatus(msg.fs)
+	msg.Airborne, msg.AirborneKnown, err = airborneFromFlightStatus(msg.fs)
 
 	// set decoded altitude
 	msg.Altitude, err = altitudeFromAltitudeCode13bit(msg.ac)
@@ -11,9 +11,10 @@ type DF5message struct {
 	id int    // Identity code (ID): The 13-bit identity code encodes the 4 octal digit squawk code (from 0000 to 7777).
 	ap []byte // Address parity bytes
 
-	Airborne bool
-	Squawk   int
-	ICAO     int // ICAO aircraft address
+	Airborne      bool
+	AirborneKnown bool // false when the flight status doesn't show airborne status
+	Squawk        int
+	ICAO          int // ICAO aircraft address
 }
 
 func DecodeDF5(data []byte) (msg DF5message, err error) {
@@ -55,7 +56,7 @@ func DecodeDF5(data []byte) (msg DF5message, err error) {
 	}
 
 	// set airborne based on Flight Status bits
-	msg.Airborne, err = airborneFromFlightStatus(msg.fs)
+	msg.Airborne, msg.AirborneKnown, err = airborneFromFlightStatus(msg.fs)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,78 @@
+package df
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRCRemainder(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		data              []byte
+		expectedRemainder int
+	}{
+		{
+			// DF17, intact
+			data:              []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe0, 0x57, 0x60, 0x98},
+			expectedRemainder: 0,
+		},
+		{
+			// DF17, last byte of ME corrupted
+			data:              []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe1, 0x57, 0x60, 0x98},
+			expectedRemainder: 0xfff409,
+		},
+		{
+			// DF20, address parity
+			data:              []byte{0xa0, 0x00, 0x02, 0xbf, 0x10, 0x02, 0x0a, 0x80, 0xf0, 0x00, 0x00, 0x1b, 0x43, 0x5f},
+			expectedRemainder: 0x7CF9DA,
+		},
+		{
+			// DF11, interrogator identifier overlaid on parity
+			data:              []byte{0x5d, 0x48, 0x4f, 0xde, 0xa2, 0x48, 0xf5},
+			expectedRemainder: 0x16,
+		},
+		{
+			// DF11, address corrupted
+			data:              []byte{0x5d, 0x48, 0x4f, 0xdf, 0xa2, 0x48, 0xf5},
+			expectedRemainder: 0xfff41f,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("data: %014x, ", testData.data)
+		assert.Equal(testData.expectedRemainder, CRCRemainder(testData.data), testMsg+"remainder")
+	}
+}
+
+func TestAirborneFromFlightStatus(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		fs               int
+		expectedAirborne bool
+		expectedKnown    bool
+		expectedErr      bool
+	}{
+		{fs: 0, expectedAirborne: true, expectedKnown: true},
+		{fs: 1, expectedAirborne: false, expectedKnown: true},
+		{fs: 2, expectedAirborne: true, expectedKnown: true},
+		{fs: 3, expectedAirborne: false, expectedKnown: true},
+		{fs: 4, expectedKnown: false},
+		{fs: 5, expectedKnown: false},
+		{fs: 6, expectedKnown: false, expectedErr: true},
+		{fs: 7, expectedKnown: false, expectedErr: true},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("fs: %d, ", testData.fs)
+		airborne, known, err := airborneFromFlightStatus(testData.fs)
+		assert.Equal(testData.expectedKnown, known, testMsg+"known")
+		if testData.expectedKnown {
+			assert.Equal(testData.expectedAirborne, airborne, testMsg+"airborne")
+		}
+		assert.Equal(testData.expectedErr, err != nil, testMsg+"err")
+	}
+}
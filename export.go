@@ -0,0 +1,415 @@
+package main
+
+import (
+	"beastdecoder/bds"
+	"beastdecoder/df"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Capture formats
+const captureFormatAuto = "auto"
+const captureFormatBEAST = "beast"
+const captureFormatAVR = "avr"
+
+// Export formats
+const exportFormatJSONL = "jsonl"
+const exportFormatCSV = "csv"
+
+// Frequency of the receiver's MLAT timestamp counter (Hz)
+const mlatClockFrequency = 12e6
+
+// A message read from a capture
+type capturedMessage struct {
+	timestampKnown bool
+	timestamp      float64 // seconds, from the receiver's MLAT clock
+	rssiKnown      bool
+	rssi           float64 // dBFS
+	data           []byte
+}
+
+// A decoded message, as exported
+type exportRecord struct {
+	Timestamp *float64          `json:"timestamp"` // seconds, from the receiver's MLAT clock
+	RSSI      *float64          `json:"rssi"`      // dBFS
+	Hex       string            `json:"hex"`
+	DF        df.DownlinkFormat `json:"df"`
+	CRC       crcStatus         `json:"crc"`
+	ICAO      string            `json:"icao"`
+	BDS       string            `json:"bds"`
+	Fields    map[string]any    `json:"fields"` // decoded fields of the downlink format and register
+	Errors    []string          `json:"errors"`
+}
+
+// Decoded downlink formats & registers, each of their fields is a CSV column
+var exportCSVFrames = []any{
+	df.DF0message{}, df.DF4message{}, df.DF5message{}, df.DF11message{}, df.DF16message{},
+	df.DF17message{}, df.DF18message{}, df.DF20message{}, df.DF21message{},
+	bds.BDS05Frame{}, bds.BDS06Frame{}, bds.BDS07Frame{}, bds.BDS08Frame{}, bds.BDS09Frame{},
+	bds.BDS10Frame{}, bds.BDS17Frame{}, bds.BDS20Frame{}, bds.BDS40Frame{}, bds.BDS44Frame{}, bds.BDS45Frame{},
+	bds.BDS50Frame{}, bds.BDS53Frame{}, bds.BDS54Frame{}, bds.BDS55Frame{}, bds.BDS56Frame{},
+	bds.BDS60Frame{}, bds.BDS61Frame{}, bds.BDS62Frame{}, bds.BDS65Frame{},
+}
+
+// CSV columns either side of the decoded fields
+var exportCSVHeader = []string{"timestamp", "rssi", "hex", "df", "crc", "icao", "bds"}
+var exportCSVTrailer = []string{"errors"}
+
+// Decoded field columns, the union of the fields of every downlink format & register
+var exportCSVFields = csvFieldColumns(exportCSVFrames)
+
+func export(ctx *cli.Context) error {
+	// decodes a BEAST or AVR capture, writing one record per message
+
+	initLogger(ctx)
+
+	// stop reading on SIGINT/SIGTERM, writing what was decoded so far
+	sigCtx, stop := notifyContext(ctx)
+	defer stop()
+
+	in, _, err := openCapture(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	r := bufio.NewReader(in)
+
+	out, err := createOutput(ctx, ctx.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	var write func(record exportRecord) error
+	switch ctx.String("format") {
+	case exportFormatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(record exportRecord) error {
+			return enc.Encode(record)
+		}
+	case exportFormatCSV:
+		cw := csv.NewWriter(w)
+		defer cw.Flush()
+		header := append(append(append([]string{}, exportCSVHeader...), exportCSVFields...), exportCSVTrailer...)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		write = func(record exportRecord) error {
+			row, err := record.csvRow()
+			if err != nil {
+				return err
+			}
+			return cw.Write(row)
+		}
+	default:
+		return fmt.Errorf("unknown export format (format: %s)", ctx.String("format"))
+	}
+
+	// decode
+	count, skipped := 0, 0
+	handle := func(captured capturedMessage) error {
+		if err := sigCtx.Err(); err != nil {
+			return err
+		}
+		msg := decodeMessage(captured.data)
+		if ctx.Bool("drop-bad-crc") && msg.CRC == crcBad {
+			skipped++
+			return nil
+		}
+		record := newExportRecord(captured, msg)
+		if err := write(record); err != nil {
+			// eg: a field that can't be represented in JSON
+			log.Warn().AnErr("err", err).Str("data", record.Hex).Msg("could not export message")
+			skipped++
+			return nil
+		}
+		count++
+		return nil
+	}
+
+	format := ctx.String("input-format")
+	if format == captureFormatAuto {
+		format = detectCaptureFormat(r)
+	}
+	switch format {
+	case captureFormatBEAST:
+		err = readBEASTCapture(r, handle)
+	case captureFormatAVR:
+		err = readAVRCapture(r, handle)
+	default:
+		err = fmt.Errorf("unknown capture format (format: %s)", format)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		log.Warn().Msg("interrupted, writing messages decoded so far")
+		err = nil
+	}
+
+	log.Info().Int("exported", count).Int("skipped", skipped).Msg("export complete")
+	return err
+}
+
+// Output to stdout, which isn't closed
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func openCapture(path string) (in io.ReadCloser, modTime time.Time, err error) {
+	// opens a capture file, or stdin if path is empty or "-"
+	// modTime is when the file was last modified, or now for stdin
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), time.Now(), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, modTime, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, modTime, err
+	}
+	return f, info.ModTime(), nil
+}
+
+func createOutput(ctx *cli.Context, path string) (io.WriteCloser, error) {
+	// creates an output file, or writes to stdout if path is empty or "-"
+	if path == "" || path == "-" {
+		return nopWriteCloser{ctx.App.Writer}, nil
+	}
+	return os.Create(path)
+}
+
+func newExportRecord(captured capturedMessage, msg decodedMessage) (record exportRecord) {
+	if captured.timestampKnown {
+		record.Timestamp = &captured.timestamp
+	}
+	if captured.rssiKnown {
+		record.RSSI = &captured.rssi
+	}
+	record.Hex = msg.Hex
+	record.DF = msg.DF
+	record.CRC = msg.CRC
+	record.ICAO = msg.ICAO
+	record.BDS = msg.BDS
+	if msg.Message != nil {
+		record.Fields = msg.flatten()
+	}
+	record.Errors = msg.Errors
+	return record
+}
+
+func (record *exportRecord) csvRow() (row []string, err error) {
+	// fields differ between registers, so each has its own column, empty if the message doesn't have it
+	values := make(map[string]string)
+	for name, value := range record.Fields {
+		addCSVValues(values, name, reflect.ValueOf(value))
+	}
+
+	row = []string{"", "", record.Hex, strconv.Itoa(int(record.DF)), string(record.CRC), record.ICAO, record.BDS}
+	if record.Timestamp != nil {
+		row[0] = strconv.FormatFloat(*record.Timestamp, 'f', -1, 64)
+	}
+	if record.RSSI != nil {
+		row[1] = strconv.FormatFloat(*record.RSSI, 'f', 1, 64)
+	}
+	for _, column := range exportCSVFields {
+		row = append(row, values[column])
+		delete(values, column)
+	}
+	for column := range values {
+		return nil, fmt.Errorf("decoded field has no CSV column (field: %s)", column)
+	}
+	row = append(row, strings.Join(record.Errors, "; "))
+	return row, nil
+}
+
+func csvFieldColumns(frames []any) (columns []string) {
+	// returns the exported fields of each frame, in order of first appearance
+	seen := make(map[string]bool)
+	var add func(name string, t reflect.Type)
+	add = func(name string, t reflect.Type) {
+		// nested structs are flattened, eg: TcasRA.ARA
+		if t.Kind() == reflect.Struct {
+			for i := 0; i < t.NumField(); i++ {
+				if t.Field(i).IsExported() {
+					add(name+"."+t.Field(i).Name, t.Field(i).Type)
+				}
+			}
+			return
+		}
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+	for _, frame := range frames {
+		t := reflect.TypeOf(frame)
+		for i := 0; i < t.NumField(); i++ {
+			// included as columns of their own, or raw message contents
+			name := t.Field(i).Name
+			if !t.Field(i).IsExported() || name == "ICAO" || name == "ME" || name == "MB" {
+				continue
+			}
+			add(name, t.Field(i).Type)
+		}
+	}
+	return columns
+}
+
+func addCSVValues(values map[string]string, name string, v reflect.Value) {
+	// formats a field for CSV, flattening nested structs as csvFieldColumns does
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				addCSVValues(values, name+"."+v.Type().Field(i).Name, v.Field(i))
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		values[name] = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		b, _ := json.Marshal(v.Interface())
+		values[name] = string(b)
+	default:
+		values[name] = fmt.Sprint(v.Interface())
+	}
+}
+
+func detectCaptureFormat(r *bufio.Reader) string {
+	// BEAST frames start with an escape byte, AVR is text
+	b, err := r.Peek(1)
+	if err == nil && b[0] == 0x1a {
+		return captureFormatBEAST
+	}
+	return captureFormatAVR
+}
+
+func readBEASTCapture(r io.Reader, handle func(capturedMessage) error) error {
+	// reads Mode-S frames from BEAST data until EOF
+
+	var frameType byte
+	var err error
+	buf := make([]byte, 2)
+
+	// a capture normally starts on a frame boundary
+	synced := true
+	for {
+		if !synced {
+			frameType, err = beastSync(r)
+			if err != nil {
+				break
+			}
+			synced = true
+		} else {
+			_, err = io.ReadFull(r, buf)
+			if err != nil {
+				break
+			}
+			if buf[0] != 0x1a {
+				synced = false
+				continue
+			}
+			frameType = buf[1]
+		}
+
+		// Mode-S short & long frames only
+		if frameType != 0x32 && frameType != 0x33 {
+			synced = false
+			continue
+		}
+
+		frameData, err := beastReadFrame(r, frameType)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			// escaping error, resynchronise
+			synced = false
+			continue
+		}
+
+		if err := handle(beastMessage(frameType, frameData)); err != nil {
+			return err
+		}
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil
+	}
+	return err
+}
+
+func readAVRCapture(r io.Reader, handle func(capturedMessage) error) error {
+	// reads AVR format lines until EOF, either *<message>; or @<timestamp><message>;
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var captured capturedMessage
+		var err error
+		if strings.HasPrefix(line, "@") && len(line) > 13 {
+			// 6 byte MLAT timestamp precedes the message
+			var ts []byte
+			ts, err = hex.DecodeString(line[1:13])
+			if err == nil {
+				captured.timestampKnown = true
+				captured.timestamp = mlatSeconds(ts)
+				captured.data, err = parseHexMessage(line[13:])
+			}
+		} else {
+			captured.data, err = parseHexMessage(line)
+		}
+		if err != nil {
+			log.Debug().AnErr("err", err).Str("line", line).Msg("skipping unparseable line")
+			continue
+		}
+
+		if err := handle(captured); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func mlatSeconds(mlatTimestamp []byte) float64 {
+	// converts a 6 byte MLAT timestamp to seconds since the receiver's clock started
+	var ticks uint64
+	for _, b := range mlatTimestamp {
+		ticks = ticks<<8 + uint64(b)
+	}
+	return float64(ticks) / mlatClockFrequency
+}
+
+func rssiDBFS(rssi byte) (dbfs float64, ok bool) {
+	// converts a BEAST signal level (0-255, square root of power) to dBFS
+	if rssi == 0 {
+		return 0, false
+	}
+	level := float64(rssi) / 255
+	return 10 * math.Log10(level*level), true
+}
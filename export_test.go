@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// identification, ICAO 4840D6
+var testIdentification = []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe0, 0x57, 0x60, 0x98}
+
+// all call reply, ICAO 4840D6
+var testAllCall = []byte{0x5d, 0x48, 0x40, 0xd6, 0x10, 0x2d, 0x1d}
+
+func testBEASTFrame(frameType byte, ticks uint64, rssi byte, data []byte) []byte {
+	// returns a BEAST frame, escaping 0x1a
+	frame := []byte{0x1a, frameType}
+	payload := make([]byte, 0, 7+len(data))
+	for i := 5; i >= 0; i-- {
+		payload = append(payload, byte(ticks>>(8*i)))
+	}
+	payload = append(payload, rssi)
+	payload = append(payload, data...)
+	for _, b := range payload {
+		frame = append(frame, b)
+		if b == 0x1a {
+			frame = append(frame, 0x1a)
+		}
+	}
+	return frame
+}
+
+func TestReadBEASTCapture(t *testing.T) {
+	long := testBEASTFrame(0x33, 12e6, 255, testIdentification)
+	short := testBEASTFrame(0x32, 24e6, 0, testAllCall)
+
+	// define test data
+	var testTable = []struct {
+		name               string
+		capture            []byte
+		expectedData       [][]byte
+		expectedTimestamps []float64
+	}{
+		{
+			name:               "long & short frames",
+			capture:            append(append([]byte{}, long...), short...),
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{1, 2},
+		},
+		{
+			name:               "escaped timestamp",
+			capture:            testBEASTFrame(0x33, 0x1a1a1a, 255, testIdentification),
+			expectedData:       [][]byte{testIdentification},
+			expectedTimestamps: []float64{float64(0x1a1a1a) / mlatClockFrequency},
+		},
+		{
+			name:               "resync after garbage",
+			capture:            append([]byte{0x00, 0x01, 0x02}, long...),
+			expectedData:       [][]byte{testIdentification},
+			expectedTimestamps: []float64{1},
+		},
+		{
+			name:               "mode-ac frame skipped",
+			capture:            append(append([]byte{0x1a, 0x31, 0, 0, 0, 0, 0, 1, 0x80, 0x12, 0x34}, long...), short...),
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{1, 2},
+		},
+		{
+			name:               "bad escape resyncs",
+			capture:            append(append([]byte{0x1a, 0x33, 0, 0, 0x1a, 0x00, 0xff}, long...), short...),
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{1, 2},
+		},
+		{
+			name:               "truncated frame",
+			capture:            append(append([]byte{}, long...), short[:8]...),
+			expectedData:       [][]byte{testIdentification},
+			expectedTimestamps: []float64{1},
+		},
+		{
+			name:    "empty",
+			capture: []byte{},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("capture: %s, ", testData.name)
+		var data [][]byte
+		var timestamps []float64
+		err := readBEASTCapture(bytes.NewReader(testData.capture), func(captured capturedMessage) error {
+			assert.True(captured.timestampKnown, testMsg+"timestampKnown")
+			data = append(data, captured.data)
+			timestamps = append(timestamps, captured.timestamp)
+			return nil
+		})
+		assert.NoError(err, testMsg+"error")
+		assert.Equal(testData.expectedData, data, testMsg+"data")
+		assert.Equal(testData.expectedTimestamps, timestamps, testMsg+"timestamps")
+	}
+}
+
+func TestReadAVRCapture(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name               string
+		capture            string
+		expectedData       [][]byte
+		expectedTimestamps []float64 // -1 if unknown
+	}{
+		{
+			name:               "plain",
+			capture:            "*8D4840D6202CC371C32CE0576098;\n*5D4840D6102D1D;\n",
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{-1, -1},
+		},
+		{
+			name:               "timestamped",
+			capture:            "@000000B71B008D4840D6202CC371C32CE0576098;\n@0000016E36005D4840D6102D1D;\n",
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{1, 2},
+		},
+		{
+			name:               "blank & unparseable lines skipped",
+			capture:            "\n  *8D4840D6202CC371C32CE0576098;  \nnot a message\n*8D48;\n@0000016E3600zz;\n*5D4840D6102D1D;",
+			expectedData:       [][]byte{testIdentification, testAllCall},
+			expectedTimestamps: []float64{-1, -1},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("capture: %s, ", testData.name)
+		var data [][]byte
+		var timestamps []float64
+		err := readAVRCapture(strings.NewReader(testData.capture), func(captured capturedMessage) error {
+			data = append(data, captured.data)
+			if captured.timestampKnown {
+				timestamps = append(timestamps, captured.timestamp)
+			} else {
+				timestamps = append(timestamps, -1)
+			}
+			return nil
+		})
+		assert.NoError(err, testMsg+"error")
+		assert.Equal(testData.expectedData, data, testMsg+"data")
+		assert.Equal(testData.expectedTimestamps, timestamps, testMsg+"timestamps")
+	}
+}
+
+func TestDetectCaptureFormat(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(captureFormatBEAST, detectCaptureFormat(bufioReader(testBEASTFrame(0x33, 0, 0, testIdentification))))
+	assert.Equal(captureFormatAVR, detectCaptureFormat(bufioReader([]byte("*8D4840D6202CC371C32CE0576098;\n"))))
+	assert.Equal(captureFormatAVR, detectCaptureFormat(bufioReader(nil)))
+}
+
+func TestMlatSeconds(t *testing.T) {
+	var testTable = []struct {
+		timestamp       []byte
+		expectedSeconds float64
+	}{
+		{timestamp: []byte{0, 0, 0, 0, 0, 0}, expectedSeconds: 0},
+		{timestamp: []byte{0, 0, 0, 0xb7, 0x1b, 0x00}, expectedSeconds: 1},
+		{timestamp: []byte{0, 0, 0, 0x5b, 0x8d, 0x80}, expectedSeconds: 0.5},
+		{timestamp: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, expectedSeconds: float64(1<<48-1) / mlatClockFrequency},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("timestamp: %x, ", testData.timestamp)
+		assert.Equal(testData.expectedSeconds, mlatSeconds(testData.timestamp), testMsg+"seconds")
+	}
+}
+
+func TestRSSIDBFS(t *testing.T) {
+	var testTable = []struct {
+		rssi          byte
+		expectedKnown bool
+		expectedDBFS  float64
+	}{
+		{rssi: 0, expectedKnown: false},
+		{rssi: 255, expectedKnown: true, expectedDBFS: 0},
+		{rssi: 128, expectedKnown: true, expectedDBFS: -6.0},
+		{rssi: 26, expectedKnown: true, expectedDBFS: -19.8},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("rssi: %d, ", testData.rssi)
+		dbfs, ok := rssiDBFS(testData.rssi)
+		assert.Equal(testData.expectedKnown, ok, testMsg+"known")
+		if testData.expectedKnown {
+			assert.Equal(testData.expectedDBFS, math.Round(dbfs*10)/10, testMsg+"dBFS")
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		msg            string
+		expectedFields map[string]string // non-empty decoded field columns
+	}{
+		{
+			msg:            "8D4840D6202CC371C32CE0576098",
+			expectedFields: map[string]string{"Tc": "4", "CA": "0", "Category": "A0", "Callsign": "KLM1023"},
+		},
+		{
+			msg: "A000029C85E42F313000007047D3",
+			expectedFields: map[string]string{
+				"Airborne": "true", "AirborneKnown": "true", "Altitude": "3300",
+				"McpFcuSelectedAltitudeValid": "true", "McpFcuSelectedAltitude": "3008",
+				"FmsSelectedAltitudeValid": "true", "FmsSelectedAltitude": "188",
+				"BarometricPressureSettingValid": "true", "BarometricPressureSetting": "1020",
+				"McpFcuModeValid": "false", "McpFcuMode.VnavMode": "false", "McpFcuMode.AltHoldMode": "false", "McpFcuMode.ApproachMode": "false",
+				"TargetAltitudeSourceValid": "false", "TargetAltitudeSource": "0",
+			},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("msg: %s, ", testData.msg)
+		path := filepath.Join(t.TempDir(), "capture.avr")
+		if err := os.WriteFile(path, []byte("*"+testData.msg+";\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		app := newApp()
+		app.Writer = &out
+		if err := app.Run([]string{"beastdecoder", "export", "--format", "csv", path}); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := csv.NewReader(&out).ReadAll()
+		assert.NoError(err, testMsg+"csv")
+		if !assert.Len(rows, 2, testMsg+"rows") {
+			continue
+		}
+		fields := make(map[string]string)
+		for i, column := range rows[0][len(exportCSVHeader) : len(rows[0])-len(exportCSVTrailer)] {
+			if value := rows[1][len(exportCSVHeader)+i]; value != "" {
+				fields[column] = value
+			}
+		}
+		assert.Equal(testData.msg, rows[1][2], testMsg+"hex")
+		assert.Equal(testData.expectedFields, fields, testMsg+"fields")
+	}
+}
+
+func bufioReader(data []byte) *bufio.Reader {
+	return bufio.NewReader(bytes.NewReader(data))
+}
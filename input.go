@@ -0,0 +1,331 @@
+package main
+
+import (
+	"beastdecoder/dedup"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Reconnection backoff, doubled after each failed attempt & reset once a connection is receiving
+const reconnectBackoffMin = time.Second
+const reconnectBackoffMax = time.Minute
+
+// Messages from all inputs are merged into a queue of this length
+const messageQueueLength = 1024
+
+// Held frames are checked this often
+const dedupFlushInterval = time.Millisecond * 50
+
+// A message received from an input
+type receivedMessage struct {
+	capturedMessage
+	input string // address of the input
+}
+
+// Wait between connection attempts
+type reconnectBackoff struct {
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+// A host:port providing BEAST data
+type beastInput struct {
+	addr    string
+	backoff reconnectBackoff
+	bytes   atomic.Uint64 // received over all connections
+	frames  atomic.Uint64 // received over all connections
+	stalls  atomic.Uint64 // frames that waited for space in the merged queue, when decoding falls behind
+
+	mu             sync.Mutex
+	connected      bool
+	connectedSince time.Time
+	connects       int
+	lastErr        error
+	lastErrTime    time.Time
+}
+
+// Status of an input, as served on the web interface
+type inputStatus struct {
+	Addr           string     `json:"addr"`
+	Connected      bool       `json:"connected"`
+	ConnectedSince *time.Time `json:"connected_since"`
+	Connects       int        `json:"connects"`
+	Bytes          uint64     `json:"bytes"`
+	Frames         uint64     `json:"frames"`
+	Stalls         uint64     `json:"stalls"` // frames that waited for space in the merged queue
+	LastError      string     `json:"last_error"`
+	LastErrorTime  *time.Time `json:"last_error_time"`
+}
+
+func newBEASTInput(addr string) (*beastInput, error) {
+	// returns an input for addr, a host:port where the host may be a name or IP address
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" || port == "" {
+		return nil, errors.New("input must be host:port")
+	}
+	return &beastInput{addr: addr, backoff: reconnectBackoff{min: reconnectBackoffMin, max: reconnectBackoffMax}}, nil
+}
+
+func (b *reconnectBackoff) Wait() time.Duration {
+	// returns how long to wait before the next attempt, and doubles the backoff up to max
+	// jittered between half & all of the backoff, so inputs on the same host don't reconnect in step
+	if b.current < b.min {
+		b.current = b.min
+	}
+	wait := b.current / 2
+	if wait > 0 {
+		wait += time.Duration(rand.Int63n(int64(wait)))
+	}
+	b.current *= 2
+	if b.current > b.max {
+		b.current = b.max
+	}
+	return wait
+}
+
+func (b *reconnectBackoff) Reset() {
+	// waits the minimum before the next attempt
+	b.current = b.min
+}
+
+func (in *beastInput) Status() inputStatus {
+	// returns the input's current status
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	status := inputStatus{
+		Addr:      in.addr,
+		Connected: in.connected,
+		Connects:  in.connects,
+		Bytes:     in.bytes.Load(),
+		Frames:    in.frames.Load(),
+		Stalls:    in.stalls.Load(),
+	}
+	if in.connected {
+		since := in.connectedSince
+		status.ConnectedSince = &since
+	}
+	if in.lastErr != nil {
+		errTime := in.lastErrTime
+		status.LastError = in.lastErr.Error()
+		status.LastErrorTime = &errTime
+	}
+	return status
+}
+
+func (in *beastInput) setConnected(connected bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.connected = connected
+	if connected {
+		in.connectedSince = time.Now()
+		in.connects++
+	}
+}
+
+func (in *beastInput) setError(err error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.lastErr = err
+	in.lastErrTime = time.Now()
+}
+
+func (in *beastInput) run(ctx context.Context, messages chan<- receivedMessage) {
+	// receives BEAST data, reconnecting with backoff on error, until ctx is cancelled
+
+	// set up logger
+	log := log.With().Str("src", in.addr).Logger()
+
+	var dialer net.Dialer
+	in.backoff.Reset()
+
+	for {
+
+		// host names are resolved on each attempt, in case the address changes
+		log.Info().Msg("connecting")
+		conn, err := dialer.DialContext(ctx, "tcp", in.addr)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Info().Err(err).Msg("connection error")
+				in.setError(err)
+			}
+		} else {
+			log.Info().Str("remote", conn.RemoteAddr().String()).Msg("connected, synchronising")
+			in.setConnected(true)
+			frames := in.frames.Load()
+			err = in.receive(ctx, conn, messages, log)
+			if err != nil && ctx.Err() == nil {
+				in.setError(err)
+			}
+			in.setConnected(false)
+			conn.Close()
+
+			// don't back off from a connection that was working
+			if in.frames.Load() > frames {
+				in.backoff.Reset()
+			}
+		}
+
+		// wait before reconnecting
+		wait := in.backoff.Wait()
+		if ctx.Err() == nil {
+			log.Info().Dur("wait", wait).Msg("reconnecting after wait")
+		}
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Counts bytes read into an input's total
+type countingReader struct {
+	r     io.Reader
+	count *atomic.Uint64
+}
+
+func (c countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.count.Add(uint64(n))
+	return n, err
+}
+
+func (in *beastInput) receive(ctx context.Context, conn net.Conn, messages chan<- receivedMessage, log zerolog.Logger) error {
+	// receives frames from conn onto messages until the connection fails or ctx is cancelled
+
+	// unblock reads when cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	r := countingReader{r: conn, count: &in.bytes}
+
+	var frameType byte
+	var err error
+	buf := make([]byte, 2)
+
+	synced := false
+	for {
+		if !synced {
+			frameType, err = beastSync(r)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Err(err).Msg("synchronisation error")
+				}
+				return err
+			} else {
+				synced = true
+				log.Info().Msg("synchronised, receiving")
+			}
+
+		} else {
+			_, err := io.ReadFull(r, buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Err(err).Msg("receive error")
+				}
+				return err
+			}
+			if buf[0] == 0x1a {
+				frameType = buf[1]
+			} else {
+				synced = false
+			}
+		}
+
+		if synced {
+
+			frameData, err := beastReadFrame(r, frameType)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Err(err).Msg("frame read error")
+				}
+				return err
+			}
+			in.frames.Add(1)
+
+			switch frameType {
+
+			// Mode-AC frame
+			case 0x31:
+				// ignore this frame type
+				continue
+
+			// Mode-S short & long frames
+			case 0x32, 0x33:
+				msg := receivedMessage{
+					capturedMessage: beastMessage(frameType, frameData),
+					input:           in.addr,
+				}
+				select {
+				case messages <- msg:
+				default:
+					// merged queue is full, stop reading until there's space
+					in.stalls.Add(1)
+					select {
+					case messages <- msg:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+			default:
+				log.Warn().Hex("frameType", []byte{frameType}).Hex("frameData", frameData).Msg("unknown frameType")
+				continue
+			}
+		}
+	}
+}
+
+func mergeMessages(messages <-chan receivedMessage, frames *dedup.Deduplicator, handle func(dedup.Frame)) {
+	// merges messages from all inputs until messages is closed, handling one copy of each frame
+
+	ticker := time.NewTicker(dedupFlushInterval)
+	defer ticker.Stop()
+	flush := func(now time.Time) {
+		for _, frame := range frames.Flush(now) {
+			handle(frame)
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				// inputs stopped, handle frames still held
+				flush(time.Now().Add(time.Hour))
+				return
+			}
+			frames.Add(dedup.Frame{
+				Data:      msg.data,
+				Receiver:  msg.input,
+				RSSIKnown: msg.rssiKnown,
+				RSSI:      msg.rssi,
+				Received:  time.Now(),
+			})
+			flush(time.Now())
+		case <-ticker.C:
+			flush(time.Now())
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"beastdecoder/dedup"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		reset           bool          // before waiting
+		expectedBackoff time.Duration // wait is jittered between half & all of this
+	}{
+		{expectedBackoff: time.Second},
+		{expectedBackoff: time.Second * 2},
+		{expectedBackoff: time.Second * 4},
+		{expectedBackoff: time.Second * 8},
+		{expectedBackoff: time.Second * 8},
+		{reset: true, expectedBackoff: time.Second},
+		{expectedBackoff: time.Second * 2},
+	}
+
+	assert := assert.New(t)
+	for i := 0; i < 100; i++ {
+		b := reconnectBackoff{min: time.Second, max: time.Second * 8}
+		for step, testData := range testTable {
+			testMsg := fmt.Sprintf("step: %d, ", step)
+			if testData.reset {
+				b.Reset()
+			}
+			wait := b.Wait()
+			assert.GreaterOrEqual(wait, testData.expectedBackoff/2, testMsg+"wait")
+			assert.Less(wait, testData.expectedBackoff, testMsg+"wait")
+		}
+	}
+}
+
+func TestNewBEASTInput(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		addr        string
+		expectedErr bool
+	}{
+		{addr: "127.0.0.1:30005"},
+		{addr: "localhost:30005"},
+		{addr: "[::1]:30005"},
+		{addr: "localhost", expectedErr: true},
+		{addr: ":30005", expectedErr: true},
+		{addr: "localhost:", expectedErr: true},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("addr: %s, ", testData.addr)
+		input, err := newBEASTInput(testData.addr)
+		if testData.expectedErr {
+			assert.Error(err, testMsg+"err")
+			continue
+		}
+		if assert.NoError(err, testMsg+"err") {
+			assert.Equal(testData.addr, input.Status().Addr, testMsg+"addr")
+		}
+	}
+}
+
+// A BEAST source on localhost, serving each connection with serve
+type testBEASTServer struct {
+	listener net.Listener
+	accepts  atomic.Int64
+	wg       sync.WaitGroup
+}
+
+func newTestBEASTServer(t *testing.T, serve func(conn net.Conn)) *testBEASTServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testBEASTServer{listener: listener}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.accepts.Add(1)
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *testBEASTServer) addr() string {
+	// by host name, so the input resolves it
+	return fmt.Sprintf("localhost:%d", s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func runTestInput(t *testing.T, addr string, backoff time.Duration, messages chan<- receivedMessage) (*beastInput, context.CancelFunc) {
+	// runs an input until the returned function is called, which waits for it to stop
+	input, err := newBEASTInput(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.backoff = reconnectBackoff{min: backoff, max: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		input.run(ctx, messages)
+	}()
+	stop := func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second * 5):
+			t.Fatal("input didn't stop")
+		}
+	}
+	t.Cleanup(stop)
+	return input, stop
+}
+
+func TestBEASTInputReceive(t *testing.T) {
+	assert := assert.New(t)
+
+	// leading byte to synchronise on, then a long frame & a short frame with its header split across writes
+	long := testBEASTFrame(0x33, 12e6, 255, testIdentification)
+	short := testBEASTFrame(0x32, 24e6, 0, testAllCall)
+	server := newTestBEASTServer(t, func(conn net.Conn) {
+		conn.Write(append([]byte{0x00}, long...))
+		time.Sleep(time.Millisecond * 20)
+		conn.Write(short[:1])
+		time.Sleep(time.Millisecond * 20)
+		conn.Write(short[1:])
+	})
+
+	messages := make(chan receivedMessage, messageQueueLength)
+	input, stop := runTestInput(t, server.addr(), time.Millisecond*10, messages)
+
+	// each connection delivers both frames, then reconnects
+	for i := 0; i < 4; i++ {
+		testMsg := fmt.Sprintf("message: %d, ", i)
+		select {
+		case msg := <-messages:
+			expected := testIdentification
+			if i%2 == 1 {
+				expected = testAllCall
+			}
+			assert.Equal(expected, msg.data, testMsg+"data")
+			assert.Equal(server.addr(), msg.input, testMsg+"input")
+		case <-time.After(time.Second * 5):
+			t.Fatal(testMsg + "not received")
+		}
+	}
+	stop()
+
+	status := input.Status()
+	assert.False(status.Connected, "connected")
+	assert.Nil(status.ConnectedSince, "connected since")
+	assert.GreaterOrEqual(status.Connects, 2, "connects")
+	assert.GreaterOrEqual(status.Frames, uint64(4), "frames")
+	assert.GreaterOrEqual(status.Bytes, uint64(2*(1+len(long)+len(short))), "bytes")
+	assert.NotEmpty(status.LastError, "last error")
+	assert.NotNil(status.LastErrorTime, "last error time")
+	assert.Zero(status.Stalls, "stalls")
+}
+
+func TestBEASTInputBackoff(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name       string
+		serve      func(conn net.Conn)
+		duration   time.Duration
+		minAccepts int64
+		maxAccepts int64
+	}{
+		{
+			// waits of at least 5, 10, 20, 40, 80, 160 & 320 ms
+			name:       "nothing received",
+			serve:      func(conn net.Conn) {},
+			duration:   time.Millisecond * 500,
+			minAccepts: 2,
+			maxAccepts: 7,
+		},
+		{
+			// reset after each connection, so waits of at most 10 ms
+			name: "frames received",
+			serve: func(conn net.Conn) {
+				conn.Write(append([]byte{0x00}, testBEASTFrame(0x32, 12e6, 0, testAllCall)...))
+			},
+			duration:   time.Second,
+			minAccepts: 10,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("backoff: %s, ", testData.name)
+		server := newTestBEASTServer(t, testData.serve)
+		messages := make(chan receivedMessage, messageQueueLength)
+		_, stop := runTestInput(t, server.addr(), time.Millisecond*10, messages)
+		time.Sleep(testData.duration)
+		stop()
+		assert.GreaterOrEqual(server.accepts.Load(), testData.minAccepts, testMsg+"accepts")
+		if testData.maxAccepts > 0 {
+			assert.LessOrEqual(server.accepts.Load(), testData.maxAccepts, testMsg+"accepts")
+		}
+	}
+}
+
+func TestBEASTInputConnectionRefused(t *testing.T) {
+	assert := assert.New(t)
+
+	// nothing listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	messages := make(chan receivedMessage, messageQueueLength)
+	input, stop := runTestInput(t, addr, time.Millisecond*10, messages)
+	assert.Eventually(func() bool {
+		return input.Status().LastError != ""
+	}, time.Second*5, time.Millisecond*10, "last error")
+	stop()
+
+	status := input.Status()
+	assert.False(status.Connected, "connected")
+	assert.Zero(status.Connects, "connects")
+	assert.Zero(status.Frames, "frames")
+}
+
+func TestMergeMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	// both inputs hear the identification, only the first hears the all call reply
+	serve := func(frames ...[]byte) func(conn net.Conn) {
+		return func(conn net.Conn) {
+			conn.Write([]byte{0x00})
+			for _, frame := range frames {
+				conn.Write(frame)
+			}
+			// hold the connection open until the input stops
+			conn.Read(make([]byte, 1))
+		}
+	}
+	shared := testBEASTFrame(0x33, 12e6, 255, testIdentification)
+	servers := []*testBEASTServer{
+		newTestBEASTServer(t, serve(shared, testBEASTFrame(0x32, 24e6, 0, testAllCall))),
+		newTestBEASTServer(t, serve(shared)),
+	}
+
+	messages := make(chan receivedMessage, messageQueueLength)
+	var stops []context.CancelFunc
+	var inputs []*beastInput
+	for _, server := range servers {
+		input, stop := runTestInput(t, server.addr(), time.Millisecond*10, messages)
+		inputs = append(inputs, input)
+		stops = append(stops, stop)
+	}
+	assert.Eventually(func() bool {
+		return inputs[0].Status().Frames == 2 && inputs[1].Status().Frames == 1
+	}, time.Second*5, time.Millisecond*10, "frames received")
+	for _, stop := range stops {
+		stop()
+	}
+	close(messages)
+
+	var d dedup.Deduplicator
+	d.Init(time.Second)
+	var handled []dedup.Frame
+	mergeMessages(messages, &d, func(frame dedup.Frame) {
+		handled = append(handled, frame)
+	})
+
+	if assert.Len(handled, 2, "frames") {
+		assert.Equal(testIdentification, handled[0].Data, "data")
+		assert.ElementsMatch([]string{servers[0].addr(), servers[1].addr()}, handled[0].Receivers, "receivers")
+		assert.Equal(testAllCall, handled[1].Data, "data")
+		assert.Equal([]string{servers[0].addr()}, handled[1].Receivers, "receivers")
+	}
+	assert.Equal(uint64(1), d.Coverage().Duplicates, "duplicates")
+}
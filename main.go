@@ -1,14 +1,21 @@
 package main
 
 import (
+	"beastdecoder/dedup"
+	"beastdecoder/meteo"
 	"beastdecoder/vesselstate"
 	"beastdecoder/webview"
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"os"
+	"os/signal"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -18,9 +25,22 @@ import (
 )
 
 var vdb vesselstate.Vessels
+var grid meteo.Grid
+var frames dedup.Deduplicator
+var decoder pipeline
+var ranges rangeStats
 
 func main() {
-	app := &cli.App{
+	if err := newApp().Run(os.Args); err != nil {
+		log.Err(err).Msg("finished with error")
+	} else {
+		log.Info().Msg("finished")
+	}
+}
+
+func newApp() *cli.App {
+	// returns the command line interface, with its flags & subcommands
+	return &cli.App{
 		Name:  "beastdecoder",
 		Usage: "Decodes BEAST data",
 		Flags: []cli.Flag{
@@ -33,10 +53,22 @@ func main() {
 			&cli.StringSliceFlag{
 				Category:  "BEAST Data Input",
 				Name:      "connect",
-				Usage:     "ip:port of host to receive BEAST data from",
+				Usage:     "host:port to receive BEAST data from, may be given more than once",
 				TakesFile: false,
 				KeepSpace: false,
 			},
+			&cli.DurationFlag{
+				Category: "BEAST Data Input",
+				Name:     "dedup-window",
+				Usage:    "frames are held this long, so copies heard by other inputs are merged, keeping the strongest",
+				Value:    dedup.DefaultWindow,
+			},
+			&cli.IntFlag{
+				Category: "BEAST Data Input",
+				Name:     "decode-workers",
+				Usage:    "number of frames decoded in parallel",
+				Value:    runtime.NumCPU(),
+			},
 			&cli.Float64Flag{
 				Category: "Receiver Location",
 				Name:     "lat",
@@ -47,6 +79,51 @@ func main() {
 				Name:     "lon",
 				Usage:    "longitude of receiver",
 			},
+			&cli.Float64Flag{
+				Category: "Receiver Location",
+				Name:     "magnetic-declination",
+				Usage:    "magnetic declination at receiver in degrees (east positive), used when deriving wind from heading",
+			},
+			&cli.Float64Flag{
+				Category: "Receiver Location",
+				Name:     "max-range",
+				Usage:    "discard positions further than this from the receiver in NM (surface positions beyond 45 NM are always discarded), 0 accepts all positions, needs --lat/--lon",
+				Value:    vesselstate.DefaultMaxRange,
+			},
+			&cli.IntFlag{
+				Category: "Position Quality",
+				Name:     "min-nic",
+				Usage:    "discard positions with a navigation integrity category (NIC) below this, 0 accepts all positions",
+			},
+			&cli.DurationFlag{
+				Category: "Position Quality",
+				Name:     "history",
+				Usage:    "positions are kept in each vessel's history for this long, drawn as trails on the web interface, 0 disables history",
+				Value:    vesselstate.DefaultHistoryDuration,
+			},
+			&cli.BoolFlag{
+				Category: "Meteorology",
+				Name:     "meteo",
+				Usage:    "aggregate wind/temperature observations into a grid, served at /meteo.json and /meteo.csv on the web interface (needs --webview)",
+			},
+			&cli.Float64Flag{
+				Category: "Meteorology",
+				Name:     "meteo-grid-size",
+				Usage:    "size of grid cells in degrees of latitude/longitude",
+				Value:    meteo.DefaultGridLatStep,
+			},
+			&cli.IntFlag{
+				Category: "Meteorology",
+				Name:     "meteo-grid-fl",
+				Usage:    "height of grid cells in flight levels",
+				Value:    meteo.DefaultGridFlightLevelStep,
+			},
+			&cli.DurationFlag{
+				Category: "Meteorology",
+				Name:     "meteo-window",
+				Usage:    "observations older than this are dropped from the grid",
+				Value:    meteo.DefaultGridWindow,
+			},
 			&cli.BoolFlag{
 				Category: "Logging",
 				Name:     "debug",
@@ -54,32 +131,165 @@ func main() {
 			},
 		},
 		Action: run,
-	}
-
-	if err := app.Run(os.Args); err != nil {
-		log.Err(err).Msg("finished with error")
-	} else {
-		log.Info().Msg("finished")
+		Commands: []*cli.Command{
+			{
+				Name:      "decode",
+				Usage:     "decode hex Mode-S messages",
+				ArgsUsage: "[hex message...]",
+				Description: "Prints a breakdown of each message given as an argument, or read one per line from stdin.\n" +
+					"Messages may be plain hex or AVR format (*8D4840D6202CC371C32CE0576098;).",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "print one JSON object per message",
+					},
+				},
+				Action: decode,
+			},
+			{
+				Name:      "export",
+				Usage:     "decode a BEAST or AVR capture to JSON Lines or CSV",
+				ArgsUsage: "[capture file]",
+				Description: "Writes one record per message with its timestamp, RSSI, DF, ICAO, BDS and decoded fields.\n" +
+					"The capture is read from stdin if no file is given.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "input-format",
+						Usage: "capture format: auto, beast or avr",
+						Value: captureFormatAuto,
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "output format: jsonl or csv",
+						Value: exportFormatJSONL,
+					},
+					&cli.StringFlag{
+						Name:      "output",
+						Aliases:   []string{"o"},
+						Usage:     "file to write to, instead of stdout",
+						TakesFile: true,
+					},
+					&cli.BoolFlag{
+						Name:  "drop-bad-crc",
+						Usage: "skip messages that fail the parity check",
+					},
+				},
+				Action: export,
+			},
+			{
+				Name:      "track",
+				Usage:     "reconstruct aircraft tracks from a BEAST or AVR capture",
+				ArgsUsage: "[capture file]",
+				Description: "Replays a capture through the vessel db, with time driven by the capture's MLAT timestamps,\n" +
+					"and writes each aircraft's positions with altitude, ground speed, track & vertical rate.\n" +
+					"The capture is read from stdin if no file is given. Receiver location & position quality options apply.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "input-format",
+						Usage: "capture format: auto, beast or avr",
+						Value: captureFormatAuto,
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "output format: csv, geojson or kml",
+						Value: trackFormatCSV,
+					},
+					&cli.StringFlag{
+						Name:      "output",
+						Aliases:   []string{"o"},
+						Usage:     "file to write to, instead of stdout",
+						TakesFile: true,
+					},
+					&cli.StringFlag{
+						Name:  "start",
+						Usage: "time of the first message (RFC 3339), default assumes the capture ended when the file was last modified",
+					},
+					&cli.IntFlag{
+						Name:  "min-points",
+						Usage: "drop tracks with fewer positions than this",
+						Value: 2,
+					},
+				},
+				Action: reconstructTracks,
+			},
+		},
 	}
 }
 
 func run(ctx *cli.Context) error {
 
-	// init logger
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.UnixDate})
-	if !ctx.Bool("debug") {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	}
+	initLogger(ctx)
 	log.Info().Msg(fmt.Sprintf("starting %s", ctx.App.Name))
 
+	// everything is stopped on SIGINT/SIGTERM, or if a component fails
+	sigCtx, stop := notifyContext(ctx)
+	defer stop()
+	runCtx, cancel := context.WithCancel(sigCtx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var runErr error
+	var runErrOnce sync.Once
+	fail := func(err error) {
+		runErrOnce.Do(func() {
+			runErr = err
+		})
+		cancel()
+	}
+
+	// inputs
+	var inputs []*beastInput
+	for _, addr := range ctx.StringSlice("connect") {
+		input, err := newBEASTInput(addr)
+		if err != nil {
+			log.Err(err).Str("addr", addr).Msg("connect format must be host:port")
+			return fmt.Errorf("could not parse connect address (addr: %s): %w", addr, err)
+		}
+		inputs = append(inputs, input)
+	}
+	if len(inputs) == 0 {
+		return errors.New("no inputs, use --connect host:port")
+	}
+
+	// copies of a frame heard by more than one input are suppressed
+	frames.Init(ctx.Duration("dedup-window"))
+
+	// frames are decoded by workers, and applied to the vessel db from one goroutine
+	decoder.Init(ctx.Int("decode-workers"))
+
+	// meteorological grid, only served on the web interface
+	if ctx.Bool("meteo") {
+		if !ctx.IsSet("webview") {
+			return errors.New("--meteo needs --webview, the grid is only served at /meteo.json and /meteo.csv")
+		}
+		err := grid.Init(ctx.Float64("meteo-grid-size"), ctx.Float64("meteo-grid-size"), ctx.Int("meteo-grid-fl"), ctx.Duration("meteo-window"))
+		if err != nil {
+			log.Err(err).Msg("invalid meteo grid options")
+			return fmt.Errorf("invalid meteo grid options: %w", err)
+		}
+	}
+
 	// init vessel database
 	vdb.Init()
+	configureVessels(ctx, &vdb)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		vdb.RunEvictor(runCtx)
+	}()
 
-	// set refLat/refLon if given
-	if ctx.IsSet("lat") && ctx.IsSet("lon") {
-		vdb.SetRefLatLon(ctx.Float64("lat"), ctx.Float64("lon"))
+	// enable meteorological aggregation
+	var gridPtr *meteo.Grid
+	if ctx.Bool("meteo") {
+		vdb.OnMeteoObservation(func(obs meteo.Observation) {
+			grid.Add(obs)
+		})
+		gridPtr = &grid
 	}
 
+	// range of positions heard by each input
+	vdb.OnPosition(ranges.AddPosition)
+	vdb.OnRangeRejected(ranges.AddRejected)
+
 	// enable web interface
 	if ctx.IsSet("webview") {
 		addrSplit := strings.Split(ctx.String("webview"), ":")
@@ -97,26 +307,115 @@ func run(ctx *cli.Context) error {
 			IP:   ip,
 			Port: int(port),
 		}
-		go webview.Init(addr, &vdb)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := func() any {
+				return runStatus(inputs, &frames, &decoder, &ranges)
+			}
+			if err := webview.Serve(runCtx, addr, &vdb, gridPtr, status); err != nil {
+				log.Err(err).Msg("webview stopped")
+				fail(err)
+			}
+		}()
 	}
 
-	// outgoing connections
-	for _, addr := range ctx.StringSlice("connect") {
-		addrSplit := strings.Split(addr, ":")
-		if len(addrSplit) != 2 {
-			log.Error().Str("addr", addr).Msg("connect format must be host:port")
-			continue
-		}
-		ip := net.ParseIP(addrSplit[0])
-		port, err := strconv.ParseInt(addrSplit[1], 10, 0)
-		if err != nil {
-			log.Err(err).Any("port", addrSplit[1]).Msg("could not parse port")
-		}
-		addr := &net.TCPAddr{
-			IP:   ip,
-			Port: int(port),
-		}
-		beastDial(addr)
+	// outgoing connections, each running concurrently
+	messages := make(chan receivedMessage, messageQueueLength)
+	var inputsWg sync.WaitGroup
+	for _, input := range inputs {
+		inputsWg.Add(1)
+		go func(input *beastInput) {
+			defer inputsWg.Done()
+			input.run(runCtx, messages)
+		}(input)
+	}
+	go func() {
+		inputsWg.Wait()
+		close(messages)
+	}()
+
+	// messages from all inputs are merged, decoded in parallel, and applied to the vessel db in order
+	decoder.Run(func(frame decodedFrame) {
+		log.Debug().Msg("START OF FRAME")
+		ranges.Apply(frame.receivers, func() {
+			applyFrame(&vdb, frame)
+		})
+		log.Debug().Msg("END OF FRAME")
+	})
+	mergeMessages(messages, &frames, func(frame dedup.Frame) {
+		decoder.Submit(frame.Data, frame.Receivers)
+	})
+	decoder.Close()
+
+	// wait for everything to stop
+	cancel()
+	wg.Wait()
+	return runErr
+}
+
+// Status of the running decoder, as served on the web interface
+type status struct {
+	Inputs   []inputStatus  `json:"inputs"`
+	Coverage dedup.Coverage `json:"coverage"` // of each input, from frames heard by more than one
+	Decoder  pipelineStatus `json:"decoder"`
+
+	Ranges map[string]receiverRange `json:"ranges"` // of positions from frames heard by each input
+}
+
+func runStatus(inputs []*beastInput, frames *dedup.Deduplicator, decoder *pipeline, ranges *rangeStats) status {
+	// returns the status of each input, how much their coverage overlaps, their range, and decoder throughput
+	s := status{
+		Inputs:   make([]inputStatus, 0, len(inputs)),
+		Coverage: frames.Coverage(),
+		Decoder:  decoder.Status(),
+		Ranges:   ranges.Status(),
+	}
+	for _, input := range inputs {
+		s.Inputs = append(s.Inputs, input.Status())
+	}
+	return s
+}
+
+func initLogger(ctx *cli.Context) {
+	// logs to stderr, so output of subcommands can be piped
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.UnixDate})
+	if !ctx.Bool("debug") {
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	}
+}
+
+func configureVessels(ctx *cli.Context, vdb *vesselstate.Vessels) {
+	// applies receiver location & position quality options to a vessel db
+
+	// set refLat/refLon if given
+	if ctx.IsSet("lat") && ctx.IsSet("lon") {
+		vdb.SetRefLatLon(ctx.Float64("lat"), ctx.Float64("lon"))
+	}
+
+	// set maximum range from receiver
+	vdb.SetMaxRange(ctx.Float64("max-range"))
+
+	// set magnetic declination if given
+	if ctx.IsSet("magnetic-declination") {
+		vdb.SetMagneticDeclination(ctx.Float64("magnetic-declination"))
+	}
+
+	// set minimum position integrity if given
+	if ctx.IsSet("min-nic") {
+		vdb.SetMinimumNIC(ctx.Int("min-nic"))
 	}
-	return nil
+
+	// set history duration
+	vdb.SetHistoryDuration(ctx.Duration("history"))
+}
+
+func notifyContext(ctx *cli.Context) (context.Context, context.CancelFunc) {
+	// returns a context cancelled on SIGINT/SIGTERM, after which a second signal exits immediately
+	sigCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCtx.Done()
+		stop()
+	}()
+	return sigCtx, stop
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func freeTestAddr(t *testing.T) string {
+	// returns a localhost address with nothing listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestRunShutdown(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name          string
+		webviewInUse  bool // webview can't bind, which stops the run
+		cancel        bool
+		expectedError bool
+	}{
+		{name: "cancelled", cancel: true},
+		{name: "webview bind failure", webviewInUse: true, expectedError: true},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("run: %s, ", testData.name)
+
+		// input connects, then blocks reading as nothing is sent
+		closed := make(chan struct{}, 1)
+		server := newTestBEASTServer(t, func(conn net.Conn) {
+			conn.Read(make([]byte, 1))
+			closed <- struct{}{}
+		})
+
+		webviewAddr := freeTestAddr(t)
+		if testData.webviewInUse {
+			listener, err := net.Listen("tcp", webviewAddr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- newApp().RunContext(ctx, []string{"beastdecoder", "--connect", server.addr(), "--webview", webviewAddr})
+		}()
+
+		if testData.cancel {
+			assert.Eventually(func() bool {
+				return server.accepts.Load() > 0
+			}, time.Second*5, time.Millisecond*10, testMsg+"connected")
+			assert.Eventually(func() bool {
+				resp, err := http.Get("http://" + webviewAddr + "/status.json")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusOK
+			}, time.Second*5, time.Millisecond*10, testMsg+"webview serving")
+			cancel()
+		}
+
+		select {
+		case err := <-done:
+			assert.Equal(testData.expectedError, err != nil, testMsg+"error")
+		case <-time.After(time.Second * 5):
+			t.Fatal(testMsg + "run didn't return")
+		}
+
+		// input's connection was closed, if it connected
+		if server.accepts.Load() > 0 {
+			select {
+			case <-closed:
+			case <-time.After(time.Second * 5):
+				t.Fatal(testMsg + "input connection not closed")
+			}
+		}
+
+		// webview stopped listening
+		if !testData.webviewInUse {
+			_, err := http.Get("http://" + webviewAddr + "/status.json")
+			assert.Error(err, testMsg+"webview stopped")
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package meteo
+
+import (
+	"beastdecoder/bds"
+	"math"
+	"sync"
+	"time"
+)
+
+// Default maximum time between BDS 5,0 and BDS 6,0 replies for them to be paired
+const DefaultPairingWindow = time.Second * 5
+
+// Aircraft that are turning report misleading heading/track combinations, so pairs where the
+// aircraft is banked more than this (degrees) are discarded
+const maxRollAngle = 5.0
+
+// Below this Mach number the resolution of the Mach field (0.004) makes temperature meaningless
+const minMachForTemperature = 0.4
+
+type Deriver struct {
+	mu sync.Mutex // sync mutex
+
+	// maximum time between BDS 5,0 and BDS 6,0 replies for them to be paired
+	window time.Duration
+
+	// magnetic declination (degrees, east positive) used to convert BDS 6,0 magnetic heading to true heading
+	declination float64
+
+	// most recent EHS replies per aircraft, key is ICAO
+	aircraft map[int]*ehsReplies
+}
+
+type ehsReplies struct {
+	bds50      bds.BDS50Frame
+	bds50Time  time.Time
+	bds50Known bool
+
+	bds60      bds.BDS60Frame
+	bds60Time  time.Time
+	bds60Known bool
+}
+
+func (d *Deriver) Init(window time.Duration, declination float64) {
+	// run once before use
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.window = window
+	d.declination = declination
+	d.aircraft = make(map[int]*ehsReplies)
+}
+
+func (d *Deriver) SetDeclination(declination float64) {
+	// sets magnetic declination (degrees, east positive)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.declination = declination
+}
+
+func (d *Deriver) AddBDS50(icao int, t time.Time, frame bds.BDS50Frame) (obs Observation, ok bool) {
+	// stores a track and turn report, and returns an observation if a heading and speed report was received recently
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a := d.get(icao)
+	a.bds50 = frame
+	a.bds50Time = t
+	a.bds50Known = true
+	return d.derive(icao, a, t)
+}
+
+func (d *Deriver) AddBDS60(icao int, t time.Time, frame bds.BDS60Frame) (obs Observation, ok bool) {
+	// stores a heading and speed report, and returns an observation if a track and turn report was received recently
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a := d.get(icao)
+	a.bds60 = frame
+	a.bds60Time = t
+	a.bds60Known = true
+	return d.derive(icao, a, t)
+}
+
+func (d *Deriver) Forget(icao int) {
+	// drops stored replies for an aircraft, for example when it is evicted
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.aircraft, icao)
+}
+
+func (d *Deriver) get(icao int) *ehsReplies {
+	// returns stored replies for an aircraft, creating an entry if needed
+	if d.aircraft == nil {
+		d.aircraft = make(map[int]*ehsReplies)
+	}
+	a, ok := d.aircraft[icao]
+	if !ok {
+		a = &ehsReplies{}
+		d.aircraft[icao] = a
+	}
+	return a
+}
+
+func (d *Deriver) derive(icao int, a *ehsReplies, t time.Time) (obs Observation, ok bool) {
+	// computes wind and temperature from a pair of BDS 5,0 and BDS 6,0 replies
+
+	window := d.window
+	if window == 0 {
+		window = DefaultPairingWindow
+	}
+
+	// need both replies, close together in time
+	if !a.bds50Known || !a.bds60Known {
+		return obs, false
+	}
+	dt := a.bds50Time.Sub(a.bds60Time)
+	if dt < 0 {
+		dt = -dt
+	}
+	if dt > window {
+		return obs, false
+	}
+
+	// discard if turning
+	if a.bds50.RollAngleValid && math.Abs(a.bds50.RollAngle) > maxRollAngle {
+		return obs, false
+	}
+
+	obs = Observation{
+		ICAO:   icao,
+		Time:   t,
+		Source: SourceEHS,
+	}
+
+	// wind: ground vector from BDS 5,0, air vector from BDS 5,0 TAS and BDS 6,0 heading
+	if a.bds50.GroundSpeedValid && a.bds50.TrueTrackAngleValid && a.bds50.TrueAirspeedValid && a.bds60.MagneticHeadingValid {
+		heading := math.Mod(a.bds60.MagneticHeading+d.declination+360, 360)
+		obs.WindSpeed, obs.WindDirection = Wind(a.bds50.GroundSpeed, a.bds50.TrueTrackAngle, a.bds50.TrueAirspeed, heading)
+		obs.WindValid = true
+	}
+
+	// temperature: BDS 5,0 TAS and BDS 6,0 Mach
+	if a.bds50.TrueAirspeedValid && a.bds60.MachNumberValid && a.bds60.MachNumber >= minMachForTemperature {
+		temperature := StaticAirTemperature(a.bds50.TrueAirspeed, a.bds60.MachNumber)
+		if temperature >= -80 && temperature <= 60 {
+			obs.Temperature = temperature
+			obs.TemperatureValid = true
+		}
+	}
+
+	return obs, obs.WindValid || obs.TemperatureValid
+}
@@ -0,0 +1,274 @@
+package meteo
+
+// Gridded aggregation of meteorological observations.
+//
+// Observations are binned into cells by latitude, longitude and flight level.
+// Each cell keeps the observations received within a rolling window, and reports
+// the number of observations and their average. Wind is averaged as a vector.
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default grid configuration
+const DefaultGridLatStep = 1.0             // degrees
+const DefaultGridLonStep = 1.0             // degrees
+const DefaultGridFlightLevelStep = 50      // flight levels (ie: 5000 ft)
+const DefaultGridWindow = time.Minute * 60 // rolling window
+const maxFlightLevel = 600                 // observations above this are discarded
+const minFlightLevel = 0                   // observations below this are discarded
+
+type Grid struct {
+	mu sync.Mutex // sync mutex
+
+	latStep, lonStep float64       // cell size (degrees)
+	flStep           int           // cell height (flight levels)
+	window           time.Duration // observations older than this are discarded
+
+	cells map[gridKey]*gridCell
+}
+
+type gridKey struct {
+	lat, lon, fl int // cell indexes
+}
+
+type gridCell struct {
+	samples []gridSample
+}
+
+type gridSample struct {
+	time time.Time
+
+	windValid bool
+	u, v      float64 // wind vector, direction the wind is blowing towards (kt)
+
+	temperatureValid bool
+	temperature      float64 // °C
+}
+
+// Summary of the observations in a grid cell
+type Cell struct {
+	LatMin         float64 `json:"lat_min"`
+	LatMax         float64 `json:"lat_max"`
+	LonMin         float64 `json:"lon_min"`
+	LonMax         float64 `json:"lon_max"`
+	FlightLevel    int     `json:"fl_min"` // lower bound of cell
+	FlightLevelMax int     `json:"fl_max"` // upper bound of cell
+
+	WindCount     int     `json:"wind_count"`
+	WindSpeed     float64 `json:"wind_speed"`     // kt
+	WindDirection float64 `json:"wind_direction"` // degrees true, direction the wind is blowing from
+
+	TemperatureCount int     `json:"temperature_count"`
+	Temperature      float64 `json:"temperature"` // °C
+
+	Updated time.Time `json:"updated"` // time of most recent observation
+}
+
+func (g *Grid) Init(latStep, lonStep float64, flStep int, window time.Duration) error {
+	// run once before use, returns an error if the cell size or window isn't positive
+	if !(latStep > 0) || !(lonStep > 0) || math.IsInf(latStep, 0) || math.IsInf(lonStep, 0) {
+		return fmt.Errorf("grid cell size must be positive (lat: %v, lon: %v)", latStep, lonStep)
+	}
+	if flStep <= 0 {
+		return fmt.Errorf("grid cell height must be positive (fl: %v)", flStep)
+	}
+	if window <= 0 {
+		return fmt.Errorf("grid window must be positive (window: %v)", window)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.latStep = latStep
+	g.lonStep = lonStep
+	g.flStep = flStep
+	g.window = window
+	g.cells = make(map[gridKey]*gridCell)
+	return nil
+}
+
+func (g *Grid) Add(obs Observation) bool {
+	// adds an observation to the grid
+	// returns false if the observation could not be placed in a cell
+
+	if !obs.PositionKnown || !obs.AltitudeKnown {
+		return false
+	}
+	if !obs.WindValid && !obs.TemperatureValid {
+		return false
+	}
+
+	fl := obs.Altitude / 100
+	if fl < minFlightLevel || fl > maxFlightLevel {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := gridKey{
+		lat: int(math.Floor(obs.Lat / g.latStep)),
+		lon: int(math.Floor(obs.Lon / g.lonStep)),
+		fl:  fl / g.flStep,
+	}
+
+	c, ok := g.cells[key]
+	if !ok {
+		c = &gridCell{}
+		g.cells[key] = c
+	}
+
+	s := gridSample{
+		time:             obs.Time,
+		windValid:        obs.WindValid,
+		temperatureValid: obs.TemperatureValid,
+		temperature:      obs.Temperature,
+	}
+	if obs.WindValid {
+		s.u = -obs.WindSpeed * math.Sin(obs.WindDirection*math.Pi/180)
+		s.v = -obs.WindSpeed * math.Cos(obs.WindDirection*math.Pi/180)
+	}
+	c.samples = append(c.samples, s)
+
+	// discard expired samples, so cells stay bounded even if nothing reads the grid
+	for len(c.samples) > 0 && obs.Time.Sub(c.samples[0].time) > g.window {
+		c.samples = c.samples[1:]
+	}
+
+	return true
+}
+
+func (g *Grid) Cells(now time.Time) (cells []Cell) {
+	// discards expired observations, and returns a summary of each cell that has observations
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for key, c := range g.cells {
+
+		// discard expired samples
+		kept := c.samples[:0]
+		for _, s := range c.samples {
+			if now.Sub(s.time) <= g.window {
+				kept = append(kept, s)
+			}
+		}
+		c.samples = kept
+		if len(c.samples) == 0 {
+			delete(g.cells, key)
+			continue
+		}
+
+		cell := Cell{
+			LatMin:         float64(key.lat) * g.latStep,
+			LatMax:         float64(key.lat+1) * g.latStep,
+			LonMin:         float64(key.lon) * g.lonStep,
+			LonMax:         float64(key.lon+1) * g.lonStep,
+			FlightLevel:    key.fl * g.flStep,
+			FlightLevelMax: (key.fl + 1) * g.flStep,
+		}
+
+		var u, v, temperature float64
+		for _, s := range c.samples {
+			if s.windValid {
+				u += s.u
+				v += s.v
+				cell.WindCount++
+			}
+			if s.temperatureValid {
+				temperature += s.temperature
+				cell.TemperatureCount++
+			}
+			if s.time.After(cell.Updated) {
+				cell.Updated = s.time
+			}
+		}
+
+		if cell.WindCount > 0 {
+			u /= float64(cell.WindCount)
+			v /= float64(cell.WindCount)
+			cell.WindSpeed = math.Sqrt(math.Pow(u, 2) + math.Pow(v, 2))
+			cell.WindDirection = math.Atan2(-u, -v) * 180 / math.Pi
+			if cell.WindDirection < 0 {
+				cell.WindDirection += 360
+			}
+		}
+
+		if cell.TemperatureCount > 0 {
+			cell.Temperature = temperature / float64(cell.TemperatureCount)
+		}
+
+		cells = append(cells, cell)
+	}
+
+	// stable output: by flight level, then latitude, then longitude
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].FlightLevel != cells[j].FlightLevel {
+			return cells[i].FlightLevel < cells[j].FlightLevel
+		}
+		if cells[i].LatMin != cells[j].LatMin {
+			return cells[i].LatMin < cells[j].LatMin
+		}
+		return cells[i].LonMin < cells[j].LonMin
+	})
+
+	return cells
+}
+
+func (g *Grid) WriteJSON(w io.Writer, now time.Time) error {
+	// writes a summary of each cell as a JSON array
+	cells := g.Cells(now)
+	if cells == nil {
+		cells = []Cell{}
+	}
+	return json.NewEncoder(w).Encode(cells)
+}
+
+func (g *Grid) WriteCSV(w io.Writer, now time.Time) error {
+	// writes a summary of each cell as CSV, with a header row
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"lat_min", "lat_max", "lon_min", "lon_max", "fl_min", "fl_max", "wind_count", "wind_speed", "wind_direction", "temperature_count", "temperature", "updated"})
+	if err != nil {
+		return err
+	}
+
+	for _, cell := range g.Cells(now) {
+		record := []string{
+			strconv.FormatFloat(cell.LatMin, 'f', -1, 64),
+			strconv.FormatFloat(cell.LatMax, 'f', -1, 64),
+			strconv.FormatFloat(cell.LonMin, 'f', -1, 64),
+			strconv.FormatFloat(cell.LonMax, 'f', -1, 64),
+			strconv.Itoa(cell.FlightLevel),
+			strconv.Itoa(cell.FlightLevelMax),
+			strconv.Itoa(cell.WindCount),
+			"",
+			"",
+			strconv.Itoa(cell.TemperatureCount),
+			"",
+			cell.Updated.UTC().Format(time.RFC3339),
+		}
+		if cell.WindCount > 0 {
+			record[7] = fmt.Sprintf("%.1f", cell.WindSpeed)
+			record[8] = fmt.Sprintf("%.0f", cell.WindDirection)
+		}
+		if cell.TemperatureCount > 0 {
+			record[10] = fmt.Sprintf("%.2f", cell.Temperature)
+		}
+		err = cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
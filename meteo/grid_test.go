@@ -0,0 +1,92 @@
+package meteo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid(t *testing.T) {
+	assert := assert.New(t)
+
+	var g Grid
+	assert.NoError(g.Init(1, 1, 50, time.Minute*60))
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// two observations in the same cell, winds from 350° and 010° average to 000°
+	assert.True(g.Add(Observation{Time: now, PositionKnown: true, Lat: -31.5, Lon: 115.5, AltitudeKnown: true, Altitude: 35000, WindValid: true, WindSpeed: 50, WindDirection: 350, TemperatureValid: true, Temperature: -50}))
+	assert.True(g.Add(Observation{Time: now.Add(time.Minute), PositionKnown: true, Lat: -31.1, Lon: 115.9, AltitudeKnown: true, Altitude: 36000, WindValid: true, WindSpeed: 50, WindDirection: 10, TemperatureValid: true, Temperature: -54}))
+
+	// a different cell
+	assert.True(g.Add(Observation{Time: now, PositionKnown: true, Lat: -31.5, Lon: 115.5, AltitudeKnown: true, Altitude: 5000, TemperatureValid: true, Temperature: 10}))
+
+	// no position
+	assert.False(g.Add(Observation{Time: now, AltitudeKnown: true, Altitude: 5000, TemperatureValid: true, Temperature: 10}))
+
+	cells := g.Cells(now.Add(time.Minute * 2))
+	assert.Len(cells, 2)
+
+	assert.Equal(50, cells[0].FlightLevel)
+	assert.Equal(0, cells[0].WindCount)
+	assert.Equal(1, cells[0].TemperatureCount)
+	assert.Equal(10.0, cells[0].Temperature)
+
+	assert.Equal(350, cells[1].FlightLevel)
+	assert.Equal(400, cells[1].FlightLevelMax)
+	assert.Equal(-32.0, cells[1].LatMin)
+	assert.Equal(115.0, cells[1].LonMin)
+	assert.Equal(2, cells[1].WindCount)
+	assert.Equal(49.2, math.Round(cells[1].WindSpeed*10)/10)
+	assert.Equal(0.0, math.Mod(math.Round(cells[1].WindDirection), 360))
+	assert.Equal(-52.0, cells[1].Temperature)
+	assert.Equal(now.Add(time.Minute), cells[1].Updated)
+
+	// csv has a header and a row per cell
+	var buf bytes.Buffer
+	assert.NoError(g.WriteCSV(&buf, now.Add(time.Minute*2)))
+	assert.Len(strings.Split(strings.TrimSpace(buf.String()), "\n"), 3)
+
+	// observations expire
+	assert.Len(g.Cells(now.Add(time.Minute*90)), 0)
+
+	buf.Reset()
+	assert.NoError(g.WriteJSON(&buf, now.Add(time.Minute*90)))
+	assert.Equal("[]\n", buf.String())
+}
+
+func TestGridInit(t *testing.T) {
+	var testTable = []struct {
+		latStep, lonStep float64
+		flStep           int
+		window           time.Duration
+		expectedErr      bool
+	}{
+		{latStep: 1, lonStep: 1, flStep: 50, window: time.Hour, expectedErr: false},
+		{latStep: 0.25, lonStep: 0.5, flStep: 10, window: time.Minute, expectedErr: false},
+		{latStep: 0, lonStep: 1, flStep: 50, window: time.Hour, expectedErr: true},
+		{latStep: 1, lonStep: -1, flStep: 50, window: time.Hour, expectedErr: true},
+		{latStep: math.NaN(), lonStep: 1, flStep: 50, window: time.Hour, expectedErr: true},
+		{latStep: 1, lonStep: math.Inf(1), flStep: 50, window: time.Hour, expectedErr: true},
+		{latStep: 1, lonStep: 1, flStep: 0, window: time.Hour, expectedErr: true},
+		{latStep: 1, lonStep: 1, flStep: -50, window: time.Hour, expectedErr: true},
+		{latStep: 1, lonStep: 1, flStep: 50, window: 0, expectedErr: true},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("lat: %v, lon: %v, fl: %v, window: %v, ", testData.latStep, testData.lonStep, testData.flStep, testData.window)
+		var g Grid
+		err := g.Init(testData.latStep, testData.lonStep, testData.flStep, testData.window)
+		if testData.expectedErr {
+			assert.Error(err, testMsg+"error")
+		} else {
+			assert.NoError(err, testMsg+"error")
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package meteo
+
+// Meteorological observations from Mode-S replies.
+//
+// Aircraft that do not broadcast BDS 4,4 (MRAR) still report ground speed/track and
+// true airspeed (BDS 5,0) alongside heading and Mach number (BDS 6,0).
+// The difference between the ground and air vectors is the wind, and the ratio of
+// true airspeed to Mach number gives the local speed of sound, and hence temperature.
+// See: https://mode-s.org/decode/content/mode-s/8-meteo.html
+
+import (
+	"beastdecoder/bds"
+	"math"
+	"time"
+)
+
+// Observation source
+type Source uint8
+
+const SourceMRAR = Source(1) // Reported directly by the aircraft in BDS 4,4
+const SourceEHS = Source(2)  // Derived from BDS 5,0 and BDS 6,0
+
+func (s Source) String() string {
+	switch s {
+	case SourceMRAR:
+		return "mrar"
+	case SourceEHS:
+		return "ehs"
+	}
+	return "unknown"
+}
+
+type Observation struct {
+	ICAO   int       // ICAO address of the reporting aircraft
+	Time   time.Time // Time of observation
+	Source Source    // Where the observation came from
+
+	// Position of the aircraft at the time of observation
+	PositionKnown bool
+	Lat, Lon      float64
+
+	// Altitude (ft) of the aircraft at the time of observation
+	AltitudeKnown bool
+	Altitude      int
+
+	// Wind
+	WindValid     bool
+	WindSpeed     float64 // kt
+	WindDirection float64 // degrees true, direction the wind is blowing from
+
+	// Static air temperature
+	TemperatureValid bool
+	Temperature      float64 // °C
+}
+
+// speed of sound at ISA sea level (kt)
+const speedOfSoundSeaLevel = 661.4788
+
+// ISA sea level temperature (K)
+const temperatureSeaLevel = 288.15
+
+// 0°C in K
+const zeroCelsius = 273.15
+
+func Wind(groundSpeed, groundTrack, trueAirspeed, heading float64) (windSpeed, windDirection float64) {
+	// returns wind speed and the direction the wind is blowing from,
+	// given the ground vector (speed/true track) and air vector (speed/true heading)
+	// speeds are in the same units, angles in degrees
+
+	// ground vector
+	gx := groundSpeed * math.Sin(groundTrack*math.Pi/180)
+	gy := groundSpeed * math.Cos(groundTrack*math.Pi/180)
+
+	// air vector
+	ax := trueAirspeed * math.Sin(heading*math.Pi/180)
+	ay := trueAirspeed * math.Cos(heading*math.Pi/180)
+
+	// wind vector (direction the wind is blowing towards)
+	wx := gx - ax
+	wy := gy - ay
+
+	windSpeed = math.Sqrt(math.Pow(wx, 2) + math.Pow(wy, 2))
+
+	// direction the wind is blowing from
+	windDirection = math.Atan2(-wx, -wy) * 180 / math.Pi
+	if windDirection < 0 {
+		windDirection += 360
+	}
+
+	return windSpeed, windDirection
+}
+
+func StaticAirTemperature(trueAirspeed, mach float64) (temperature float64) {
+	// returns static air temperature (°C) from true airspeed (kt) and Mach number
+	// the speed of sound is proportional to the square root of absolute temperature
+	a := trueAirspeed / mach
+	return temperatureSeaLevel*math.Pow(a/speedOfSoundSeaLevel, 2) - zeroCelsius
+}
+
+func FromBDS44(icao int, t time.Time, frame bds.BDS44Frame) (obs Observation) {
+	// returns an observation from a meteorological routine air report
+	obs = Observation{
+		ICAO:             icao,
+		Time:             t,
+		Source:           SourceMRAR,
+		WindValid:        frame.WindValid,
+		WindSpeed:        frame.WindSpeed,
+		WindDirection:    frame.WindDirection,
+		TemperatureValid: frame.StaticAirTemperatureValid,
+		Temperature:      frame.StaticAirTemperature,
+	}
+	return obs
+}
@@ -0,0 +1,152 @@
+package meteo
+
+import (
+	"beastdecoder/bds"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWind(t *testing.T) {
+	var testTable = []struct {
+		groundSpeed           float64
+		groundTrack           float64
+		trueAirspeed          float64
+		heading               float64
+		expectedWindSpeed     float64
+		expectedWindDirection float64
+	}{
+		{
+			// headwind from the north
+			groundSpeed:           400,
+			groundTrack:           0,
+			trueAirspeed:          450,
+			heading:               0,
+			expectedWindSpeed:     50,
+			expectedWindDirection: 0,
+		},
+		{
+			// tailwind from the west
+			groundSpeed:           500,
+			groundTrack:           90,
+			trueAirspeed:          450,
+			heading:               90,
+			expectedWindSpeed:     50,
+			expectedWindDirection: 270,
+		},
+		{
+			// crosswind from the south, crabbing left
+			groundSpeed:           400,
+			groundTrack:           270,
+			trueAirspeed:          403.1,
+			heading:               262.9,
+			expectedWindSpeed:     49.8,
+			expectedWindDirection: 180,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("gs: %.1f, trk: %.1f, tas: %.1f, hdg: %.1f, ", testData.groundSpeed, testData.groundTrack, testData.trueAirspeed, testData.heading)
+		ws, wd := Wind(testData.groundSpeed, testData.groundTrack, testData.trueAirspeed, testData.heading)
+		assert.Equal(testData.expectedWindSpeed, math.Round(ws*10)/10, testMsg+"windSpeed")
+		assert.Equal(testData.expectedWindDirection, math.Mod(math.Round(wd), 360), testMsg+"windDirection")
+	}
+}
+
+func TestStaticAirTemperature(t *testing.T) {
+	assert := assert.New(t)
+
+	// ISA sea level
+	assert.Equal(15.0, math.Round(StaticAirTemperature(661.4788, 1)*10)/10)
+
+	// ISA tropopause (-56.5°C), 0.78 Mach is 447.4 kt
+	assert.Equal(-56.5, math.Round(StaticAirTemperature(447.38, 0.78)*10)/10)
+}
+
+func TestDeriver(t *testing.T) {
+	assert := assert.New(t)
+
+	var d Deriver
+	d.Init(time.Second*5, 0)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bds50frame := bds.BDS50Frame{
+		RollAngleValid:      true,
+		RollAngle:           0.5,
+		TrueTrackAngleValid: true,
+		TrueTrackAngle:      0,
+		GroundSpeedValid:    true,
+		GroundSpeed:         400,
+		TrueAirspeedValid:   true,
+		TrueAirspeed:        448,
+	}
+	bds60frame := bds.BDS60Frame{
+		MagneticHeadingValid: true,
+		MagneticHeading:      0,
+		MachNumberValid:      true,
+		MachNumber:           0.78,
+	}
+
+	// no pair yet
+	_, ok := d.AddBDS50(0x7c1234, now, bds50frame)
+	assert.False(ok)
+
+	// pair within window
+	obs, ok := d.AddBDS60(0x7c1234, now.Add(time.Second*2), bds60frame)
+	assert.True(ok)
+	assert.Equal(SourceEHS, obs.Source)
+	assert.Equal(0x7c1234, obs.ICAO)
+	assert.True(obs.WindValid)
+	assert.Equal(48.0, obs.WindSpeed)
+	assert.Equal(0.0, math.Round(obs.WindDirection))
+	assert.True(obs.TemperatureValid)
+	assert.Equal(-55.9, math.Round(obs.Temperature*10)/10)
+
+	// other aircraft don't pair
+	_, ok = d.AddBDS60(0x7c4321, now.Add(time.Second*2), bds60frame)
+	assert.False(ok)
+
+	// pair outside window
+	_, ok = d.AddBDS50(0x7c1234, now.Add(time.Second*10), bds50frame)
+	assert.False(ok)
+
+	// turning aircraft
+	bds50frame.RollAngle = 25
+	_, ok = d.AddBDS50(0x7c1234, now.Add(time.Second*11), bds50frame)
+	assert.False(ok)
+	_, ok = d.AddBDS60(0x7c1234, now.Add(time.Second*12), bds60frame)
+	assert.False(ok)
+}
+
+func TestFromBDS44(t *testing.T) {
+	var testTable = []struct {
+		mb                       []byte
+		expectedWindValid        bool
+		expectedTemperatureValid bool
+		expectedTemperature      float64
+	}{
+		{mb: []byte{0x18, 0x5B, 0xD5, 0xCF, 0x40, 0x00, 0x00}, expectedWindValid: true, expectedTemperatureValid: true, expectedTemperature: -48.75},
+		{mb: []byte{0x18, 0x5B, 0xD4, 0x00, 0x00, 0x00, 0x00}, expectedWindValid: true, expectedTemperatureValid: false},
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("mb: %014x, ", testData.mb)
+		frame, err := bds.DecodeBDS44(testData.mb)
+		assert.NoError(err, testMsg+"DecodeBDS44 error")
+		obs := FromBDS44(0x7c1234, now, frame)
+		assert.Equal(SourceMRAR, obs.Source, testMsg+"source")
+		assert.Equal(testData.expectedWindValid, obs.WindValid, testMsg+"windValid")
+		assert.Equal(testData.expectedTemperatureValid, obs.TemperatureValid, testMsg+"temperatureValid")
+		if testData.expectedTemperatureValid {
+			assert.Equal(testData.expectedTemperature, obs.Temperature, testMsg+"temperature")
+		}
+	}
+}
@@ -101,17 +101,3 @@ package main
 // 	// Returns DF (downlink format) from message
 // 	return df.DownlinkFormat(((int(data[0]) & 0b11111000) >> 3) + 100)
 // }
-
-// func isSurfacePosValid(refLat, refLon, surfaceLat, surfaceLon float64) bool {
-
-// 	surfacePos := haversine.Coord{Lat: surfaceLat, Lon: surfaceLon}
-// 	receiverPos := haversine.Coord{Lat: refLat, Lon: refLon}
-
-// 	_, km := haversine.Distance(surfacePos, receiverPos)
-// 	nm := km * 0.539957
-
-// 	if nm < 45 {
-// 		return true
-// 	}
-// 	return false
-// }
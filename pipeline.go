@@ -0,0 +1,115 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Frames waiting to be decoded or applied to the vessel db, submitting blocks when full
+const pipelineQueueLength = 4096
+
+// Decodes frames across workers, and applies them to the vessel db from a single goroutine in the order submitted.
+// The vessel db is only locked once per frame, and a busy feed backs up to the inputs rather than growing without bound.
+type pipeline struct {
+	workers int
+	jobs    chan pipelineJob
+	results chan chan decodedFrame // in order submitted
+	done    chan struct{}          // closed once every result has been applied
+
+	submitted atomic.Uint64
+	applied   atomic.Uint64
+	stalls    atomic.Uint64 // submissions that waited for space in the queue
+	stalled   atomic.Int64  // total time waiting (ns)
+}
+
+type pipelineJob struct {
+	data      []byte
+	receivers []string
+	result    chan<- decodedFrame
+}
+
+// Status of the pipeline, as served on the web interface
+type pipelineStatus struct {
+	Workers        int     `json:"workers"`
+	Queued         int     `json:"queued"` // submitted, not yet applied
+	QueueLength    int     `json:"queue_length"`
+	Submitted      uint64  `json:"submitted"`
+	Applied        uint64  `json:"applied"`
+	Stalls         uint64  `json:"stalls"`          // submissions that waited for space in the queue
+	StalledSeconds float64 `json:"stalled_seconds"` // total time waiting
+}
+
+func (p *pipeline) Init(workers int) {
+	// run once before use
+	if workers < 1 {
+		workers = 1
+	}
+	p.workers = workers
+	p.jobs = make(chan pipelineJob, pipelineQueueLength)
+	p.results = make(chan chan decodedFrame, pipelineQueueLength)
+	p.done = make(chan struct{})
+}
+
+func (p *pipeline) Run(apply func(decodedFrame)) {
+	// starts the workers, and applies decoded frames until Close is called & the queue is empty
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range p.jobs {
+				frame := decodeFrame(job.data)
+				frame.receivers = job.receivers
+				job.result <- frame
+			}
+		}()
+	}
+
+	go func() {
+		defer close(p.done)
+		for result := range p.results {
+			apply(<-result)
+			p.applied.Add(1)
+		}
+		wg.Wait()
+	}()
+}
+
+func (p *pipeline) Submit(data []byte, receivers []string) {
+	// queues a frame heard by receivers for decoding, waiting for space if the queue is full
+	// called from a single goroutine, which sets the order frames are applied in
+
+	result := make(chan decodedFrame, 1)
+	select {
+	case p.results <- result:
+	default:
+		p.stalls.Add(1)
+		start := time.Now()
+		p.results <- result
+		p.stalled.Add(int64(time.Since(start)))
+	}
+	p.jobs <- pipelineJob{data: data, receivers: receivers, result: result}
+	p.submitted.Add(1)
+}
+
+func (p *pipeline) Close() {
+	// stops accepting frames, and waits for those queued to be applied
+	close(p.jobs)
+	close(p.results)
+	<-p.done
+}
+
+func (p *pipeline) Status() pipelineStatus {
+	// returns throughput & backpressure metrics
+	return pipelineStatus{
+		Workers:        p.workers,
+		Queued:         len(p.results),
+		QueueLength:    cap(p.results),
+		Submitted:      p.submitted.Load(),
+		Applied:        p.applied.Load(),
+		Stalls:         p.stalls.Load(),
+		StalledSeconds: time.Duration(p.stalled.Load()).Seconds(),
+	}
+}
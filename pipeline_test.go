@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineOrder(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		workers int
+		frames  int
+	}{
+		{workers: 1, frames: 100},
+		{workers: 4, frames: 10000},
+		{workers: 16, frames: pipelineQueueLength * 3}, // more than the queue holds
+		{workers: 0, frames: 10},                       // at least one worker
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("workers: %d, frames: %d, ", testData.workers, testData.frames)
+
+		var p pipeline
+		p.Init(testData.workers)
+		var applied []uint32
+		var receivers []string
+		p.Run(func(frame decodedFrame) {
+			applied = append(applied, binary.BigEndian.Uint32(frame.data[1:5]))
+			receivers = append(receivers, frame.receivers...)
+		})
+
+		// all call replies, numbered
+		for i := 0; i < testData.frames; i++ {
+			data := []byte{0x5d, 0, 0, 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(data[1:5], uint32(i))
+			p.Submit(data, []string{fmt.Sprintf("input%d", i%2)})
+		}
+		p.Close()
+
+		// everything is applied by the time Close returns, in the order submitted
+		status := p.Status()
+		assert.Equal(uint64(testData.frames), status.Submitted, testMsg+"submitted")
+		assert.Equal(status.Submitted, status.Applied, testMsg+"applied")
+		assert.Equal(0, status.Queued, testMsg+"queued")
+		if assert.Len(applied, testData.frames, testMsg+"applied frames") {
+			inOrder := true
+			for i, n := range applied {
+				if n != uint32(i) || receivers[i] != fmt.Sprintf("input%d", i%2) {
+					inOrder = false
+					break
+				}
+			}
+			assert.True(inOrder, testMsg+"order")
+		}
+	}
+}
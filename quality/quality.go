@@ -0,0 +1,258 @@
+package quality
+
+// Position quality indicators.
+//
+// Airborne (TC 9-22) and surface (TC 5-8) position messages don't carry an explicit
+// integrity value. Instead, the type code is combined with NIC supplement bits to give the
+// Navigation Integrity Category (NIC) and containment radius (Rc):
+//  - NIC supplement-A: from aircraft operational status (version 1 "NIC supplement", version 2 "NIC supplement-A")
+//  - NIC supplement-B: from the airborne position message (version 2 only, the SAF bit in earlier versions)
+//  - NIC supplement-C: from surface aircraft operational status (version 2 only)
+// Version 0 transponders report NUCp rather than NIC, the horizontal protection limit is reported as Rc.
+//
+// Accuracy (NACp/NACv) and integrity level (SIL) come from operational status and airborne velocity.
+// See: https://mode-s.org/decode/content/ads-b/7-uncertainty.html
+
+// metres per nautical mile
+const nm = 1852.0
+
+type Inputs struct {
+	Tc      int // type code of the position message
+	Version int // ADS-B version, 0 if operational status has not been received
+
+	NICSupplementA int // NIC supplement-A (or version 1 NIC supplement)
+	NICSupplementB int // NIC supplement-B, airborne position only
+	NICSupplementC int // NIC supplement-C, surface position only
+
+	// Navigational accuracy category - position
+	NACpKnown bool
+	NACp      int
+
+	// Navigational accuracy category - velocity
+	NACvKnown bool
+	NACv      int
+
+	// Source integrity level
+	SILKnown      bool
+	SIL           int
+	SILSupplement int
+}
+
+type Indicators struct {
+	// Navigation integrity category & containment radius (m)
+	NICKnown bool
+	NIC      int
+	Rc       float64 // 0 if unknown
+
+	// Navigational accuracy category - position & estimated position uncertainty (m, 95%)
+	NACpKnown bool
+	NACp      int
+	EPU       float64 // 0 if unknown
+
+	// Navigational accuracy category - velocity & horizontal velocity error (m/s, 95%)
+	NACvKnown        bool
+	NACv             int
+	VelocityAccuracy float64 // 0 if unknown
+
+	// Source integrity level & probability of exceeding Rc without alert
+	SILKnown       bool
+	SIL            int
+	SILProbability float64 // 0 if unknown
+	SILPerSample   bool    // probability is per sample, otherwise per hour
+}
+
+// estimated position uncertainty (m) by NACp
+var nacpEPU = []float64{0, 10 * nm, 4 * nm, 2 * nm, 1 * nm, 0.5 * nm, 0.3 * nm, 0.1 * nm, 0.05 * nm, 30, 10, 3}
+
+// horizontal velocity error (m/s) by NACv
+var nacvAccuracy = []float64{0, 10, 3, 1, 0.3}
+
+// probability of exceeding Rc without alert by SIL
+var silProbability = []float64{0, 1e-3, 1e-5, 1e-7}
+
+func Compute(in Inputs) (q Indicators) {
+	// returns quality indicators for a position message
+
+	q.NIC, q.Rc, q.NICKnown = NIC(in.Tc, in.Version, in.NICSupplementA, in.NICSupplementB, in.NICSupplementC)
+
+	if in.NACpKnown {
+		q.NACpKnown = true
+		q.NACp = in.NACp
+		q.EPU, _ = EPU(in.NACp)
+	}
+
+	if in.NACvKnown {
+		q.NACvKnown = true
+		q.NACv = in.NACv
+		q.VelocityAccuracy, _ = VelocityAccuracy(in.NACv)
+	}
+
+	if in.SILKnown {
+		q.SILKnown = true
+		q.SIL = in.SIL
+		q.SILProbability, _ = SILProbability(in.SIL)
+		q.SILPerSample = in.SILSupplement == 1
+	}
+
+	return q
+}
+
+func NIC(tc, version, nica, nicb, nicc int) (nic int, rc float64, ok bool) {
+	// returns NIC and containment radius (m) from type code, ADS-B version and NIC supplements
+	// rc is 0 when the containment radius is unknown
+	switch {
+	case tc >= 5 && tc <= 8:
+		return surfaceNIC(tc, version, nica, nicc)
+	case tc >= 9 && tc <= 18, tc >= 20 && tc <= 22:
+		return airborneNIC(tc, version, nica, nicb)
+	}
+	return 0, 0, false
+}
+
+func airborneNIC(tc, version, nica, nicb int) (nic int, rc float64, ok bool) {
+	// airborne position, TC 9-18 & 20-22
+
+	// version 0: NUCp, reported as NIC with horizontal protection limit as Rc
+	if version == 0 {
+		switch tc {
+		case 9, 20:
+			return 9, 7.5, true
+		case 10, 21:
+			return 8, 25, true
+		case 11:
+			return 7, 0.1 * nm, true
+		case 12:
+			return 6, 0.2 * nm, true
+		case 13:
+			return 5, 0.5 * nm, true
+		case 14:
+			return 4, 1 * nm, true
+		case 15:
+			return 3, 2 * nm, true
+		case 16:
+			return 2, 10 * nm, true
+		case 17:
+			return 1, 20 * nm, true
+		}
+		return 0, 0, true
+	}
+
+	// version 1 has a single NIC supplement, equivalent to NIC supplement-A & NIC supplement-B both set
+	if version == 1 {
+		nicb = nica
+	}
+
+	switch tc {
+	case 9, 20:
+		return 11, 7.5, true
+	case 10, 21:
+		return 10, 25, true
+	case 11:
+		if nica == 1 && nicb == 1 {
+			return 9, 75, true
+		}
+		return 8, 0.1 * nm, true
+	case 12:
+		return 7, 0.2 * nm, true
+	case 13:
+		switch {
+		case nica == 0 && nicb == 1:
+			return 6, 0.3 * nm, true
+		case nica == 0 && nicb == 0:
+			return 6, 0.5 * nm, true
+		}
+		return 6, 0.6 * nm, true
+	case 14:
+		return 5, 1 * nm, true
+	case 15:
+		return 4, 2 * nm, true
+	case 16:
+		if nica == 1 && nicb == 1 {
+			return 3, 4 * nm, true
+		}
+		return 2, 8 * nm, true
+	case 17:
+		return 1, 20 * nm, true
+	}
+
+	// TC 18 & 22: Rc unknown
+	return 0, 0, true
+}
+
+func surfaceNIC(tc, version, nica, nicc int) (nic int, rc float64, ok bool) {
+	// surface position, TC 5-8
+
+	// version 0: NUCp, reported as NIC with horizontal protection limit as Rc
+	if version == 0 {
+		switch tc {
+		case 5:
+			return 9, 7.5, true
+		case 6:
+			return 8, 25, true
+		case 7:
+			return 7, 0.1 * nm, true
+		}
+		return 6, 0.2 * nm, true
+	}
+
+	switch tc {
+	case 5:
+		return 11, 7.5, true
+	case 6:
+		return 10, 25, true
+	case 7:
+		if nica == 1 {
+			return 9, 75, true
+		}
+		return 8, 0.1 * nm, true
+	}
+
+	// TC 8: only version 2 can report a containment radius
+	if version == 2 {
+		switch {
+		case nica == 1 && nicc == 1:
+			return 7, 0.2 * nm, true
+		case nica == 1 && nicc == 0:
+			return 6, 0.3 * nm, true
+		case nica == 0 && nicc == 1:
+			return 6, 0.6 * nm, true
+		}
+	}
+	return 0, 0, true
+}
+
+func EPU(nacp int) (epu float64, ok bool) {
+	// returns estimated position uncertainty (m, 95%) for NACp
+	// NACp 0 is unknown accuracy
+	if nacp <= 0 || nacp >= len(nacpEPU) {
+		return 0, false
+	}
+	return nacpEPU[nacp], true
+}
+
+func VelocityAccuracy(nacv int) (accuracy float64, ok bool) {
+	// returns horizontal velocity error (m/s, 95%) for NACv
+	// NACv 0 is unknown accuracy
+	if nacv <= 0 || nacv >= len(nacvAccuracy) {
+		return 0, false
+	}
+	return nacvAccuracy[nacv], true
+}
+
+func SILProbability(sil int) (probability float64, ok bool) {
+	// returns probability of exceeding the containment radius without alert for SIL
+	// SIL 0 is unknown
+	if sil <= 0 || sil >= len(silProbability) {
+		return 0, false
+	}
+	return silProbability[sil], true
+}
+
+func (q *Indicators) MeetsMinimumNIC(minNIC int) bool {
+	// returns true if the position's NIC is at least minNIC
+	// positions of unknown integrity only pass when minNIC is 0
+	if minNIC <= 0 {
+		return true
+	}
+	return q.NICKnown && q.NIC >= minNIC
+}
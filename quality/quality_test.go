@@ -0,0 +1,120 @@
+package quality
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNIC(t *testing.T) {
+	var testTable = []struct {
+		tc, version, nica, nicb, nicc int
+		expectedNIC                   int
+		expectedRc                    float64
+		expectedOk                    bool
+	}{
+		// version 2 airborne
+		{tc: 9, version: 2, expectedNIC: 11, expectedRc: 7.5, expectedOk: true},
+		{tc: 11, version: 2, nica: 1, nicb: 1, expectedNIC: 9, expectedRc: 75, expectedOk: true},
+		{tc: 11, version: 2, expectedNIC: 8, expectedRc: 185.2, expectedOk: true},
+		{tc: 13, version: 2, nicb: 1, expectedNIC: 6, expectedRc: 555.6, expectedOk: true},
+		{tc: 13, version: 2, expectedNIC: 6, expectedRc: 926, expectedOk: true},
+		{tc: 13, version: 2, nica: 1, nicb: 1, expectedNIC: 6, expectedRc: 1111.2, expectedOk: true},
+		{tc: 16, version: 2, nica: 1, nicb: 1, expectedNIC: 3, expectedRc: 7408, expectedOk: true},
+		{tc: 16, version: 2, expectedNIC: 2, expectedRc: 14816, expectedOk: true},
+		{tc: 18, version: 2, expectedNIC: 0, expectedRc: 0, expectedOk: true},
+		{tc: 21, version: 2, expectedNIC: 10, expectedRc: 25, expectedOk: true},
+
+		// version 1 airborne, single NIC supplement
+		{tc: 11, version: 1, nica: 1, expectedNIC: 9, expectedRc: 75, expectedOk: true},
+		{tc: 13, version: 1, nica: 1, expectedNIC: 6, expectedRc: 1111.2, expectedOk: true},
+
+		// version 0 airborne (NUCp)
+		{tc: 11, version: 0, nica: 1, nicb: 1, expectedNIC: 7, expectedRc: 185.2, expectedOk: true},
+		{tc: 16, version: 0, expectedNIC: 2, expectedRc: 18520, expectedOk: true},
+
+		// version 2 surface
+		{tc: 5, version: 2, expectedNIC: 11, expectedRc: 7.5, expectedOk: true},
+		{tc: 7, version: 2, nica: 1, expectedNIC: 9, expectedRc: 75, expectedOk: true},
+		{tc: 8, version: 2, nica: 1, nicc: 1, expectedNIC: 7, expectedRc: 370.4, expectedOk: true},
+		{tc: 8, version: 2, nicc: 1, expectedNIC: 6, expectedRc: 1111.2, expectedOk: true},
+		{tc: 8, version: 2, expectedNIC: 0, expectedRc: 0, expectedOk: true},
+
+		// version 1 surface
+		{tc: 8, version: 1, nica: 1, expectedNIC: 0, expectedRc: 0, expectedOk: true},
+
+		// not a position message
+		{tc: 19, version: 2, expectedOk: false},
+		{tc: 4, version: 2, expectedOk: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("tc: %d, version: %d, nica: %d, nicb: %d, nicc: %d, ", testData.tc, testData.version, testData.nica, testData.nicb, testData.nicc)
+		nic, rc, ok := NIC(testData.tc, testData.version, testData.nica, testData.nicb, testData.nicc)
+		assert.Equal(testData.expectedOk, ok, testMsg+"ok")
+		assert.Equal(testData.expectedNIC, nic, testMsg+"NIC")
+		assert.InDelta(testData.expectedRc, rc, 0.001, testMsg+"Rc")
+	}
+}
+
+func TestCompute(t *testing.T) {
+	assert := assert.New(t)
+
+	q := Compute(Inputs{
+		Tc:             11,
+		Version:        2,
+		NICSupplementA: 1,
+		NICSupplementB: 1,
+		NACpKnown:      true,
+		NACp:           9,
+		SILKnown:       true,
+		SIL:            3,
+		SILSupplement:  0,
+	})
+	assert.True(q.NICKnown)
+	assert.Equal(9, q.NIC)
+	assert.Equal(75.0, q.Rc)
+	assert.True(q.NACpKnown)
+	assert.Equal(30.0, q.EPU)
+	assert.False(q.NACvKnown)
+	assert.True(q.SILKnown)
+	assert.Equal(1e-7, q.SILProbability)
+	assert.False(q.SILPerSample)
+
+	assert.True(q.MeetsMinimumNIC(0))
+	assert.True(q.MeetsMinimumNIC(9))
+	assert.False(q.MeetsMinimumNIC(10))
+
+	// unknown integrity
+	q = Compute(Inputs{Tc: 19, Version: 2})
+	assert.False(q.NICKnown)
+	assert.True(q.MeetsMinimumNIC(0))
+	assert.False(q.MeetsMinimumNIC(1))
+}
+
+func TestAccuracy(t *testing.T) {
+	assert := assert.New(t)
+
+	epu, ok := EPU(0)
+	assert.False(ok)
+	assert.Equal(0.0, epu)
+	epu, ok = EPU(11)
+	assert.True(ok)
+	assert.Equal(3.0, epu)
+	_, ok = EPU(12)
+	assert.False(ok)
+
+	acc, ok := VelocityAccuracy(2)
+	assert.True(ok)
+	assert.Equal(3.0, acc)
+	_, ok = VelocityAccuracy(5)
+	assert.False(ok)
+
+	p, ok := SILProbability(1)
+	assert.True(ok)
+	assert.Equal(1e-3, p)
+	_, ok = SILProbability(0)
+	assert.False(ok)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"beastdecoder/vesselstate"
+	"sync"
+)
+
+// Positions are counted in bands of this width (NM) from the receiver
+const rangeBandSize = 25.0
+
+// Number of bands counted, positions beyond are counted in the last band
+const rangeBands = 12
+
+// Range of the positions decoded from frames heard by each input, for positions relative to the receiver location
+type rangeStats struct {
+	mu        sync.Mutex
+	receivers map[string]*receiverRange
+	current   []string // inputs that heard the frame being applied
+}
+
+// Range of the positions decoded from frames heard by an input, as served on the web interface
+type receiverRange struct {
+	Positions uint64   `json:"positions"`
+	Rejected  uint64   `json:"rejected"`  // beyond max range
+	MaxRange  float64  `json:"max_range"` // NM, furthest position accepted
+	Bands     []uint64 `json:"bands"`     // positions in each band of rangeBandSize NM, nearest first
+}
+
+func (r *rangeStats) Apply(receivers []string, apply func()) {
+	// runs apply, counting positions decoded as it runs against receivers
+	// vessel db observers are run before the update returns, so positions are counted against the frame that caused them
+	r.mu.Lock()
+	r.current = receivers
+	r.mu.Unlock()
+
+	apply()
+
+	r.mu.Lock()
+	r.current = nil
+	r.mu.Unlock()
+}
+
+func (r *rangeStats) AddPosition(pos vesselstate.Position) {
+	// counts a position against the inputs that heard the frame being applied
+	if !pos.RangeKnown {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, receiver := range r.current {
+		rr := r.receiver(receiver)
+		rr.Positions++
+		if pos.Range > rr.MaxRange {
+			rr.MaxRange = pos.Range
+		}
+		band := int(pos.Range / rangeBandSize)
+		if band >= rangeBands {
+			band = rangeBands - 1
+		}
+		rr.Bands[band]++
+	}
+}
+
+func (r *rangeStats) AddRejected(pos vesselstate.Position) {
+	// counts a position beyond max range against the inputs that heard the frame being applied
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, receiver := range r.current {
+		r.receiver(receiver).Rejected++
+	}
+}
+
+func (r *rangeStats) receiver(receiver string) *receiverRange {
+	if r.receivers == nil {
+		r.receivers = make(map[string]*receiverRange)
+	}
+	rr, ok := r.receivers[receiver]
+	if !ok {
+		rr = &receiverRange{
+			Bands: make([]uint64, rangeBands),
+		}
+		r.receivers[receiver] = rr
+	}
+	return rr
+}
+
+func (r *rangeStats) Status() map[string]receiverRange {
+	// returns a copy of each input's range statistics
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := make(map[string]receiverRange, len(r.receivers))
+	for receiver, rr := range r.receivers {
+		c := *rr
+		c.Bands = append([]uint64(nil), rr.Bands...)
+		status[receiver] = c
+	}
+	return status
+}
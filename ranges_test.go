@@ -0,0 +1,86 @@
+package main
+
+import (
+	"beastdecoder/vesselstate"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeStats(t *testing.T) {
+	type testFrame struct {
+		receivers []string
+		ranges    []float64 // of positions decoded from the frame (NM)
+		rejected  int       // positions beyond max range
+	}
+
+	// define test data
+	var testTable = []struct {
+		name     string
+		frames   []testFrame
+		expected map[string]receiverRange
+	}{
+		{
+			name:     "no frames",
+			expected: map[string]receiverRange{},
+		},
+		{
+			name: "counted against receivers of the frame",
+			frames: []testFrame{
+				{receivers: []string{"a"}, ranges: []float64{10}},
+				{receivers: []string{"a", "b"}, ranges: []float64{30}},
+				{receivers: []string{"b"}, rejected: 1},
+			},
+			expected: map[string]receiverRange{
+				"a": {Positions: 2, MaxRange: 30, Bands: []uint64{1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+				"b": {Positions: 1, Rejected: 1, MaxRange: 30, Bands: []uint64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			},
+		},
+		{
+			name: "beyond last band",
+			frames: []testFrame{
+				{receivers: []string{"a"}, ranges: []float64{rangeBandSize*rangeBands - 1, rangeBandSize * rangeBands, 1000}},
+			},
+			expected: map[string]receiverRange{
+				"a": {Positions: 3, MaxRange: 1000, Bands: []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3}},
+			},
+		},
+		{
+			name: "range unknown",
+			frames: []testFrame{
+				{receivers: []string{"a"}, ranges: []float64{-1}},
+			},
+			expected: map[string]receiverRange{},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("ranges: %s, ", testData.name)
+		var ranges rangeStats
+		for _, frame := range testData.frames {
+			ranges.Apply(frame.receivers, func() {
+				for _, r := range frame.ranges {
+					ranges.AddPosition(vesselstate.Position{RangeKnown: r >= 0, Range: r})
+				}
+				for i := 0; i < frame.rejected; i++ {
+					ranges.AddRejected(vesselstate.Position{RangeKnown: true, Range: 500})
+				}
+			})
+		}
+
+		// positions outside Apply aren't counted
+		ranges.AddPosition(vesselstate.Position{RangeKnown: true, Range: 10})
+		ranges.AddRejected(vesselstate.Position{RangeKnown: true, Range: 500})
+
+		status := ranges.Status()
+		assert.Equal(testData.expected, status, testMsg+"status")
+
+		// status is a copy
+		for _, rr := range status {
+			rr.Bands[0]++
+		}
+		assert.Equal(testData.expected, ranges.Status(), testMsg+"status copy")
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"beastdecoder/vesselstate"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Track formats
+const trackFormatCSV = "csv"
+const trackFormatGeoJSON = "geojson"
+const trackFormatKML = "kml"
+
+// Simulated time starts here, and is shifted to the capture's start time on output
+var replayEpoch = time.Unix(0, 0).UTC()
+
+// MLAT timestamps going backwards by more than this are treated as a receiver clock reset
+const mlatResetThreshold = 1.0 // seconds
+
+const feetToMetres = 0.3048
+
+// Simulated time for replaying a capture, advanced by MLAT timestamps
+type replayClock struct {
+	vesselstate.ManualClock
+	started  bool
+	lastMLAT float64       // seconds
+	elapsed  time.Duration // since the first message
+}
+
+func (c *replayClock) advance(mlat float64) {
+	// moves simulated time forward to an MLAT timestamp (seconds)
+	if !c.started {
+		c.started = true
+		c.lastMLAT = mlat
+		c.Set(replayEpoch)
+		return
+	}
+
+	delta := mlat - c.lastMLAT
+	switch {
+	case delta >= 0:
+		c.elapsed += time.Duration(math.Round(delta * float64(time.Second)))
+		c.lastMLAT = mlat
+	case delta < -mlatResetThreshold:
+		// receiver clock reset, continue from here
+		c.lastMLAT = mlat
+	}
+	// otherwise messages slightly out of order, don't go back in time
+
+	c.Set(replayEpoch.Add(c.elapsed))
+}
+
+// An aircraft's reconstructed track
+type track struct {
+	icao     int
+	callsign string // last known
+	points   []vesselstate.Position
+}
+
+func reconstructTracks(ctx *cli.Context) error {
+	// replays a capture through the vessel db with simulated time, and writes each aircraft's track
+
+	initLogger(ctx)
+
+	// stop replaying on SIGINT/SIGTERM, writing tracks reconstructed so far
+	sigCtx, stop := notifyContext(ctx)
+	defer stop()
+
+	in, modTime, err := openCapture(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	r := bufio.NewReader(in)
+
+	// vessel db driven by capture timestamps
+	var clock replayClock
+	clock.Set(replayEpoch)
+	var replay vesselstate.Vessels
+	replay.SetClock(&clock)
+	replay.Init()
+	configureVessels(ctx, &replay)
+
+	tracks := make(map[int]*track)
+	replay.OnPosition(func(pos vesselstate.Position) {
+		t, ok := tracks[pos.ICAO]
+		if !ok {
+			t = &track{icao: pos.ICAO}
+			tracks[pos.ICAO] = t
+		}
+		if pos.Callsign != "" {
+			t.callsign = pos.Callsign
+		}
+		t.points = append(t.points, pos)
+	})
+
+	// replay
+	count, untimed := 0, 0
+	lastEvicted := replayEpoch
+	handle := func(captured capturedMessage) error {
+		if err := sigCtx.Err(); err != nil {
+			return err
+		}
+		if captured.timestampKnown {
+			clock.advance(captured.timestamp)
+		} else {
+			untimed++
+		}
+
+		// evict stale vessels & positions every simulated second
+		if clock.Now().Sub(lastEvicted) >= time.Second {
+			replay.Evict()
+			lastEvicted = clock.Now()
+		}
+
+		updateVessels(&replay, captured.data)
+		count++
+		return nil
+	}
+
+	format := ctx.String("input-format")
+	if format == captureFormatAuto {
+		format = detectCaptureFormat(r)
+	}
+	switch format {
+	case captureFormatBEAST:
+		err = readBEASTCapture(r, handle)
+	case captureFormatAVR:
+		err = readAVRCapture(r, handle)
+	default:
+		err = fmt.Errorf("unknown capture format (format: %s)", format)
+	}
+	if errors.Is(err, context.Canceled) {
+		log.Warn().Msg("interrupted, writing tracks reconstructed so far")
+	} else if err != nil {
+		return err
+	}
+	if untimed > 0 {
+		log.Warn().Int("untimed", untimed).Msg("messages without timestamps were replayed at the time of the previous message")
+	}
+
+	// shift simulated time to wall clock, the capture is assumed to have ended when it was last modified
+	duration := clock.Now().Sub(replayEpoch)
+	start := modTime.Add(-duration)
+	if ctx.IsSet("start") {
+		start, err = time.Parse(time.RFC3339, ctx.String("start"))
+		if err != nil {
+			return fmt.Errorf("could not parse start time: %w", err)
+		}
+	}
+	shift := start.Sub(replayEpoch)
+
+	// tracks in ICAO order, dropping those too short to draw
+	var output []*track
+	for _, t := range tracks {
+		if len(t.points) < ctx.Int("min-points") {
+			continue
+		}
+		for i := range t.points {
+			t.points[i].Time = t.points[i].Time.Add(shift)
+		}
+		output = append(output, t)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].icao < output[j].icao
+	})
+
+	out, err := createOutput(ctx, ctx.String("output"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	switch ctx.String("format") {
+	case trackFormatCSV:
+		err = writeTracksCSV(w, output)
+	case trackFormatGeoJSON:
+		err = writeTracksGeoJSON(w, output)
+	case trackFormatKML:
+		err = writeTracksKML(w, output)
+	default:
+		err = fmt.Errorf("unknown track format (format: %s)", ctx.String("format"))
+	}
+
+	log.Info().Int("messages", count).Int("tracks", len(output)).Dur("duration", duration).Msg("track reconstruction complete")
+	return err
+}
+
+func (t *track) name() string {
+	// callsign & ICAO, or just ICAO if callsign unknown
+	if t.callsign != "" {
+		return fmt.Sprintf("%s (%06X)", t.callsign, t.icao)
+	}
+	return fmt.Sprintf("%06X", t.icao)
+}
+
+func formatTrackTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+func writeTracksCSV(w io.Writer, tracks []*track) error {
+	// one row per position
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"icao", "callsign", "time", "lat", "lon", "altitude", "ground_speed", "track", "vertical_rate"})
+	if err != nil {
+		return err
+	}
+	for _, t := range tracks {
+		for _, p := range t.points {
+			row := []string{
+				fmt.Sprintf("%06X", t.icao),
+				t.callsign,
+				formatTrackTime(p.Time),
+				strconv.FormatFloat(p.Lat, 'f', 6, 64),
+				strconv.FormatFloat(p.Lon, 'f', 6, 64),
+				"", "", "", "",
+			}
+			if p.AltitudeKnown {
+				row[5] = strconv.Itoa(p.Altitude)
+			}
+			if p.GroundSpeedKnown {
+				row[6] = strconv.FormatFloat(p.GroundSpeed, 'f', 1, 64)
+			}
+			if p.TrackKnown {
+				row[7] = strconv.FormatFloat(p.Track, 'f', 1, 64)
+			}
+			if p.VerticalRateKnown {
+				row[8] = strconv.Itoa(p.VerticalRate)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+func writeTracksGeoJSON(w io.Writer, tracks []*track) error {
+	// one LineString feature per aircraft, per-position values are arrays in properties (null if unknown)
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []geoJSONFeature{},
+	}
+	for _, t := range tracks {
+		coordinates := make([][]float64, 0, len(t.points))
+		times := make([]string, 0, len(t.points))
+		altitudes := make([]any, 0, len(t.points))
+		groundSpeeds := make([]any, 0, len(t.points))
+		trackAngles := make([]any, 0, len(t.points))
+		verticalRates := make([]any, 0, len(t.points))
+		for _, p := range t.points {
+			coordinates = append(coordinates, []float64{p.Lon, p.Lat})
+			times = append(times, formatTrackTime(p.Time))
+			altitudes = append(altitudes, knownOrNil(p.AltitudeKnown, p.Altitude))
+			groundSpeeds = append(groundSpeeds, knownOrNil(p.GroundSpeedKnown, p.GroundSpeed))
+			trackAngles = append(trackAngles, knownOrNil(p.TrackKnown, p.Track))
+			verticalRates = append(verticalRates, knownOrNil(p.VerticalRateKnown, p.VerticalRate))
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONGeometry{
+				Type:        "LineString",
+				Coordinates: coordinates,
+			},
+			Properties: map[string]any{
+				"icao":           fmt.Sprintf("%06X", t.icao),
+				"callsign":       t.callsign,
+				"times":          times,
+				"altitudes":      altitudes,
+				"ground_speeds":  groundSpeeds,
+				"tracks":         trackAngles,
+				"vertical_rates": verticalRates,
+			},
+		})
+	}
+	return json.NewEncoder(w).Encode(collection)
+}
+
+func knownOrNil(known bool, v any) any {
+	if !known {
+		return nil
+	}
+	return v
+}
+
+func writeTracksKML(w io.Writer, tracks []*track) error {
+	// one time-stamped gx:Track placemark per aircraft, altitude in metres
+	// points without altitude carry the last known altitude (the first, before it is known),
+	// and tracks without any altitude are clamped to the ground rather than drawn at sea level
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">`)
+	fmt.Fprintln(w, `<Document>`)
+	fmt.Fprintln(w, `<name>beastdecoder tracks</name>`)
+	for _, t := range tracks {
+		fmt.Fprintln(w, `<Placemark>`)
+		fmt.Fprint(w, `<name>`)
+		if err := xml.EscapeText(w, []byte(t.name())); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, `</name>`)
+		fmt.Fprintln(w, `<gx:Track>`)
+		altitudeKnown := false
+		altitude := 0.0
+		for _, p := range t.points {
+			if p.AltitudeKnown {
+				altitudeKnown = true
+				altitude = float64(p.Altitude) * feetToMetres
+				break
+			}
+		}
+		if altitudeKnown {
+			fmt.Fprintln(w, `<altitudeMode>absolute</altitudeMode>`)
+		} else {
+			fmt.Fprintln(w, `<altitudeMode>clampToGround</altitudeMode>`)
+		}
+		for _, p := range t.points {
+			fmt.Fprintf(w, "<when>%s</when>\n", formatTrackTime(p.Time))
+		}
+		for _, p := range t.points {
+			if p.AltitudeKnown {
+				altitude = float64(p.Altitude) * feetToMetres
+			}
+			fmt.Fprintf(w, "<gx:coord>%f %f %.1f</gx:coord>\n", p.Lon, p.Lat, altitude)
+		}
+		fmt.Fprintln(w, `</gx:Track>`)
+		fmt.Fprintln(w, `</Placemark>`)
+	}
+	fmt.Fprintln(w, `</Document>`)
+	_, err := fmt.Fprintln(w, `</kml>`)
+	return err
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"beastdecoder/vesselstate"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// airborne positions from ICAO 40621D, one per second from MLAT time 1s, even/odd/even/odd
+// https://mode-s.org/decode/content/ads-b/3-airborne-position.html
+const testTrackCapture = "@000000B71B008D40621D58C382D690C8AC2863A7;\n" +
+	"@0000016E36008D40621D58C386435CC412692AD6;\n" +
+	"@0000022551008D40621D58C382D690C8AC2863A7;\n" +
+	"@000002DC6C008D40621D58C386435CC412692AD6;\n"
+
+func TestReplayClockAdvance(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name            string
+		mlat            []float64 // seconds
+		expectedElapsed []time.Duration
+	}{
+		{
+			name:            "in order",
+			mlat:            []float64{100, 100.5, 101, 103},
+			expectedElapsed: []time.Duration{0, time.Millisecond * 500, time.Second, time.Second * 3},
+		},
+		{
+			name:            "slightly out of order",
+			mlat:            []float64{100, 101, 100.5, 101.5},
+			expectedElapsed: []time.Duration{0, time.Second, time.Second, time.Millisecond * 1500},
+		},
+		{
+			name:            "clock reset",
+			mlat:            []float64{100, 101, 5, 6},
+			expectedElapsed: []time.Duration{0, time.Second, time.Second, time.Second * 2},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("mlat: %s, ", testData.name)
+		var clock replayClock
+		for i, mlat := range testData.mlat {
+			clock.advance(mlat)
+			assert.Equal(testData.expectedElapsed[i], clock.Now().Sub(replayEpoch), testMsg+fmt.Sprintf("elapsed %d", i))
+		}
+	}
+}
+
+func runTestTrack(t *testing.T, capture string, args ...string) []byte {
+	// runs the track subcommand over a capture, returning its output
+	path := filepath.Join(t.TempDir(), "capture.avr")
+	if err := os.WriteFile(path, []byte(capture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	app := newApp()
+	app.Writer = &out
+	args = append([]string{"beastdecoder", "track", "--start", "2024-01-01T00:00:00Z"}, args...)
+	if err := app.Run(append(args, path)); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestReconstructTracksCSV(t *testing.T) {
+	// define test data, positions are calculated from the third message (2s after the first)
+	var testTable = []struct {
+		name         string
+		args         []string
+		expectedRows [][]string
+	}{
+		{
+			name: "track",
+			expectedRows: [][]string{
+				{"icao", "callsign", "time", "lat", "lon", "altitude", "ground_speed", "track", "vertical_rate"},
+				{"40621D", "", "2024-01-01T00:00:02.000Z", "52.257202", "3.919373", "38000", "", "", ""},
+				{"40621D", "", "2024-01-01T00:00:03.000Z", "52.265780", "3.938913", "38000", "", "", ""},
+			},
+		},
+		{
+			name: "too few points",
+			args: []string{"--min-points", "3"},
+			expectedRows: [][]string{
+				{"icao", "callsign", "time", "lat", "lon", "altitude", "ground_speed", "track", "vertical_rate"},
+			},
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("track: %s, ", testData.name)
+		out := runTestTrack(t, testTrackCapture, append([]string{"--format", trackFormatCSV}, testData.args...)...)
+		rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+		assert.NoError(err, testMsg+"csv")
+		assert.Equal(testData.expectedRows, rows, testMsg+"rows")
+	}
+}
+
+func TestReconstructTracksGeoJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	out := runTestTrack(t, testTrackCapture, "--format", trackFormatGeoJSON)
+	var collection struct {
+		Type     string
+		Features []struct {
+			Type     string
+			Geometry struct {
+				Type        string
+				Coordinates [][]float64
+			}
+			Properties map[string]any
+		}
+	}
+	assert.NoError(json.Unmarshal(out, &collection))
+	assert.Equal("FeatureCollection", collection.Type)
+	if !assert.Len(collection.Features, 1) {
+		return
+	}
+
+	feature := collection.Features[0]
+	assert.Equal("Feature", feature.Type)
+	assert.Equal("LineString", feature.Geometry.Type)
+	if assert.Len(feature.Geometry.Coordinates, 2) {
+		// lon, lat
+		assert.InDelta(3.919373, feature.Geometry.Coordinates[0][0], 0.000001)
+		assert.InDelta(52.257202, feature.Geometry.Coordinates[0][1], 0.000001)
+		assert.InDelta(3.938913, feature.Geometry.Coordinates[1][0], 0.000001)
+		assert.InDelta(52.265780, feature.Geometry.Coordinates[1][1], 0.000001)
+	}
+	assert.Equal("40621D", feature.Properties["icao"])
+	assert.Equal([]any{"2024-01-01T00:00:02.000Z", "2024-01-01T00:00:03.000Z"}, feature.Properties["times"])
+	assert.Equal([]any{38000.0, 38000.0}, feature.Properties["altitudes"])
+	assert.Equal([]any{nil, nil}, feature.Properties["ground_speeds"])
+}
+
+func TestWriteTracksKML(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testPoints := func(altitudes ...int) (points []vesselstate.Position) {
+		// one point per second, altitude (ft) unknown if negative
+		for i, altitude := range altitudes {
+			points = append(points, vesselstate.Position{
+				Time:          start.Add(time.Second * time.Duration(i)),
+				Lat:           52,
+				Lon:           4,
+				AltitudeKnown: altitude >= 0,
+				Altitude:      altitude,
+			})
+		}
+		return points
+	}
+
+	// define test data
+	var testTable = []struct {
+		name                 string
+		points               []vesselstate.Position
+		expectedAltitudeMode string
+		expectedAltitudes    []string // metres
+	}{
+		{name: "known", points: testPoints(1000, 2000), expectedAltitudeMode: "absolute", expectedAltitudes: []string{"304.8", "609.6"}},
+		{name: "gaps carry last known", points: testPoints(-1, 1000, -1, 2000, -1), expectedAltitudeMode: "absolute", expectedAltitudes: []string{"304.8", "304.8", "304.8", "609.6", "609.6"}},
+		{name: "unknown", points: testPoints(-1, -1), expectedAltitudeMode: "clampToGround", expectedAltitudes: []string{"0.0", "0.0"}},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("track: %s, ", testData.name)
+		var out bytes.Buffer
+		err := writeTracksKML(&out, []*track{{icao: 0x40621D, points: testData.points}})
+		assert.NoError(err, testMsg+"writeTracksKML error")
+
+		var kml struct {
+			Placemarks []struct {
+				AltitudeMode string   `xml:"Track>altitudeMode"`
+				Coords       []string `xml:"Track>coord"`
+			} `xml:"Document>Placemark"`
+		}
+		assert.NoError(xml.Unmarshal(out.Bytes(), &kml), testMsg+"xml")
+		if !assert.Len(kml.Placemarks, 1, testMsg+"placemarks") {
+			continue
+		}
+		assert.Equal(testData.expectedAltitudeMode, kml.Placemarks[0].AltitudeMode, testMsg+"altitude mode")
+		var altitudes []string
+		for _, coord := range kml.Placemarks[0].Coords {
+			fields := strings.Fields(coord)
+			altitudes = append(altitudes, fields[len(fields)-1])
+		}
+		assert.Equal(testData.expectedAltitudes, altitudes, testMsg+"altitudes")
+	}
+}
@@ -0,0 +1,54 @@
+package vesselstate
+
+import (
+	"sync"
+	"time"
+)
+
+// Provides the current time, so vessel state can be driven by simulated time (eg: when replaying a capture)
+type Clock interface {
+	Now() time.Time
+}
+
+// Wall clock time
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// A clock that only moves when set, for tests & replaying captures
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Set(t time.Time) {
+	// sets the current time
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func (c *ManualClock) Advance(d time.Duration) {
+	// moves the current time forward
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (vdb *Vessels) SetClock(clock Clock) {
+	// sets the clock used to timestamp updates & evict stale vessels, call before Init
+	vdb.clock = clock
+}
+
+func (vdb *Vessels) now() time.Time {
+	// returns the current time from the vessel db's clock
+	return vdb.clock.Now()
+}
@@ -0,0 +1,170 @@
+package vesselstate
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Kind of change to a vessel's state
+type EventType uint8
+
+const EventAdded = EventType(1)     // first message from a vessel
+const EventRemoved = EventType(2)   // vessel evicted after no messages
+const EventPosition = EventType(3)  // position calculated
+const EventCallsign = EventType(4)  // callsign first received or changed
+const EventSquawk = EventType(5)    // squawk code first received or changed
+const EventEmergency = EventType(6) // emergency declared or cleared, see Vessel.Emergency
+const EventGround = EventType(7)    // landed or took off, see Vessel.Airborne
+
+func (t EventType) String() string {
+	switch t {
+	case EventAdded:
+		return "added"
+	case EventRemoved:
+		return "removed"
+	case EventPosition:
+		return "position"
+	case EventCallsign:
+		return "callsign"
+	case EventSquawk:
+		return "squawk"
+	case EventEmergency:
+		return "emergency"
+	case EventGround:
+		return "ground"
+	}
+	return "unknown"
+}
+
+// A change to a vessel's state
+type Event struct {
+	Type   EventType
+	ICAO   int
+	Time   time.Time
+	Vessel VesselState // copy of the vessel's state as of the change, without history
+}
+
+// Events are dropped for subscribers with this many events waiting
+const subscriptionBufferLength = 256
+
+// Events selected by a filter, from Subscribe
+type Subscription struct {
+	C <-chan Event // closed by Close
+
+	c       chan Event
+	filter  Filter
+	types   []EventType
+	dropped atomic.Uint64
+	vdb     *Vessels
+}
+
+func (vdb *Vessels) Subscribe(filter Filter, types ...EventType) *Subscription {
+	// returns a subscription to changes of the given types (all types if none given), for vessels selected by filter
+	// the filter's MaxAge doesn't apply, and Bounds selects vessels by their last calculated position, even if
+	// it has since expired, so vessels are only selected once their position has been calculated
+	// events are dropped rather than delaying updates if the subscriber falls behind, see Dropped
+	c := make(chan Event, subscriptionBufferLength)
+	sub := &Subscription{
+		C:      c,
+		c:      c,
+		filter: filter,
+		types:  types,
+		vdb:    vdb,
+	}
+	sub.filter.MaxAge = 0
+
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.subscriptions = append(vdb.subscriptions, sub)
+	return sub
+}
+
+func (sub *Subscription) Close() {
+	// stops the subscription & closes its channel
+	sub.vdb.mu.Lock()
+	defer sub.vdb.mu.Unlock()
+	for i, s := range sub.vdb.subscriptions {
+		if s == sub {
+			sub.vdb.subscriptions = append(sub.vdb.subscriptions[:i:i], sub.vdb.subscriptions[i+1:]...)
+			close(sub.c)
+			return
+		}
+	}
+}
+
+func (sub *Subscription) Dropped() uint64 {
+	// returns the number of events dropped because the subscriber fell behind
+	return sub.dropped.Load()
+}
+
+func (sub *Subscription) wants(event *Event) bool {
+	// returns true if the subscription selects the event
+	if len(sub.types) > 0 {
+		found := false
+		for _, t := range sub.types {
+			if t == event.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if sub.filter.Bounds != nil {
+		v := &event.Vessel
+		if v.LatLonMethod == "" || !sub.filter.Bounds.Contains(v.Lat, v.Lon) {
+			return false
+		}
+	}
+	filter := sub.filter
+	filter.Bounds = nil
+	return filter.matches(&event.Vessel, event.Time)
+}
+
+func (vdb *Vessels) emitEvent(eventType EventType, icao int) {
+	// queues an event for subscribers, with a copy of the vessel's current state
+	// called with the db locked, events are sent once the update is complete
+
+	if len(vdb.subscriptions) == 0 || !vdb.isVesselTracked(icao) {
+		return
+	}
+
+	event := Event{
+		Type:   eventType,
+		ICAO:   icao,
+		Time:   vdb.now(),
+		Vessel: vdb.Vessels[icao].snapshot(false),
+	}
+	var subscriptions []*Subscription
+	for _, sub := range vdb.subscriptions {
+		if sub.wants(&event) {
+			subscriptions = append(subscriptions, sub)
+		}
+	}
+
+	vdb.notify(func() {
+		// subscriptions closed since are skipped, as their channel is closed
+		vdb.mu.RLock()
+		defer vdb.mu.RUnlock()
+		for _, sub := range subscriptions {
+			if !vdb.isSubscribed(sub) {
+				continue
+			}
+			select {
+			case sub.c <- event:
+			default:
+				sub.dropped.Add(1)
+			}
+		}
+	})
+}
+
+func (vdb *Vessels) isSubscribed(sub *Subscription) bool {
+	for _, s := range vdb.subscriptions {
+		if s == sub {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package vesselstate
+
+import "time"
+
+// Positions are kept in each vessel's history for this long by default
+const DefaultHistoryDuration = time.Minute * 5
+
+// Maximum positions kept in each vessel's history, regardless of duration (ie: 30 minutes at 2 per second)
+const maxHistoryPoints = 3600
+
+// Initial size of a vessel's history, grown as positions are added
+const minHistoryPoints = 16
+
+// Ring buffer of a vessel's recent positions
+type positionHistory struct {
+	buf   []Position // grows up to maxHistoryPoints, then wraps
+	start int        // index of the oldest position
+	n     int        // number of positions
+}
+
+func (h *positionHistory) add(pos Position) {
+	// adds a position, overwriting the oldest if full
+	if h.n == len(h.buf) {
+		if len(h.buf) == maxHistoryPoints {
+			h.buf[h.start] = pos
+			h.start = (h.start + 1) % len(h.buf)
+			return
+		}
+
+		// grow
+		size := len(h.buf) * 2
+		if size < minHistoryPoints {
+			size = minHistoryPoints
+		}
+		if size > maxHistoryPoints {
+			size = maxHistoryPoints
+		}
+		buf := make([]Position, size)
+		copy(buf, h.positions())
+		h.buf = buf
+		h.start = 0
+	}
+	h.buf[(h.start+h.n)%len(h.buf)] = pos
+	h.n++
+}
+
+func (h *positionHistory) prune(before time.Time) {
+	// drops positions older than before
+	for h.n > 0 && h.buf[h.start].Time.Before(before) {
+		h.buf[h.start] = Position{}
+		h.start = (h.start + 1) % len(h.buf)
+		h.n--
+	}
+}
+
+func (h *positionHistory) positions() []Position {
+	// returns a copy of the history, oldest first
+	positions := make([]Position, 0, h.n)
+	for i := 0; i < h.n; i++ {
+		positions = append(positions, h.buf[(h.start+i)%len(h.buf)])
+	}
+	return positions
+}
+
+func (vdb *Vessels) SetHistoryDuration(d time.Duration) {
+	// sets how long positions are kept in each vessel's history, 0 disables history
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.historyDuration = d
+	vdb.historyDurationSet = true
+}
+
+func (vdb *Vessels) addHistory(icao int, pos Position) {
+	// adds a position to a vessel's history, dropping positions older than the history duration
+	if vdb.historyDuration <= 0 || !vdb.isVesselTracked(icao) {
+		return
+	}
+	h := &vdb.Vessels[icao].history
+	h.add(pos)
+	h.prune(vdb.now().Add(-vdb.historyDuration))
+}
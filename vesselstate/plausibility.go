@@ -0,0 +1,147 @@
+package vesselstate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umahmood/haversine"
+)
+
+// Positions further than this (NM) from the receiver are discarded by default, beyond line of sight of any aircraft
+const DefaultMaxRange = 300.0
+
+// Surface positions further than this (NM) from the receiver are discarded, whatever the max range.
+// Surface CPR positions repeat every 90 degrees of latitude/longitude, and are decoded relative to the receiver.
+const maxSurfaceRange = 45.0
+
+// Decoded positions further from the vessel's last position than it could have travelled since are rejected.
+// Speeds are allowed this much error, ie: speed * 4/3 + 100 kt
+const positionSpeedFactor = 4.0 / 3
+const positionSpeedMargin = 100.0 // kt
+
+// Highest plausible speeds (kt) when a vessel's speed isn't known
+const maxAirborneSpeed = 1000.0
+const maxSurfaceSpeed = 200.0
+
+// Positions within this distance (NM) of the last position are always plausible, allowing for CPR & timing errors
+const positionMinRange = 1.0
+
+// Reported or derived speeds older than this aren't used to check positions
+const positionSpeedMaxAge = time.Second * 30
+
+// When speed isn't reported, it's derived from positions at least this far apart
+const derivedSpeedInterval = time.Second * 5
+
+// A vessel's position is known once this many plausible positions have been decoded, see PositionReliability
+const positionReliableThreshold = 2
+const maxPositionReliability = 4
+
+func (v *VesselState) plausibleSpeed(now time.Time, surface bool) float64 {
+	// returns the highest plausible speed (kt) of the vessel, from its reported or derived speed
+	var speed float64
+	switch {
+	case surface && v.SurfaceSpeedKnown:
+		speed = v.SurfaceSpeed
+	case !surface && v.VelocityKnown && now.Sub(v.VelocityUpdated) < positionSpeedMaxAge:
+		speed = v.VelocitySpeed
+	case v.derivedSpeedKnown && now.Sub(v.derivedSpeedTime) < positionSpeedMaxAge:
+		speed = v.derivedSpeed
+	case surface:
+		return maxSurfaceSpeed
+	default:
+		return maxAirborneSpeed
+	}
+	return speed*positionSpeedFactor + positionSpeedMargin
+}
+
+func (vdb *Vessels) checkPosition(icao int, lat, lon float64, surface bool) error {
+	// checks a decoded position is plausible given the vessel's last position, its speed & the time since
+	// plausible positions increase the vessel's position reliability, and implausible ones decrease it
+	// once reliability drops to zero the last position is forgotten, so a bad position can't lock out good ones
+
+	v := vdb.Vessels[icao]
+	now := vdb.now()
+	pos := haversine.Coord{Lat: lat, Lon: lon}
+
+	if !v.positionTime.IsZero() {
+		elapsed := now.Sub(v.positionTime)
+		_, km := haversine.Distance(haversine.Coord{Lat: v.Lat, Lon: v.Lon}, pos)
+		nm := km * 0.539957
+		limit := positionMinRange + v.plausibleSpeed(now, surface)*elapsed.Hours()
+		if nm > limit {
+			v.PositionReliability--
+			if v.PositionReliability <= 0 {
+				v.PositionReliability = 0
+				v.LatLonKnown = false
+				v.positionTime = time.Time{}
+				v.derivedSpeedKnown = false
+				v.derivedSpeedAnchorTime = time.Time{}
+			}
+			return fmt.Errorf("implausible position (distance: %.2fNM, limit: %.2fNM, elapsed: %s)", nm, limit, elapsed)
+		}
+	}
+
+	// derive speed from positions far enough apart to average out CPR & timing errors
+	if v.derivedSpeedAnchorTime.IsZero() {
+		v.derivedSpeedAnchor, v.derivedSpeedAnchorTime = pos, now
+	} else if elapsed := now.Sub(v.derivedSpeedAnchorTime); elapsed >= derivedSpeedInterval {
+		_, km := haversine.Distance(v.derivedSpeedAnchor, pos)
+		v.derivedSpeed = km * 0.539957 / elapsed.Hours()
+		v.derivedSpeedKnown = true
+		v.derivedSpeedTime = now
+		v.derivedSpeedAnchor, v.derivedSpeedAnchorTime = pos, now
+	}
+
+	v.positionTime = now
+	if v.PositionReliability < maxPositionReliability {
+		v.PositionReliability++
+	}
+	if v.PositionReliability >= positionReliableThreshold {
+		v.LatLonKnown = true
+	}
+	return nil
+}
+
+func (vdb *Vessels) rangeOf(lat, lon float64) (nm float64, ok bool) {
+	// returns the distance (NM) of lat/lon from the reference lat/lon, if known
+	if !vdb.refLatLonKnown {
+		return 0, false
+	}
+	_, km := haversine.Distance(haversine.Coord{Lat: vdb.refLat, Lon: vdb.refLon}, haversine.Coord{Lat: lat, Lon: lon})
+	return km * 0.539957, true
+}
+
+func (vdb *Vessels) checkRange(icao int, lat, lon float64, method string) error {
+	// checks a decoded position is within range of the receiver, passing positions beyond range to observers
+	// method is the decoding method, eg: "surface,global"
+
+	nm, ok := vdb.rangeOf(lat, lon)
+	if !ok {
+		return nil
+	}
+	limit := vdb.maxRange
+	if strings.HasPrefix(method, "surface") && (limit <= 0 || limit > maxSurfaceRange) {
+		limit = maxSurfaceRange
+	}
+	if limit <= 0 || nm <= limit {
+		return nil
+	}
+
+	observers := vdb.rangeRejectedObservers
+	pos := Position{
+		ICAO:       icao,
+		Time:       vdb.now(),
+		Lat:        lat,
+		Lon:        lon,
+		Method:     method,
+		RangeKnown: true,
+		Range:      nm,
+	}
+	vdb.notify(func() {
+		for _, fn := range observers {
+			fn(pos)
+		}
+	})
+	return fmt.Errorf("position beyond max range (range: %.1fNM, max: %.1fNM)", nm, limit)
+}
@@ -0,0 +1,103 @@
+package vesselstate
+
+import (
+	"sort"
+	"time"
+)
+
+// Selects vessels returned by Snapshot, the zero value selects all vessels
+type Filter struct {
+	ICAOs  []int         // only these vessels, if not empty
+	Bounds *Bounds       // only vessels with a known position within these bounds, if set
+	MaxAge time.Duration // only vessels heard from within this long, if not zero
+
+	History bool // include each vessel's recent positions
+}
+
+// Area between two latitudes & longitudes (degrees)
+// MinLon may be greater than MaxLon, for an area crossing the antimeridian
+type Bounds struct {
+	MinLat, MinLon float64
+	MaxLat, MaxLon float64
+}
+
+func (b *Bounds) Contains(lat, lon float64) bool {
+	// returns true if lat/lon is within the bounds
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon > b.MaxLon {
+		return lon >= b.MinLon || lon <= b.MaxLon
+	}
+	return lon >= b.MinLon && lon <= b.MaxLon
+}
+
+func (f *Filter) matches(v *VesselState, now time.Time) bool {
+	// returns true if the filter selects the vessel
+	if len(f.ICAOs) > 0 {
+		found := false
+		for _, icao := range f.ICAOs {
+			if icao == v.ICAO {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Bounds != nil && (!v.LatLonKnown || !f.Bounds.Contains(v.Lat, v.Lon)) {
+		return false
+	}
+	if f.MaxAge > 0 && now.Sub(v.LastUpdated) > f.MaxAge {
+		return false
+	}
+	return true
+}
+
+func (v *VesselState) snapshot(history bool) VesselState {
+	// returns a copy of the vessel's state, sharing nothing with the db
+	c := *v
+	c.Waypoints = append([]Waypoint(nil), v.Waypoints...)
+	if history {
+		c.History = v.history.positions()
+	}
+
+	// decoding state isn't part of the copy
+	c.airborneLatLonCprTypeHist = nil
+	c.surfaceLatLonCprTypeHist = nil
+	c.history = positionHistory{}
+
+	return c
+}
+
+func (vdb *Vessels) Snapshot(filter Filter) []VesselState {
+	// returns copies of the vessels selected by filter, in ICAO order
+	// copies are consistent as of a single point in time, and are not changed by later updates
+	vdb.mu.RLock()
+	defer vdb.mu.RUnlock()
+
+	now := vdb.now()
+	vessels := []VesselState{}
+	for _, v := range vdb.Vessels {
+		if filter.matches(v, now) {
+			vessels = append(vessels, v.snapshot(filter.History))
+		}
+	}
+	sort.Slice(vessels, func(i, j int) bool {
+		return vessels[i].ICAO < vessels[j].ICAO
+	})
+	return vessels
+}
+
+func (vdb *Vessels) Get(icao int) (v VesselState, ok bool) {
+	// returns a copy of a vessel's state including its recent positions, or false if it isn't tracked
+	vdb.mu.RLock()
+	defer vdb.mu.RUnlock()
+
+	tracked, ok := vdb.Vessels[icao]
+	if !ok {
+		return v, false
+	}
+	return tracked.snapshot(true), true
+}
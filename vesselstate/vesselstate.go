@@ -4,9 +4,14 @@ import (
 	"beastdecoder/bds"
 	"beastdecoder/common"
 	"beastdecoder/df"
+	"beastdecoder/meteo"
+	"beastdecoder/quality"
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,7 +20,7 @@ import (
 )
 
 type VesselState struct {
-	mu sync.RWMutex // sync mutex
+	ICAO int // ICAO aircraft address
 
 	MsgCount int // message count
 
@@ -27,6 +32,12 @@ type VesselState struct {
 	CallsignKnown bool
 	Callsign      string
 
+	// Vessel Information - Aircraft category (BDS 0,8), eg: "A3"
+	CategoryKnown bool
+	Category      string
+	EmitterType   string           // description of category, "" if reserved/no information
+	EmitterClass  bds.EmitterClass // aircraft, surface vehicle or obstacle
+
 	// Position Information - Airborne Status
 	AirborneStatusKnown bool
 	Airborne            bool
@@ -41,11 +52,28 @@ type VesselState struct {
 	LatLonKnown      bool
 	LastPositionData time.Time
 
+	// Plausible positions decoded in a row, less implausible ones (0-4), the position is known from 2
+	PositionReliability int
+	positionTime        time.Time // when the last plausible position was decoded
+
+	// Speed (kt) derived from plausible positions, used to check positions when speed isn't reported
+	derivedSpeedKnown      bool
+	derivedSpeed           float64
+	derivedSpeedTime       time.Time
+	derivedSpeedAnchor     haversine.Coord // position & time speed is next derived from
+	derivedSpeedAnchorTime time.Time
+
+	// Recent positions, oldest first (snapshots requesting history only)
+	History []Position
+	history positionHistory
+
 	// Storing Airborne Position odd/even lat/lon CPR
 	airborneLatCprOdd, airborneLonCprOdd   int // lats/lons/NL used for actual lat/lon calculation
 	airborneLatLonCprOddKnown              bool
 	airborneLatCprEven, airborneLonCprEven int // lats/lons/NL used for actual lat/lon calculation
 	airborneLatLonCprEvenKnown             bool
+	airborneLatLonCprOddTime               time.Time // when odd/even CPR positions were received
+	airborneLatLonCprEvenTime              time.Time
 	airborneLatLonCprTypeHist              []common.CprFormat
 
 	// Storing Surface Position odd/even lat/lon CPR
@@ -53,6 +81,8 @@ type VesselState struct {
 	surfaceLatLonCprOddKnown             bool
 	surfaceLatCprEven, surfaceLonCprEven int // lats/lons/NL used for actual lat/lon calculation
 	surfaceLatLonCprEvenKnown            bool
+	surfaceLatLonCprOddTime              time.Time // when odd/even CPR positions were received
+	surfaceLatLonCprEvenTime             time.Time
 	surfaceLatLonCprTypeHist             []common.CprFormat
 
 	// Ground Speed (mov)
@@ -63,17 +93,231 @@ type VesselState struct {
 	GroundTrack      string
 	GroundTrackKnown bool
 
+	// Airborne velocity (BDS 0,9), ground speed (kt) & true track (degrees)
+	VelocityKnown   bool
+	VelocitySpeed   float64
+	VelocityTrack   float64
+	VelocityUpdated time.Time
+
+	// Vertical rate (ft/min) from airborne velocity (BDS 0,9)
+	VerticalRateKnown bool
+	VerticalRate      int
+
+	// Surface movement (kt) & track (degrees) from surface position (BDS 0,6)
+	SurfaceSpeedKnown bool
+	SurfaceSpeed      float64
+	SurfaceTrackKnown bool
+	SurfaceTrack      float64
+
+	// Meteorological routine air report (BDS 4,4)
+	MeteoRoutineReport        bds.BDS44Frame
+	MeteoRoutineReportKnown   bool
+	MeteoRoutineReportUpdated time.Time
+
+	// Meteorological hazard report (BDS 4,5)
+	MeteoHazardReport        bds.BDS45Frame
+	MeteoHazardReportKnown   bool
+	MeteoHazardReportUpdated time.Time
+
+	// Air-referenced state vector (BDS 5,3)
+	AirStateVector        bds.BDS53Frame
+	AirStateVectorKnown   bool
+	AirStateVectorUpdated time.Time
+
+	// Next waypoints (BDS 5,4, 5,5, 5,6), ordered by ETA
+	Waypoints []Waypoint
+
+	// Data link capability report (BDS 1,0)
+	DataLinkCapability        bds.BDS10Frame
+	DataLinkCapabilityKnown   bool
+	DataLinkCapabilityUpdated time.Time
+
+	// Common usage GICB capability report (BDS 1,7)
+	GICBCapability        bds.BDS17Frame
+	GICBCapabilityKnown   bool
+	GICBCapabilityUpdated time.Time
+
+	// Aircraft operational status (BDS 6,5)
+	OperationalStatus        bds.BDS07Frame
+	OperationalStatusKnown   bool
+	OperationalStatusUpdated time.Time
+
+	// ADS-B version, needed to interpret NIC/NACp
+	ADSBVersionKnown bool
+	ADSBVersion      int
+
+	// Navigational accuracy category - position
+	NACpKnown bool
+	NACp      int
+
+	// Source integrity level & supplement
+	SILKnown      bool
+	SIL           int
+	SILSupplement int
+
+	// NIC supplements
+	NICSupplementAKnown bool
+	NICSupplementA      int
+	NICSupplementCKnown bool
+	NICSupplementC      int
+
+	// Barometric altitude integrity
+	NICbaroKnown bool
+	NICbaro      int
+
+	// Geometric vertical accuracy
+	GVAKnown bool
+	GVA      int
+
+	// Navigational accuracy category - velocity
+	NACvKnown bool
+	NACv      int
+
+	// Emergency/priority status (BDS 6,1)
+	EmergencyStatusKnown bool
+	EmergencyStatus      bds.EmergencyPriorityStatus
+
+	// Set when the vessel reports an emergency, either in emergency/priority status or by squawking 7500/7600/7700
+	Emergency bool
+
+	// Most recent TCAS resolution advisory broadcast (BDS 6,1 subtype 2)
+	TcasRAKnown   bool
+	TcasRA        bds.BDS61FrameTcasRA
+	TcasRAUpdated time.Time
+
+	// Target state and status (BDS 6,2)
+	TargetState        bds.BDS62Frame
+	TargetStateKnown   bool
+	TargetStateUpdated time.Time
+
+	// Selected altitude (ft) & source ("MCP/FCU", "FMS", "holding", "FMS/RNAV")
+	SelectedAltitudeKnown  bool
+	SelectedAltitude       int
+	SelectedAltitudeSource string
+
+	// Barometric pressure setting, QNH (hPa)
+	BaroSettingKnown bool
+	BaroSetting      float64
+
+	// Selected heading (degrees)
+	SelectedHeadingKnown bool
+	SelectedHeading      float64
+
+	// Autopilot modes (version 2 target state and status only)
+	AutopilotModesKnown bool
+	Autopilot           bool
+	VNAV                bool
+	AltitudeHold        bool
+	Approach            bool
+	LNAV                bool
+
+	// TCAS/ACAS operational
+	TcasOperationalKnown bool
+	TcasOperational      bool
+
+	// Quality of most recent position message (NIC/Rc, NACp/NACv, SIL)
+	PositionQualityKnown bool
+	PositionQuality      quality.Indicators
+
 	// Last message received from vessel
 	LastUpdated time.Time
 }
 
+// Next waypoint from BDS 5,4, 5,5 or 5,6
+type Waypoint struct {
+	Name        string
+	ETA         time.Time     // estimated time of arrival (normal flight)
+	FlightLevel int           // estimated flight level (normal flight)
+	TimeToGo    time.Duration // time to go (direct route)
+	Updated     time.Time
+}
+
+// maximum waypoints stored per vessel (BDS 5,4, 5,5 & 5,6)
+const maxWaypoints = 3
+
+// vessels are evicted after no messages for this long
+const vesselExpiry = time.Second * 60
+
+// position data is cleared after no position messages for this long
+const positionExpiry = time.Second * 2
+
+// odd & even CPR positions must be received within this long of each other for global decoding
+const airborneCprPairingWindow = time.Second * 10
+const surfaceCprPairingWindow = time.Second * 25
+
+// Comm-B replies inferred with a lower confidence are discarded
+const minInferenceConfidence = 0.5
+
+// airborne velocity older than this isn't used to infer Comm-B registers
+const inferenceVelocityMaxAge = time.Second * 30
+
 type Vessels struct {
-	mu      sync.RWMutex         // sync mutex
+	mu      sync.RWMutex         // guards the db & all vessel state, held for the whole of each update
 	Vessels map[int]*VesselState // map of vessels, key is ICAO
 
 	// reference lat/lon for location calculations
 	refLatLonKnown bool
 	refLat, refLon float64
+
+	// derives wind/temperature from BDS 5,0 + BDS 6,0 replies, observations (including BDS 4,4) are passed to meteoObservers
+	meteo          meteo.Deriver
+	meteoObservers []func(meteo.Observation)
+
+	// position messages with a NIC below this are discarded
+	minNIC int
+
+	// positions further than this (NM) from the reference lat/lon are discarded, 0 accepts all positions
+	maxRange    float64
+	maxRangeSet bool
+
+	// called when a position is discarded as beyond maxRange
+	rangeRejectedObservers []func(Position)
+
+	// infers Comm-B registers using each vessel's known state
+	inference bds.InferenceEngine
+
+	// called when a vessel's position is calculated
+	positionObservers []func(Position)
+
+	// time source, wall clock unless replaying
+	clock Clock
+
+	// observer notifications queued during an update, called once the db is unlocked
+	notifications []func()
+
+	// receive events as vessels change
+	subscriptions []*Subscription
+
+	// positions are kept in each vessel's history for this long
+	historyDuration    time.Duration
+	historyDurationSet bool
+}
+
+// A vessel's state when its position was calculated, passed to position observers
+type Position struct {
+	ICAO     int
+	Callsign string
+	Time     time.Time
+	Lat, Lon float64
+	Method   string // "airborne,global", "surface,local" etc
+	Airborne bool
+
+	AltitudeKnown bool
+	Altitude      int
+
+	// ground speed (kt) & true track (degrees), from airborne velocity or surface movement
+	GroundSpeedKnown bool
+	GroundSpeed      float64
+	TrackKnown       bool
+	Track            float64
+
+	// vertical rate (ft/min)
+	VerticalRateKnown bool
+	VerticalRate      int
+
+	// distance (NM) from the reference lat/lon
+	RangeKnown bool
+	Range      float64
 }
 
 func (vdb *Vessels) RLock() {
@@ -87,7 +331,153 @@ func (vdb *Vessels) RUnlock() {
 func (vdb *Vessels) Init() {
 	// run once on program start to init the vessel db
 	vdb.Vessels = make(map[int]*VesselState)
-	go vdb.evictor()
+	vdb.meteo.Init(meteo.DefaultPairingWindow, 0)
+	vdb.inference.Init()
+
+	if vdb.clock == nil {
+		vdb.clock = realClock{}
+	}
+	// defaults, unless already set
+	if !vdb.historyDurationSet {
+		vdb.historyDuration = DefaultHistoryDuration
+	}
+	if !vdb.maxRangeSet {
+		vdb.maxRange = DefaultMaxRange
+	}
+}
+
+func (vdb *Vessels) SetMagneticDeclination(declination float64) {
+	// sets the magnetic declination (degrees, east positive) used to convert magnetic heading to true heading
+	vdb.meteo.SetDeclination(declination)
+}
+
+func (vdb *Vessels) OnMeteoObservation(fn func(meteo.Observation)) {
+	// registers a function to be called for every meteorological observation
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.meteoObservers = append(vdb.meteoObservers, fn)
+}
+
+func (vdb *Vessels) OnPosition(fn func(Position)) {
+	// registers a function to be called every time a vessel's position is calculated
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.positionObservers = append(vdb.positionObservers, fn)
+}
+
+func (vdb *Vessels) emitPosition(icao int) {
+	// passes the vessel's position & movement to observers, if its position is known
+
+	// ensure vessel exists before attempting to read
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+
+	observers := vdb.positionObservers
+	v := vdb.Vessels[icao]
+	known := v.LatLonKnown
+	pos := Position{
+		ICAO:              icao,
+		Time:              v.LastPositionData,
+		Lat:               v.Lat,
+		Lon:               v.Lon,
+		Method:            v.LatLonMethod,
+		Airborne:          v.Airborne,
+		AltitudeKnown:     v.AltitudeKnown,
+		Altitude:          v.Altitude,
+		VerticalRateKnown: v.VerticalRateKnown,
+		VerticalRate:      v.VerticalRate,
+	}
+	if v.CallsignKnown {
+		pos.Callsign = v.Callsign
+	}
+	pos.Range, pos.RangeKnown = vdb.rangeOf(v.Lat, v.Lon)
+	if strings.HasPrefix(v.LatLonMethod, "surface") {
+		pos.GroundSpeedKnown, pos.GroundSpeed = v.SurfaceSpeedKnown, v.SurfaceSpeed
+		pos.TrackKnown, pos.Track = v.SurfaceTrackKnown, v.SurfaceTrack
+	} else {
+		pos.GroundSpeedKnown, pos.GroundSpeed = v.VelocityKnown, v.VelocitySpeed
+		pos.TrackKnown, pos.Track = v.VelocityKnown, v.VelocityTrack
+	}
+
+	if !known {
+		return
+	}
+
+	vdb.addHistory(icao, pos)
+	vdb.emitEvent(EventPosition, icao)
+	vdb.notify(func() {
+		for _, fn := range observers {
+			fn(pos)
+		}
+	})
+}
+
+func (vdb *Vessels) emitMeteoObservation(icao int, obs meteo.Observation) {
+	// tags an observation with the vessel's position & altitude, and passes it to observers
+
+	// ensure vessel exists before attempting to read
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+
+	observers := vdb.meteoObservers
+	obs.PositionKnown = vdb.Vessels[icao].LatLonKnown
+	obs.Lat = vdb.Vessels[icao].Lat
+	obs.Lon = vdb.Vessels[icao].Lon
+	obs.AltitudeKnown = vdb.Vessels[icao].AltitudeKnown
+	obs.Altitude = vdb.Vessels[icao].Altitude
+
+	if log.Debug().Enabled() {
+		log.Debug().Str("icao", fmt.Sprintf("%06x", icao)).Stringer("source", obs.Source).Bool("WindValid", obs.WindValid).Float64("WindSpeed", obs.WindSpeed).Float64("WindDirection", obs.WindDirection).Bool("TemperatureValid", obs.TemperatureValid).Float64("Temperature", obs.Temperature).Msg("meteo observation")
+	}
+
+	vdb.notify(func() {
+		for _, fn := range observers {
+			fn(obs)
+		}
+	})
+}
+
+func (vdb *Vessels) notify(fn func()) {
+	// queues an observer notification, called once the update holding the lock is complete
+	vdb.notifications = append(vdb.notifications, fn)
+}
+
+func (vdb *Vessels) update(fn func()) {
+	// applies an update with the db locked, then notifies observers
+	// observers are called without the lock held, so may read the db
+	vdb.mu.Lock()
+	fn()
+	notifications := vdb.notifications
+	vdb.notifications = nil
+	vdb.mu.Unlock()
+
+	for _, notification := range notifications {
+		notification()
+	}
+}
+
+func (vdb *Vessels) SetMinimumNIC(nic int) {
+	// sets the minimum NIC for position messages to be used, 0 accepts all positions
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.minNIC = nic
+}
+
+func (vdb *Vessels) SetMaxRange(nm float64) {
+	// sets the furthest (NM) a position can be from the reference lat/lon, 0 accepts all positions
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.maxRange = nm
+	vdb.maxRangeSet = true
+}
+
+func (vdb *Vessels) OnRangeRejected(fn func(Position)) {
+	// registers a function to be called every time a position is discarded as beyond max range
+	vdb.mu.Lock()
+	defer vdb.mu.Unlock()
+	vdb.rangeRejectedObservers = append(vdb.rangeRejectedObservers, fn)
 }
 
 func (vdb *Vessels) SetRefLatLon(refLat, refLon float64) {
@@ -98,57 +488,74 @@ func (vdb *Vessels) SetRefLatLon(refLat, refLon float64) {
 	vdb.refLatLonKnown = true
 }
 
-func (vdb *Vessels) evictor() {
-	// evicts stale (no updates in >60sec) entries from vdb
+func (vdb *Vessels) RunEvictor(ctx context.Context) {
+	// evicts stale entries from vdb every second, until ctx is cancelled
+	ticker := time.NewTicker(time.Second * 1)
+	defer ticker.Stop()
 	for {
-		time.Sleep(time.Second * 1)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			vdb.Evict()
+		}
+	}
+}
+
+func (vdb *Vessels) Evict() {
+	// evicts stale (no updates in >60sec) entries from vdb, and clears stale position data
+	// called every second by RunEvictor, or as the clock advances when replaying
 
-		icaosToEvict := []int{}
+	vdb.update(vdb.evict)
+}
 
-		// find expired icaos (no updates in 60 sec)
-		vdb.mu.RLock()
-		for icao := range vdb.Vessels {
+func (vdb *Vessels) evict() {
+	// evicts stale entries, with the db locked
+	now := vdb.now()
+	icaosToEvict := []int{}
 
-			clearPositionData := false
+	// find expired icaos (no updates in 60 sec)
+	for icao := range vdb.Vessels {
 
-			vdb.Vessels[icao].mu.RLock()
-			// determine of record should be evicted
-			if time.Now().Sub(vdb.Vessels[icao].LastUpdated) > (time.Second * 60) {
-				icaosToEvict = append(icaosToEvict, icao)
-			} else {
-				// In the event that the navigation input ceases, the extrapolation described in
-				// §A.2.3.2.3.1 and §A.2.3.2.3.2 shall be limited to no more than 2 seconds.
-				//
-				// At the end of this time-out of 2 seconds,
-				// all fields of the airborne position register,
-				// except the altitude field, shall be cleared (set to zero).
-				//
-				// When the appropriate register fields are cleared,
-				// the zero TYPE Code field shall serve to notify ADS-B receiving equipment
-				// that the data in the latitude and longitude fields are invalid.
-				if time.Now().Sub(vdb.Vessels[icao].LastPositionData) > (time.Second * 2) {
-					clearPositionData = true
-				}
-			}
-			vdb.Vessels[icao].mu.RUnlock()
+		clearPositionData := false
 
-			// clear position data if needed
-			if clearPositionData {
-				vdb.clearPositionData(icao)
+		// determine of record should be evicted
+		if now.Sub(vdb.Vessels[icao].LastUpdated) > vesselExpiry {
+			icaosToEvict = append(icaosToEvict, icao)
+		} else {
+			// In the event that the navigation input ceases, the extrapolation described in
+			// §A.2.3.2.3.1 and §A.2.3.2.3.2 shall be limited to no more than 2 seconds.
+			//
+			// At the end of this time-out of 2 seconds,
+			// all fields of the airborne position register,
+			// except the altitude field, shall be cleared (set to zero).
+			//
+			// When the appropriate register fields are cleared,
+			// the zero TYPE Code field shall serve to notify ADS-B receiving equipment
+			// that the data in the latitude and longitude fields are invalid.
+			if now.Sub(vdb.Vessels[icao].LastPositionData) > positionExpiry {
+				clearPositionData = true
 			}
 		}
-		vdb.mu.RUnlock()
 
-		// delete them
-		vdb.mu.Lock()
-		for _, icao := range icaosToEvict {
-			if log.Debug().Enabled() {
-				log.Info().Str("icao", fmt.Sprintf("%06x", icao)).Msg("removing expired")
-			}
-			delete(vdb.Vessels, icao)
+		// clear position data if needed
+		if clearPositionData {
+			vdb.clearPositionData(icao)
 		}
-		vdb.mu.Unlock()
 
+		// drop old positions from history
+		vdb.Vessels[icao].history.prune(now.Add(-vdb.historyDuration))
+	}
+
+	// delete them
+	for _, icao := range icaosToEvict {
+		if log.Debug().Enabled() {
+			log.Info().Str("icao", fmt.Sprintf("%06x", icao)).Msg("removing expired")
+		}
+		vdb.emitEvent(EventRemoved, icao)
+		delete(vdb.Vessels, icao)
+		vdb.meteo.Forget(icao)
+		vdb.inference.Forget(icao)
 	}
 }
 
@@ -162,16 +569,12 @@ func (vdb *Vessels) incrementMessageCount(icao int) {
 	}
 
 	// increment counter
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 	vdb.Vessels[icao].MsgCount++
 }
 
 func (vdb *Vessels) isVesselTracked(icao int) bool {
 	// check if vessel is in db
-	vdb.mu.RLock()
 	_, ok := vdb.Vessels[icao]
-	defer vdb.mu.RUnlock()
 	if !ok {
 		return false
 	}
@@ -186,15 +589,15 @@ func (vdb *Vessels) addVessel(icao int) {
 
 	// if vessel is not in db, add it
 	if !vdb.isVesselTracked(icao) {
-		vdb.mu.Lock()
 		if log.Debug().Enabled() {
 			log.Debug().Str("icao", fmt.Sprintf("%06x", icao)).Msg("addVessel")
 		}
 		vdb.Vessels[icao] = &VesselState{
-			LastUpdated: time.Now(),
+			ICAO:        icao,
+			LastUpdated: vdb.now(),
 			// StoredFrames: make(map[cprFormat]map[BDScode]interface{}),
 		}
-		vdb.mu.Unlock()
+		vdb.emitEvent(EventAdded, icao)
 	} else {
 		vdb.updateLastSeen(icao)
 	}
@@ -206,12 +609,8 @@ func (vdb *Vessels) updateLastSeen(icao int) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 	// set time
-	vdb.Vessels[icao].LastUpdated = time.Now()
+	vdb.Vessels[icao].LastUpdated = vdb.now()
 	if log.Debug().Enabled() {
 		log.Debug().Time("LastUpdated", vdb.Vessels[icao].LastUpdated).Str("icao", fmt.Sprintf("%06x", icao)).Msg("updateLastSeen")
 	}
@@ -223,13 +622,49 @@ func (vdb *Vessels) setCallsign(icao int, callsign string) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
+	changed := !vdb.Vessels[icao].CallsignKnown || vdb.Vessels[icao].Callsign != callsign
 	// set ground speed
 	vdb.Vessels[icao].Callsign = callsign
 	vdb.Vessels[icao].CallsignKnown = true
+	if changed {
+		vdb.emitEvent(EventCallsign, icao)
+	}
+}
+
+func (vdb *Vessels) setCategory(icao int, frame bds.BDS08Frame) {
+	// set aircraft category & emitter type
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set category
+	vdb.Vessels[icao].Category = frame.Category
+	vdb.Vessels[icao].EmitterType = frame.EmitterType()
+	vdb.Vessels[icao].EmitterClass = frame.EmitterClass()
+	vdb.Vessels[icao].CategoryKnown = true
+}
+
+func (vdb *Vessels) setSurfaceMovement(icao int, frame bds.BDS06Frame) {
+	// set surface movement & track
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set speed
+	if frame.GroundSpeedValid {
+		vdb.Vessels[icao].SurfaceSpeed = frame.GroundSpeedKnots
+		vdb.Vessels[icao].SurfaceSpeedKnown = true
+	}
+	// set track
+	if frame.S == bds.GroundTrackStatusValid {
+		vdb.Vessels[icao].SurfaceTrack = frame.GroundTrackDegrees
+		vdb.Vessels[icao].SurfaceTrackKnown = true
+	}
+}
+
+func (v *VesselState) IsGroundVehicle() bool {
+	// returns true if the vessel has identified itself as a surface vehicle or ground obstruction
+	return v.CategoryKnown && (v.EmitterClass == bds.EmitterClassSurfaceVehicle || v.EmitterClass == bds.EmitterClassObstacle)
 }
 
 func (vdb *Vessels) setGroundSpeed(icao int, groundSpeed string) {
@@ -238,10 +673,6 @@ func (vdb *Vessels) setGroundSpeed(icao int, groundSpeed string) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 	// set ground speed
 	vdb.Vessels[icao].GroundSpeed = groundSpeed
 	vdb.Vessels[icao].GroundSpeedKnown = true
@@ -253,30 +684,411 @@ func (vdb *Vessels) setGroundTrack(icao int, groundTrack string) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 	// set ground speed
 	vdb.Vessels[icao].GroundTrack = groundTrack
 	vdb.Vessels[icao].GroundTrackKnown = true
 }
 
+func (vdb *Vessels) setMeteoRoutineReport(icao int, frame bds.BDS44Frame) {
+	// set meteorological routine air report
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set report
+	vdb.Vessels[icao].MeteoRoutineReport = frame
+	vdb.Vessels[icao].MeteoRoutineReportKnown = true
+	vdb.Vessels[icao].MeteoRoutineReportUpdated = vdb.now()
+}
+
+func (vdb *Vessels) setMeteoHazardReport(icao int, frame bds.BDS45Frame) {
+	// set meteorological hazard report
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set report
+	vdb.Vessels[icao].MeteoHazardReport = frame
+	vdb.Vessels[icao].MeteoHazardReportKnown = true
+	vdb.Vessels[icao].MeteoHazardReportUpdated = vdb.now()
+}
+
+func (vdb *Vessels) setAirStateVector(icao int, frame bds.BDS53Frame) {
+	// set air-referenced state vector
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set report
+	vdb.Vessels[icao].AirStateVector = frame
+	vdb.Vessels[icao].AirStateVectorKnown = true
+	vdb.Vessels[icao].AirStateVectorUpdated = vdb.now()
+}
+
+func (vdb *Vessels) setWaypoint(icao int, frame bds.BDS54Frame) {
+	// adds or updates a next waypoint, dropping waypoints that have been passed
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	v := vdb.Vessels[icao]
+
+	now := vdb.now()
+	wp := Waypoint{
+		Name:        frame.Waypoint,
+		ETA:         now.Add(time.Duration(frame.ETA * float64(time.Minute))),
+		FlightLevel: frame.EFL,
+		TimeToGo:    time.Duration(frame.TTG * float64(time.Minute)),
+		Updated:     now,
+	}
+
+	// keep waypoints not yet reached, replacing any with the same name
+	waypoints := []Waypoint{wp}
+	for _, existing := range v.Waypoints {
+		if existing.Name != wp.Name && existing.ETA.After(now) {
+			waypoints = append(waypoints, existing)
+		}
+	}
+
+	// order by ETA
+	sort.Slice(waypoints, func(i, j int) bool {
+		return waypoints[i].ETA.Before(waypoints[j].ETA)
+	})
+	if len(waypoints) > maxWaypoints {
+		waypoints = waypoints[:maxWaypoints]
+	}
+	v.Waypoints = waypoints
+}
+
+func (vdb *Vessels) setDataLinkCapability(icao int, frame bds.BDS10Frame) {
+	// set data link capability report
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set report
+	vdb.Vessels[icao].DataLinkCapability = frame
+	vdb.Vessels[icao].DataLinkCapabilityKnown = true
+	vdb.Vessels[icao].DataLinkCapabilityUpdated = vdb.now()
+}
+
+func (vdb *Vessels) setGICBCapability(icao int, frame bds.BDS17Frame) {
+	// set common usage GICB capability report
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set report
+	vdb.Vessels[icao].GICBCapability = frame
+	vdb.Vessels[icao].GICBCapabilityKnown = true
+	vdb.Vessels[icao].GICBCapabilityUpdated = vdb.now()
+}
+
+func (vdb *Vessels) setOperationalStatus(icao int, frame bds.BDS07Frame) {
+	// set aircraft operational status, ADS-B version and quality indicators
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	v := vdb.Vessels[icao]
+	// set report
+	v.OperationalStatus = frame
+	v.OperationalStatusKnown = true
+	v.OperationalStatusUpdated = vdb.now()
+	// set version
+	v.ADSBVersion = frame.Ver
+	v.ADSBVersionKnown = true
+	// set quality indicators, only overwriting those present in this version/subtype
+	if nacp, ok := frame.NACp(); ok {
+		v.NACp = nacp
+		v.NACpKnown = true
+	}
+	if sil, ok := frame.SIL(); ok {
+		v.SIL = sil
+		v.SILKnown = true
+		v.SILSupplement, _ = frame.SILSupplement()
+	}
+	if nica, ok := frame.NICSupplementA(); ok {
+		v.NICSupplementA = nica
+		v.NICSupplementAKnown = true
+	}
+	if nicc, ok := frame.NICSupplementC(); ok {
+		v.NICSupplementC = nicc
+		v.NICSupplementCKnown = true
+	}
+	if nicbaro, ok := frame.NICbaro(); ok {
+		v.NICbaro = nicbaro
+		v.NICbaroKnown = true
+	}
+	if gva, ok := frame.GVA(); ok {
+		v.GVA = gva
+		v.GVAKnown = true
+	}
+}
+
+func (vdb *Vessels) setTargetState(icao int, frame bds.BDS62Frame) {
+	// sets target state and status, selected altitude/heading, autopilot modes and quality indicators
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	v := vdb.Vessels[icao]
+	// set report
+	v.TargetState = frame
+	v.TargetStateKnown = true
+	v.TargetStateUpdated = vdb.now()
+
+	switch frame.St {
+
+	// version 1
+	case 0:
+		// target altitude
+		v.SelectedAltitudeKnown = frame.VerticalTargetState != bds.VTSUnavailable
+		if v.SelectedAltitudeKnown {
+			v.SelectedAltitude = frame.TargetAltitude
+			switch frame.VerticalTargetState {
+			case bds.VTSAutopilotSelectedValue:
+				v.SelectedAltitudeSource = "MCP/FCU"
+			case bds.VTSHoldingAltitude:
+				v.SelectedAltitudeSource = "holding"
+			case bds.VTSFmsRnavSystem:
+				v.SelectedAltitudeSource = "FMS/RNAV"
+			}
+		}
+		// target heading
+		v.SelectedHeadingKnown = frame.HorizontalTargetState != bds.HTSUnavailable
+		if v.SelectedHeadingKnown {
+			v.SelectedHeading = float64(frame.TargetHeading)
+		}
+		// tcas
+		v.TcasOperationalKnown = true
+		v.TcasOperational = frame.CapabilityModeCodes == bds.TcasAcasOperationalOrUnknown
+
+	// version 2
+	case 1:
+		// selected altitude
+		v.SelectedAltitudeKnown = frame.SelectedAltitudeValid
+		if v.SelectedAltitudeKnown {
+			v.SelectedAltitude = frame.SelectedAltitude
+			switch frame.SelectedAltitudeType {
+			case bds.SelectedAltitudeMcpFcu:
+				v.SelectedAltitudeSource = "MCP/FCU"
+			case bds.SelectedAltitudeFms:
+				v.SelectedAltitudeSource = "FMS"
+			}
+		}
+		// qnh
+		v.BaroSettingKnown = frame.BaroSettingValid
+		if v.BaroSettingKnown {
+			v.BaroSetting = frame.BaroSetting
+		}
+		// selected heading
+		v.SelectedHeadingKnown = frame.SelectedHeadingValid
+		if v.SelectedHeadingKnown {
+			v.SelectedHeading = frame.SelectedHeading
+		}
+		// autopilot modes
+		v.AutopilotModesKnown = frame.ModeValid
+		if v.AutopilotModesKnown {
+			v.Autopilot = frame.Autopilot
+			v.VNAV = frame.VNAV
+			v.AltitudeHold = frame.AltitudeHold
+			v.Approach = frame.Approach
+			v.LNAV = frame.LNAV
+		}
+		// tcas
+		v.TcasOperationalKnown = true
+		v.TcasOperational = frame.TcasOperational
+		// sil supplement
+		v.SILSupplement = frame.SILSupplement
+	}
+
+	// set quality indicators, present in both versions
+	v.NACp = int(frame.NACp)
+	v.NACpKnown = true
+	v.NICbaro = int(frame.NICbaro)
+	v.NICbaroKnown = true
+	v.SIL = frame.SIL
+	v.SILKnown = true
+
+	if log.Debug().Enabled() {
+		log.Debug().Int("St", frame.St).Bool("SelectedAltitudeKnown", v.SelectedAltitudeKnown).Int("SelectedAltitude", v.SelectedAltitude).Str("icao", fmt.Sprintf("%06x", icao)).Msg("setTargetState")
+	}
+}
+
+func (vdb *Vessels) setVelocity(icao int, frame bds.BDS09Frame) {
+	// set ground speed, track & vertical rate from airborne velocity
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	v := vdb.Vessels[icao]
+	// set velocity
+	if gs, trk, ok := frame.GroundVelocity(); ok {
+		v.VelocityKnown = true
+		v.VelocitySpeed = gs
+		v.VelocityTrack = trk
+		v.VelocityUpdated = vdb.now()
+		v.GroundSpeed = fmt.Sprintf("%.4f km/h (%.4f kts)", gs*1.852, gs)
+		v.GroundSpeedKnown = true
+		v.GroundTrack = fmt.Sprintf("%d°", int(math.Round(trk)))
+		v.GroundTrackKnown = true
+	}
+	// set vertical rate
+	if vr, ok := frame.VerticalRate(); ok {
+		v.VerticalRateKnown = true
+		v.VerticalRate = vr
+	}
+}
+
+func (vdb *Vessels) inferenceContext(icao int) (ctx bds.AircraftContext) {
+	// returns the vessel's known state, used to infer Comm-B registers
+	ctx.Time = vdb.now()
+
+	// ensure vessel exists before attempting to read
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	v := vdb.Vessels[icao]
+
+	// velocity, if recent
+	if v.VelocityKnown && ctx.Time.Sub(v.VelocityUpdated) < inferenceVelocityMaxAge {
+		ctx.GroundSpeedKnown = true
+		ctx.GroundSpeed = v.VelocitySpeed
+		ctx.TrackKnown = true
+		ctx.Track = v.VelocityTrack
+		ctx.VerticalRateKnown = v.VerticalRateKnown
+		ctx.VerticalRate = v.VerticalRate
+	}
+
+	// altitude
+	ctx.AltitudeKnown = v.AltitudeKnown
+	ctx.Altitude = v.Altitude
+
+	// gicb capability
+	ctx.GICBKnown = v.GICBCapabilityKnown
+	ctx.GICB = v.GICBCapability
+
+	return ctx
+}
+
+func (vdb *Vessels) setNACv(icao int, nacv int) {
+	// set navigational accuracy category - velocity
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	// set nacv
+	vdb.Vessels[icao].NACv = nacv
+	vdb.Vessels[icao].NACvKnown = true
+}
+
+func (vdb *Vessels) updatePositionQuality(icao int, tc int, nicb int) (acceptable bool) {
+	// computes and stores the quality of a position message
+	// returns false if the position should be discarded because its NIC is below the minimum
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return false
+	}
+	v := vdb.Vessels[icao]
+
+	// version 0 is assumed until operational status is received
+	q := quality.Compute(quality.Inputs{
+		Tc:             tc,
+		Version:        v.ADSBVersion,
+		NICSupplementA: v.NICSupplementA,
+		NICSupplementB: nicb,
+		NICSupplementC: v.NICSupplementC,
+		NACpKnown:      v.NACpKnown,
+		NACp:           v.NACp,
+		NACvKnown:      v.NACvKnown,
+		NACv:           v.NACv,
+		SILKnown:       v.SILKnown,
+		SIL:            v.SIL,
+		SILSupplement:  v.SILSupplement,
+	})
+
+	// set quality
+	v.PositionQuality = q
+	v.PositionQualityKnown = true
+
+	return q.MeetsMinimumNIC(vdb.minNIC)
+}
+
 func (vdb *Vessels) setSquawkCode(icao int, squawk int) {
 	// sets airborne status
 	// ensure vessel exists before attempting to update
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
+	changed := !vdb.Vessels[icao].SquawkCodeKnown || vdb.Vessels[icao].SquawkCode != squawk
 	vdb.Vessels[icao].SquawkCodeKnown = true
 	vdb.Vessels[icao].SquawkCode = squawk
 	if log.Debug().Enabled() {
 		log.Debug().Bool("SquawkCodeKnown", true).Int("SquawkCode", squawk).Str("icao", fmt.Sprintf("%06x", icao)).Msg("setSquawkCode")
 	}
+	if changed {
+		vdb.emitEvent(EventSquawk, icao)
+	}
+}
+
+func (vdb *Vessels) setEmergencyStatus(icao int, status bds.EmergencyPriorityStatus) {
+	// sets emergency/priority status
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	vdb.Vessels[icao].EmergencyStatusKnown = true
+	vdb.Vessels[icao].EmergencyStatus = status
+	if log.Debug().Enabled() {
+		log.Debug().Stringer("EmergencyStatus", status).Str("icao", fmt.Sprintf("%06x", icao)).Msg("setEmergencyStatus")
+	}
+}
+
+func (vdb *Vessels) setTcasRA(icao int, ra bds.BDS61FrameTcasRA) {
+	// sets most recent TCAS resolution advisory
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+	vdb.Vessels[icao].TcasRAKnown = true
+	vdb.Vessels[icao].TcasRA = ra
+	vdb.Vessels[icao].TcasRAUpdated = vdb.now()
+}
+
+func isEmergencySquawk(squawk int) bool {
+	// returns true for squawk codes that indicate an emergency
+	//  7500 = unlawful interference
+	//  7600 = radio failure
+	//  7700 = general emergency
+	return squawk == 7500 || squawk == 7600 || squawk == 7700
+}
+
+func (vdb *Vessels) updateEmergency(icao int) {
+	// updates the emergency flag from emergency/priority status & squawk code, and emits an event if it changed
+	// ensure vessel exists before attempting to update
+	if !vdb.isVesselTracked(icao) {
+		return
+	}
+
+	v := vdb.Vessels[icao]
+	emergency := (v.EmergencyStatusKnown && v.EmergencyStatus != bds.EmergencyNone) || (v.SquawkCodeKnown && isEmergencySquawk(v.SquawkCode))
+	changed := emergency != v.Emergency
+	v.Emergency = emergency
+	status := v.EmergencyStatus
+	squawk := v.SquawkCode
+
+	if !changed {
+		return
+	}
+
+	if emergency {
+		log.Warn().Str("icao", fmt.Sprintf("%06x", icao)).Stringer("status", status).Int("squawk", squawk).Msg("emergency declared")
+	} else {
+		log.Info().Str("icao", fmt.Sprintf("%06x", icao)).Msg("emergency cleared")
+	}
+
+	vdb.emitEvent(EventEmergency, icao)
 }
 
 func (vdb *Vessels) setAirborneStatus(icao int, airborne bool) {
@@ -285,15 +1097,16 @@ func (vdb *Vessels) setAirborneStatus(icao int, airborne bool) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
+	// landing or take off, only once airborne status is known
+	transition := vdb.Vessels[icao].AirborneStatusKnown && vdb.Vessels[icao].Airborne != airborne
 	vdb.Vessels[icao].AirborneStatusKnown = true
 	vdb.Vessels[icao].Airborne = airborne
 	if log.Debug().Enabled() {
 		log.Debug().Bool("AirborneStatusKnown", true).Bool("Airborne", airborne).Str("icao", fmt.Sprintf("%06x", icao)).Msg("setAirborneStatus")
 	}
+	if transition {
+		vdb.emitEvent(EventGround, icao)
+	}
 }
 
 func (vdb *Vessels) setAltitude(icao int, altitude int) {
@@ -302,10 +1115,6 @@ func (vdb *Vessels) setAltitude(icao int, altitude int) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 
 	// sanity check
 	if vdb.Vessels[icao].AltitudeKnown {
@@ -327,12 +1136,9 @@ func (vdb *Vessels) clearPositionData(icao int) {
 	if !vdb.isVesselTracked(icao) {
 		return
 	}
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
 
 	vdb.Vessels[icao].LatLonKnown = false
+	vdb.Vessels[icao].PositionReliability = 0
 
 	vdb.Vessels[icao].airborneLatLonCprOddKnown = false
 	vdb.Vessels[icao].airborneLatLonCprEvenKnown = false
@@ -353,23 +1159,21 @@ func (vdb *Vessels) storeAirborneLatLonCPR(icao int, latCpr, lonCpr int, f commo
 		return
 	}
 
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
-
 	// store the data
+	now := vdb.now()
 	switch f {
 	case common.CprFormatEvenFrame:
 		vdb.Vessels[icao].airborneLatCprEven = latCpr
 		vdb.Vessels[icao].airborneLonCprEven = lonCpr
 		vdb.Vessels[icao].airborneLatLonCprEvenKnown = true
+		vdb.Vessels[icao].airborneLatLonCprEvenTime = now
 	case common.CprFormatOddFrame:
 		vdb.Vessels[icao].airborneLatCprOdd = latCpr
 		vdb.Vessels[icao].airborneLonCprOdd = lonCpr
 		vdb.Vessels[icao].airborneLatLonCprOddKnown = true
+		vdb.Vessels[icao].airborneLatLonCprOddTime = now
 	}
-	vdb.Vessels[icao].LastPositionData = time.Now()
+	vdb.Vessels[icao].LastPositionData = now
 	vdb.Vessels[icao].airborneLatLonCprTypeHist = append(vdb.Vessels[icao].airborneLatLonCprTypeHist, f)
 
 	// trim airborneLatLonCprTypeHist
@@ -387,23 +1191,21 @@ func (vdb *Vessels) storeSurfaceLatLonCPR(icao int, latCpr, lonCpr int, f common
 		return
 	}
 
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
-
 	// store the data
+	now := vdb.now()
 	switch f {
 	case common.CprFormatEvenFrame:
 		vdb.Vessels[icao].surfaceLatCprEven = latCpr
 		vdb.Vessels[icao].surfaceLonCprEven = lonCpr
 		vdb.Vessels[icao].surfaceLatLonCprEvenKnown = true
+		vdb.Vessels[icao].surfaceLatLonCprEvenTime = now
 	case common.CprFormatOddFrame:
 		vdb.Vessels[icao].surfaceLatCprOdd = latCpr
 		vdb.Vessels[icao].surfaceLonCprOdd = lonCpr
 		vdb.Vessels[icao].surfaceLatLonCprOddKnown = true
+		vdb.Vessels[icao].surfaceLatLonCprOddTime = now
 	}
-	vdb.Vessels[icao].LastPositionData = time.Now()
+	vdb.Vessels[icao].LastPositionData = now
 	vdb.Vessels[icao].surfaceLatLonCprTypeHist = append(vdb.Vessels[icao].surfaceLatLonCprTypeHist, f)
 
 	// trim airborneLatLonCprTypeHist
@@ -419,16 +1221,6 @@ func (vdb *Vessels) calculateAirbornePosition(icao int) error {
 		return nil
 	}
 
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
-
-	oldLat := vdb.Vessels[icao].Lat
-	oldLon := vdb.Vessels[icao].Lon
-
-	// TODO check times that lat/lons were received, to work out whether they are still valid
-
 	// need at least two previous positions
 	if len(vdb.Vessels[icao].airborneLatLonCprTypeHist) >= 2 {
 
@@ -462,19 +1254,19 @@ func (vdb *Vessels) calculateAirbornePosition(icao int) error {
 					return errors.New("local decoding, new position greater than 180NM from old position")
 				}
 
+				// new position must be within range of the receiver, and reachable from the last position
+				if err := vdb.checkRange(icao, lat, lon, "airborne,local"); err != nil {
+					return err
+				}
+				if err := vdb.checkPosition(icao, lat, lon, false); err != nil {
+					return err
+				}
+
 				// update lat/lon
 				vdb.Vessels[icao].Lat = lat
 				vdb.Vessels[icao].Lon = lon
 				vdb.Vessels[icao].LatLonMethod = "airborne,local"
 
-				// Need two lat/lon within ~1NM of each other before saying that we know the position
-				previousPosition := haversine.Coord{Lat: oldLat, Lon: oldLon}
-				currentPosition := haversine.Coord{Lat: vdb.Vessels[icao].Lat, Lon: vdb.Vessels[icao].Lon}
-				mi, _ := haversine.Distance(previousPosition, currentPosition)
-				if mi < 1.15 {
-					vdb.Vessels[icao].LatLonKnown = true
-				}
-
 				return nil
 
 			} else {
@@ -484,6 +1276,15 @@ func (vdb *Vessels) calculateAirbornePosition(icao int) error {
 
 			// global unambiguous decoding
 
+			// odd & even positions must be recent & received close together
+			v := vdb.Vessels[icao]
+			if !v.airborneLatLonCprOddKnown || !v.airborneLatLonCprEvenKnown {
+				return errors.New("odd and even cpr positions not both known")
+			}
+			if pairing := v.airborneLatLonCprOddTime.Sub(v.airborneLatLonCprEvenTime).Abs(); pairing > airborneCprPairingWindow {
+				return fmt.Errorf("odd and even cpr positions too far apart (pairing: %s)", pairing)
+			}
+
 			// calc latitude
 			latEven, latOdd := common.AirborneLatGloballyUnambiguous(float64(vdb.Vessels[icao].airborneLatCprEven), float64(vdb.Vessels[icao].airborneLatCprOdd))
 
@@ -498,24 +1299,26 @@ func (vdb *Vessels) calculateAirbornePosition(icao int) error {
 			lonEven, lonOdd := common.AirborneLonGloballyUnambiguous(float64(vdb.Vessels[icao].airborneLonCprEven), float64(vdb.Vessels[icao].airborneLonCprOdd), NLeven)
 
 			// work out which frame type to return
+			var lat, lon float64
 			switch vdb.Vessels[icao].airborneLatLonCprTypeHist[len(vdb.Vessels[icao].airborneLatLonCprTypeHist)-1] {
 			case common.CprFormatEvenFrame:
-				vdb.Vessels[icao].Lat = latEven
-				vdb.Vessels[icao].Lon = lonEven
+				lat, lon = latEven, lonEven
 			case common.CprFormatOddFrame:
-				vdb.Vessels[icao].Lat = latOdd
-				vdb.Vessels[icao].Lon = lonOdd
+				lat, lon = latOdd, lonOdd
 			}
-			vdb.Vessels[icao].LatLonMethod = "airborne,global"
 
-			// Need two lat/lon within ~1NM of each other before saying that we know the position
-			previousPosition := haversine.Coord{Lat: oldLat, Lon: oldLon}
-			currentPosition := haversine.Coord{Lat: vdb.Vessels[icao].Lat, Lon: vdb.Vessels[icao].Lon}
-			mi, _ := haversine.Distance(previousPosition, currentPosition)
-			if mi < 1.15 {
-				vdb.Vessels[icao].LatLonKnown = true
+			// new position must be within range of the receiver, and reachable from the last position
+			if err := vdb.checkRange(icao, lat, lon, "airborne,global"); err != nil {
+				return err
+			}
+			if err := vdb.checkPosition(icao, lat, lon, false); err != nil {
+				return err
 			}
 
+			vdb.Vessels[icao].Lat = lat
+			vdb.Vessels[icao].Lon = lon
+			vdb.Vessels[icao].LatLonMethod = "airborne,global"
+
 			return nil
 		}
 	}
@@ -529,21 +1332,10 @@ func (vdb *Vessels) calculateSurfacePosition(icao int) error {
 		return nil
 	}
 
-	vdb.mu.RLock()
-	defer vdb.mu.RUnlock()
-
 	if !vdb.refLatLonKnown {
 		return errors.New("cannot decode surface position without receiver lat/lon")
 	}
 
-	vdb.Vessels[icao].mu.Lock()
-	defer vdb.Vessels[icao].mu.Unlock()
-
-	oldLat := vdb.Vessels[icao].Lat
-	oldLon := vdb.Vessels[icao].Lon
-
-	// TODO check times that lat/lons were received, to work out whether they are still valid
-
 	// need at least two previous positions
 	if len(vdb.Vessels[icao].surfaceLatLonCprTypeHist) >= 2 {
 
@@ -577,19 +1369,19 @@ func (vdb *Vessels) calculateSurfacePosition(icao int) error {
 					return errors.New("local decoding, new position greater than 180NM from old position")
 				}
 
+				// new position must be within range of the receiver, and reachable from the last position
+				if err := vdb.checkRange(icao, lat, lon, "surface,local"); err != nil {
+					return err
+				}
+				if err := vdb.checkPosition(icao, lat, lon, true); err != nil {
+					return err
+				}
+
 				// update lat/lon
 				vdb.Vessels[icao].Lat = lat
 				vdb.Vessels[icao].Lon = lon
 				vdb.Vessels[icao].LatLonMethod = "surface,local"
 
-				// Need two lat/lon within ~1NM of each other before saying that we know the position
-				previousPosition := haversine.Coord{Lat: oldLat, Lon: oldLon}
-				currentPosition := haversine.Coord{Lat: vdb.Vessels[icao].Lat, Lon: vdb.Vessels[icao].Lon}
-				mi, _ := haversine.Distance(previousPosition, currentPosition)
-				if mi < 1.15 {
-					vdb.Vessels[icao].LatLonKnown = true
-				}
-
 				return nil
 
 			} else {
@@ -599,6 +1391,15 @@ func (vdb *Vessels) calculateSurfacePosition(icao int) error {
 
 			// global unambiguous decoding
 
+			// odd & even positions must be recent & received close together
+			v := vdb.Vessels[icao]
+			if !v.surfaceLatLonCprOddKnown || !v.surfaceLatLonCprEvenKnown {
+				return errors.New("odd and even cpr positions not both known")
+			}
+			if pairing := v.surfaceLatLonCprOddTime.Sub(v.surfaceLatLonCprEvenTime).Abs(); pairing > surfaceCprPairingWindow {
+				return fmt.Errorf("odd and even cpr positions too far apart (pairing: %s)", pairing)
+			}
+
 			// calc latitude
 			latEven, latOdd := common.SurfaceLatGloballyUnambiguous(vdb.refLat, float64(vdb.Vessels[icao].surfaceLatCprEven), float64(vdb.Vessels[icao].surfaceLatCprOdd))
 
@@ -613,24 +1414,26 @@ func (vdb *Vessels) calculateSurfacePosition(icao int) error {
 			lonEven, lonOdd := common.SurfaceLonGloballyUnambiguous(vdb.refLon, float64(vdb.Vessels[icao].surfaceLonCprEven), float64(vdb.Vessels[icao].surfaceLonCprOdd), NLeven)
 
 			// work out which frame type to return
+			var lat, lon float64
 			switch vdb.Vessels[icao].surfaceLatLonCprTypeHist[len(vdb.Vessels[icao].surfaceLatLonCprTypeHist)-1] {
 			case common.CprFormatEvenFrame:
-				vdb.Vessels[icao].Lat = latEven
-				vdb.Vessels[icao].Lon = lonEven
+				lat, lon = latEven, lonEven
 			case common.CprFormatOddFrame:
-				vdb.Vessels[icao].Lat = latOdd
-				vdb.Vessels[icao].Lon = lonOdd
+				lat, lon = latOdd, lonOdd
 			}
-			vdb.Vessels[icao].LatLonMethod = "surface,global"
 
-			// Need two lat/lon within ~1NM of each other before saying that we know the position
-			previousPosition := haversine.Coord{Lat: oldLat, Lon: oldLon}
-			currentPosition := haversine.Coord{Lat: vdb.Vessels[icao].Lat, Lon: vdb.Vessels[icao].Lon}
-			mi, _ := haversine.Distance(previousPosition, currentPosition)
-			if mi < 1.15 {
-				vdb.Vessels[icao].LatLonKnown = true
+			// new position must be within range of the receiver, and reachable from the last position
+			if err := vdb.checkRange(icao, lat, lon, "surface,global"); err != nil {
+				return err
+			}
+			if err := vdb.checkPosition(icao, lat, lon, true); err != nil {
+				return err
 			}
 
+			vdb.Vessels[icao].Lat = lat
+			vdb.Vessels[icao].Lon = lon
+			vdb.Vessels[icao].LatLonMethod = "surface,global"
+
 			return nil
 		}
 	}
@@ -640,6 +1443,8 @@ func (vdb *Vessels) calculateSurfacePosition(icao int) error {
 func (vdb *Vessels) updateFromBDS07(icao int, frame bds.BDS07Frame) {
 	// BDS 0,7 - Extended squitter status
 
+	vdb.setOperationalStatus(icao, frame)
+
 	switch frame.Ver {
 	case 1:
 		switch frame.St {
@@ -667,22 +1472,27 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 
 	log := log.With().Str("component", "vesselstate").Str("icao", fmt.Sprintf("%06x", icao)).Str("mb", fmt.Sprintf("%07x", mb)).Str("data", fmt.Sprintf("%x", data)).Logger()
 
-	possibleBDS, err := bds.InferBDS(df, mb)
+	inferred, err := vdb.inference.Infer(icao, df, mb, vdb.inferenceContext(icao))
+	if log.Debug().Enabled() {
+		for _, candidate := range inferred.Report.Candidates {
+			log.Debug().Any("bds", candidate.BDS).Bool("accepted", candidate.Accepted).Str("reason", candidate.Reason).Msg("bds candidate")
+		}
+	}
 	if err != nil {
-		log.Warn().AnErr("err", err).Hex("data", data).Msg("problem inferring BDS code")
+		if len(inferred.Candidates) > 1 || log.Debug().Enabled() {
+			log.Warn().AnErr("err", err).Any("candidates", inferred.Candidates).Hex("data", data).Msg("problem inferring BDS code")
+		}
 		return
-	} else {
-		log.Debug().Any("bds", possibleBDS).Msg("inferred bds")
 	}
-
-	if len(possibleBDS) != 1 {
+	if inferred.Confidence < minInferenceConfidence {
 		if log.Debug().Enabled() {
-			log.Warn().Any("bds", possibleBDS).Msg("need at least one BDS messages")
+			log.Debug().Any("candidates", inferred.Candidates).Float64("confidence", inferred.Confidence).Msg("bds confidence too low")
 		}
 		return
 	}
+	log.Debug().Any("bds", inferred.BDS).Float64("confidence", inferred.Confidence).Msg("inferred bds")
 
-	switch possibleBDS[0] {
+	switch inferred.BDS {
 
 	// if message contains BDS05 frame:
 	case bds.BDS05:
@@ -693,6 +1503,14 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 		if bds05frame.Tc < 19 {
 			vdb.setAltitude(icao, int(math.Round(bds05frame.Altitude)))
 		}
+
+		// discard low integrity positions
+		if !vdb.updatePositionQuality(icao, bds05frame.Tc, int(bds05frame.SAF)) {
+			if log.Debug().Enabled() {
+				log.Debug().Int("tc", bds05frame.Tc).Msg("airborne position below minimum NIC")
+			}
+			return
+		}
 		vdb.storeAirborneLatLonCPR(icao, bds05frame.LatCpr, bds05frame.LonCpr, bds05frame.F)
 
 		// see if we can calculate position
@@ -701,7 +1519,9 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 			if log.Debug().Enabled() {
 				log.Err(err).Msg("could not determine airborne lat/lon")
 			}
+			return
 		}
+		vdb.emitPosition(icao)
 		return
 
 	// if message contains BDS06 frame:
@@ -709,9 +1529,23 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 		bds06frame, err := bds.DecodeBDS06(mb)
 		if err != nil {
 			log.Err(err).Msg("error decoding BDS06 frame")
+			return
+		}
+		vdb.setSurfaceMovement(icao, bds06frame)
+		if bds06frame.Mov != 0 {
+			vdb.setGroundSpeed(icao, bds06frame.GroundSpeed)
+		}
+		if bds06frame.S == bds.GroundTrackStatusValid {
+			vdb.setGroundTrack(icao, bds06frame.GroundTrack)
+		}
+
+		// discard low integrity positions
+		if !vdb.updatePositionQuality(icao, bds06frame.Tc, 0) {
+			if log.Debug().Enabled() {
+				log.Debug().Int("tc", bds06frame.Tc).Msg("surface position below minimum NIC")
+			}
+			return
 		}
-		vdb.setGroundSpeed(icao, bds06frame.GroundSpeed)
-		vdb.setGroundTrack(icao, bds06frame.GroundTrack)
 		vdb.storeSurfaceLatLonCPR(icao, bds06frame.LatCpr, bds06frame.LonCpr, bds06frame.F)
 
 		// see if we can calculate position
@@ -720,7 +1554,9 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 			if log.Debug().Enabled() {
 				log.Err(err).Msg("could not determine surface lat/lon")
 			}
+			return
 		}
+		vdb.emitPosition(icao)
 
 		return
 
@@ -728,7 +1564,8 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 	case bds.BDS07:
 		bds07frame, err := bds.DecodeBDS07(mb)
 		if err != nil {
-			log.Err(err).Msg("error decoding BDS06 frame")
+			log.Err(err).Msg("error decoding BDS07 frame")
+			return
 		}
 		vdb.updateFromBDS07(icao, bds07frame)
 		return
@@ -737,20 +1574,42 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 	case bds.BDS08:
 		bds08frame, err := bds.DecodeBDS08(mb)
 		if err != nil {
-			log.Err(err).Msg("error decoding BDS06 frame")
+			log.Err(err).Msg("error decoding BDS08 frame")
+			return
 		}
 		vdb.setCallsign(icao, bds08frame.Callsign)
+		vdb.setCategory(icao, bds08frame)
 		return
 
-	// TODO: if message contains BDS09 frame:
+	// if message contains BDS09 frame:
 	case bds.BDS09:
+		bds09frame, err := bds.DecodeBDS09(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS09 frame")
+			return
+		}
+		vdb.setNACv(icao, bds09frame.NACv)
+		vdb.setVelocity(icao, bds09frame)
 		return
 
-	// TODO: if message contains BDS10 frame:
+	// if message contains BDS10 frame:
 	case bds.BDS10:
+		bds10frame, err := bds.DecodeBDS10(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS10 frame")
+			return
+		}
+		vdb.setDataLinkCapability(icao, bds10frame)
 		return
 
+	// if message contains BDS17 frame:
 	case bds.BDS17:
+		bds17frame, err := bds.DecodeBDS17(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS17 frame")
+			return
+		}
+		vdb.setGICBCapability(icao, bds17frame)
 		return
 
 	// if message contains BDS20 frame:
@@ -766,11 +1625,35 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 	case bds.BDS40:
 		return
 
+	// if message contains BDS44 frame:
+	case bds.BDS44:
+		bds44frame, err := bds.DecodeBDS44(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS44 frame")
+			return
+		}
+		vdb.setMeteoRoutineReport(icao, bds44frame)
+
+		// pass on as an observation
+		vdb.emitMeteoObservation(icao, meteo.FromBDS44(icao, vdb.now(), bds44frame))
+		return
+
+	// if message contains BDS45 frame:
+	case bds.BDS45:
+		bds45frame, err := bds.DecodeBDS45(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS45 frame")
+			return
+		}
+		vdb.setMeteoHazardReport(icao, bds45frame)
+		return
+
 	// if message contains BDS50 frame:
 	case bds.BDS50:
 		bds50frame, err := bds.DecodeBDS50(mb)
 		if err != nil {
 			log.Err(err).Msg("error decoding BDS50 frame")
+			return
 		}
 
 		if bds50frame.GroundSpeedValid {
@@ -784,27 +1667,106 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 			vdb.setGroundTrack(icao, fmt.Sprintf("%d°", tta))
 		}
 
+		// see if we can derive wind/temperature
+		obs, ok := vdb.meteo.AddBDS50(icao, vdb.now(), bds50frame)
+		if ok {
+			vdb.emitMeteoObservation(icao, obs)
+		}
+
+		return
+
+	// if message contains BDS53 frame:
+	case bds.BDS53:
+		bds53frame, err := bds.DecodeBDS53(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS53 frame")
+			return
+		}
+		vdb.setAirStateVector(icao, bds53frame)
+		return
+
+	// if message contains BDS54, BDS55 or BDS56 frame:
+	case bds.BDS54, bds.BDS55, bds.BDS56:
+		// BDS 5,5 & 5,6 share BDS 5,4's format
+		bds54frame, err := bds.DecodeBDS54(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding waypoint frame")
+			return
+		}
+		vdb.setWaypoint(icao, bds54frame)
 		return
 
-	// TODO: if message contains BDS60 frame:
+	// if message contains BDS60 frame:
 	case bds.BDS60:
+		bds60frame, err := bds.DecodeBDS60(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS60 frame")
+			return
+		}
+
+		// see if we can derive wind/temperature
+		obs, ok := vdb.meteo.AddBDS60(icao, vdb.now(), bds60frame)
+		if ok {
+			vdb.emitMeteoObservation(icao, obs)
+		}
+
 		return
 
-	default:
+	// if message contains BDS65 frame (version 0 operational status):
+	case bds.BDS65:
+		bds65frame, err := bds.DecodeBDS65(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS65 frame")
+			return
+		}
+		vdb.setOperationalStatus(icao, bds.BDS07Frame{
+			Tc:  31,
+			Ver: 0,
+			Version0Data: bds.BDS07FrameVersion0{
+				CC4: bds65frame.CC4,
+				CC3: bds65frame.CC3,
+				CC2: bds65frame.CC2,
+				CC1: bds65frame.CC1,
+				OM4: bds65frame.OM4,
+				OM3: bds65frame.OM3,
+				OM2: bds65frame.OM2,
+				OM1: bds65frame.OM1,
+			},
+		})
+		return
 
-		// if message contains BDS61 frame:
-		_, err = bds.DecodeBDS61(mb)
-		if err == nil {
-			// TODO
+	// if message contains BDS61 frame:
+	case bds.BDS61:
+		bds61frame, err := bds.DecodeBDS61(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS61 frame")
 			return
 		}
+		switch bds61frame.St {
+		case 1:
+			vdb.setEmergencyStatus(icao, bds61frame.EmergencyStatus)
+			vdb.setSquawkCode(icao, bds61frame.Squawk)
+			vdb.updateEmergency(icao)
+		case 2:
+			vdb.setTcasRA(icao, bds61frame.TcasRA)
+		}
+		return
 
-		// if message contains BDS61 frame:
-		_, err = bds.DecodeBDS62(mb)
-		if err == nil {
-			// TODO
+	// if message contains BDS62 frame:
+	case bds.BDS62:
+		bds62frame, err := bds.DecodeBDS62(mb)
+		if err != nil {
+			log.Err(err).Msg("error decoding BDS62 frame")
 			return
 		}
+		vdb.setTargetState(icao, bds62frame)
+
+		// version 1 also carries emergency/priority status
+		if bds62frame.St == 0 {
+			vdb.setEmergencyStatus(icao, bds62frame.EmergencyStatus)
+			vdb.updateEmergency(icao)
+		}
+		return
 	}
 
 	log.Warn().Msg("type code not handled")
@@ -812,76 +1774,104 @@ func (vdb *Vessels) updateFromCommB(icao int, mb []byte, df df.DownlinkFormat, d
 
 func (vdb *Vessels) UpdateFromDF0(msg df.DF0message) {
 	// updates vessel status based on information from DF0 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF4(msg df.DF4message) {
 	// updates vessel status based on information from DF4 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		if msg.AirborneKnown {
+			vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		}
+		vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF5(msg df.DF5message) {
 	// updates vessel status based on information from DF5 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.setSquawkCode(msg.ICAO, msg.Squawk)
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		if msg.AirborneKnown {
+			vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		}
+		vdb.setSquawkCode(msg.ICAO, msg.Squawk)
+		vdb.updateEmergency(msg.ICAO)
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF11(msg df.DF11message) {
 	// updates vessel status based on information from DF11 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF16(msg df.DF16message) {
 	// updates vessel status based on information from DF16 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		vdb.setAltitude(msg.ICAO, int(math.Round(msg.Altitude)))
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF17(msg df.DF17message, data []byte) {
 	// updates vessel status based on information from DF17 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.updateFromCommB(msg.ICAO, msg.ME, df.DF17, data)
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		vdb.updateFromCommB(msg.ICAO, msg.ME, df.DF17, data)
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF18(msg df.DF18message, data []byte) {
 	// updates vessel status based on information from DF18 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.updateFromCommB(msg.ICAO, msg.ME, df.DF18, data)
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		vdb.updateFromCommB(msg.ICAO, msg.ME, df.DF18, data)
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF20(msg df.DF20message, data []byte) {
 	// updates vessel status based on information from DF20 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAltitude(msg.ICAO, int(msg.Altitude))
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.updateFromCommB(msg.ICAO, msg.MB, df.DF20, data)
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		vdb.setAltitude(msg.ICAO, int(msg.Altitude))
+		if msg.AirborneKnown {
+			vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		}
+		vdb.updateFromCommB(msg.ICAO, msg.MB, df.DF20, data)
 
-	// TODO: Downlink request
-	// TODO: Utility message
+		// TODO: Downlink request
+		// TODO: Utility message
+	})
 }
 
 func (vdb *Vessels) UpdateFromDF21(msg df.DF21message, data []byte) {
 	// updates vessel status based on information from DF21 message
-	vdb.addVessel(msg.ICAO)
-	vdb.incrementMessageCount(msg.ICAO)
-	vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
-	vdb.setSquawkCode(msg.ICAO, msg.Squawk)
-	vdb.updateFromCommB(msg.ICAO, msg.MB, df.DF21, data)
-
-	// TODO: Downlink request
-	// TODO: Utility message
+	vdb.update(func() {
+		vdb.addVessel(msg.ICAO)
+		vdb.incrementMessageCount(msg.ICAO)
+		if msg.AirborneKnown {
+			vdb.setAirborneStatus(msg.ICAO, msg.Airborne)
+		}
+		vdb.setSquawkCode(msg.ICAO, msg.Squawk)
+		vdb.updateEmergency(msg.ICAO)
+		vdb.updateFromCommB(msg.ICAO, msg.MB, df.DF21, data)
+
+		// TODO: Downlink request
+		// TODO: Utility message
+	})
 }
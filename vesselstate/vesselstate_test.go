@@ -0,0 +1,658 @@
+package vesselstate
+
+import (
+	"beastdecoder/bds"
+	"beastdecoder/df"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// airborne position, ICAO 40621D - https://mode-s.org/decode/content/ads-b/3-airborne-position.html
+var testAirbornePositionEven = []byte{0x8d, 0x40, 0x62, 0x1d, 0x58, 0xc3, 0x82, 0xd6, 0x90, 0xc8, 0xac, 0x28, 0x63, 0xa7}
+var testAirbornePositionOdd = []byte{0x8d, 0x40, 0x62, 0x1d, 0x58, 0xc3, 0x86, 0x43, 0x5c, 0xc4, 0x12, 0x69, 0x2a, 0xd6}
+
+const testICAO = 0x40621D
+
+func initTestVessels() (*Vessels, *ManualClock) {
+	// returns a vessel db driven by a manual clock
+	clock := &ManualClock{}
+	clock.Set(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	vdb := &Vessels{}
+	vdb.SetClock(clock)
+	vdb.Init()
+	return vdb, clock
+}
+
+func TestEvict(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		since           time.Duration // since last message
+		expectedTracked bool
+	}{
+		{since: time.Second * 59, expectedTracked: true},
+		{since: time.Second * 60, expectedTracked: true},
+		{since: time.Second * 61, expectedTracked: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("since: %s, ", testData.since)
+		vdb, clock := initTestVessels()
+		vdb.UpdateFromDF17(df.DecodeDF17(testAirbornePositionEven), testAirbornePositionEven)
+		clock.Advance(testData.since)
+		vdb.Evict()
+		assert.Equal(testData.expectedTracked, vdb.isVesselTracked(testICAO), testMsg+"tracked")
+	}
+}
+
+func TestRunEvictorStops(t *testing.T) {
+	vdb, _ := initTestVessels()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		vdb.RunEvictor(ctx)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("evictor didn't stop")
+	}
+}
+
+func TestEvictClearsStalePosition(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		since         time.Duration // since last position
+		expectedKnown bool
+	}{
+		{since: time.Second, expectedKnown: true},
+		{since: time.Second * 3, expectedKnown: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("since: %s, ", testData.since)
+		vdb, clock := initTestVessels()
+		for _, data := range [][]byte{testAirbornePositionOdd, testAirbornePositionEven, testAirbornePositionOdd} {
+			vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+			clock.Advance(time.Millisecond * 500)
+		}
+		assert.True(vdb.Vessels[testICAO].LatLonKnown, testMsg+"LatLonKnown before evict")
+
+		clock.Advance(testData.since)
+		vdb.Evict()
+		assert.Equal(testData.expectedKnown, vdb.Vessels[testICAO].LatLonKnown, testMsg+"LatLonKnown")
+	}
+}
+
+func TestCprPairing(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		gap           time.Duration // between odd & even positions
+		expectedKnown bool
+		expectedLat   float64
+		expectedLon   float64
+	}{
+		{gap: time.Second, expectedKnown: true, expectedLat: 52.25720, expectedLon: 3.91937},
+		{gap: time.Second * 10, expectedKnown: true, expectedLat: 52.25720, expectedLon: 3.91937},
+		{gap: time.Second * 11, expectedKnown: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("gap: %s, ", testData.gap)
+		vdb, clock := initTestVessels()
+
+		var positions []Position
+		vdb.OnPosition(func(pos Position) {
+			positions = append(positions, pos)
+		})
+
+		// global decoding twice, to confirm position
+		for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+			vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+			clock.Advance(testData.gap)
+		}
+
+		v := vdb.Vessels[testICAO]
+		assert.Equal(testData.expectedKnown, v.LatLonKnown, testMsg+"LatLonKnown")
+		if testData.expectedKnown {
+			assert.InDelta(testData.expectedLat, v.Lat, 0.00001, testMsg+"Lat")
+			assert.InDelta(testData.expectedLon, v.Lon, 0.00001, testMsg+"Lon")
+			if assert.Len(positions, 1, testMsg+"positions") {
+				assert.Equal(clock.Now().Add(-testData.gap), positions[0].Time, testMsg+"position time")
+			}
+		} else {
+			assert.Empty(positions, testMsg+"positions")
+		}
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	assert := assert.New(t)
+
+	vdb, clock := initTestVessels()
+
+	// observers may read the db, as they're called once the update is complete
+	positions := 0
+	vdb.OnPosition(func(pos Position) {
+		if v, ok := vdb.Get(pos.ICAO); ok && v.LatLonKnown {
+			positions++
+		}
+	})
+
+	// updates, eviction & reads from separate goroutines
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			vdb.Evict()
+			for _, v := range vdb.Snapshot(Filter{}) {
+				_ = v.LatLonKnown
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd} {
+			vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+		}
+		clock.Advance(time.Millisecond * 10)
+	}
+	<-done
+
+	assert.True(vdb.isVesselTracked(testICAO))
+	assert.Greater(positions, 0)
+}
+
+func TestSnapshot(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name          string
+		filter        Filter
+		expectedICAOs []int
+	}{
+		{name: "all", filter: Filter{}, expectedICAOs: []int{testICAO, 0x4840D6}},
+		{name: "icao", filter: Filter{ICAOs: []int{testICAO}}, expectedICAOs: []int{testICAO}},
+		{name: "icao not tracked", filter: Filter{ICAOs: []int{0xABCDEF}}, expectedICAOs: []int{}},
+		{name: "bounds", filter: Filter{Bounds: &Bounds{MinLat: 52, MinLon: 3, MaxLat: 53, MaxLon: 4}}, expectedICAOs: []int{testICAO}},
+		{name: "bounds excluding position", filter: Filter{Bounds: &Bounds{MinLat: 50, MinLon: 3, MaxLat: 51, MaxLon: 4}}, expectedICAOs: []int{}},
+		{name: "bounds across antimeridian", filter: Filter{Bounds: &Bounds{MinLat: 52, MinLon: 170, MaxLat: 53, MaxLon: 4}}, expectedICAOs: []int{testICAO}},
+		{name: "max age", filter: Filter{MaxAge: time.Second * 5}, expectedICAOs: []int{testICAO}},
+	}
+
+	// identification from 4840D6, then positions from 40621D 10 seconds later
+	testIdentification := []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe0, 0x57, 0x60, 0x98}
+	vdb, clock := initTestVessels()
+	vdb.UpdateFromDF17(df.DecodeDF17(testIdentification), testIdentification)
+	clock.Advance(time.Second * 10)
+	for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+		vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("filter: %s, ", testData.name)
+		icaos := []int{}
+		for _, v := range vdb.Snapshot(testData.filter) {
+			icaos = append(icaos, v.ICAO)
+		}
+		assert.Equal(testData.expectedICAOs, icaos, testMsg+"ICAOs")
+	}
+
+	// copies aren't changed by later updates
+	v, ok := vdb.Get(0x4840D6)
+	assert.True(ok)
+	assert.Equal("KLM1023", v.Callsign)
+	assert.Equal(1, v.MsgCount)
+	vdb.UpdateFromDF17(df.DecodeDF17(testIdentification), testIdentification)
+	assert.Equal(1, v.MsgCount)
+	v, _ = vdb.Get(0x4840D6)
+	assert.Equal(2, v.MsgCount)
+
+	_, ok = vdb.Get(0xABCDEF)
+	assert.False(ok)
+}
+
+func TestSubscribe(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name           string
+		filter         Filter
+		types          []EventType
+		expectedEvents []EventType
+	}{
+		{name: "all", expectedEvents: []EventType{EventAdded, EventCallsign, EventAdded, EventPosition, EventRemoved, EventRemoved}},
+		{name: "types", types: []EventType{EventAdded, EventRemoved}, expectedEvents: []EventType{EventAdded, EventAdded, EventRemoved, EventRemoved}},
+		{name: "icao", filter: Filter{ICAOs: []int{testICAO}}, expectedEvents: []EventType{EventAdded, EventPosition, EventRemoved}},
+		{name: "bounds", filter: Filter{Bounds: &Bounds{MinLat: 52, MinLon: 3, MaxLat: 53, MaxLon: 4}}, expectedEvents: []EventType{EventPosition, EventRemoved}},
+	}
+
+	testIdentification := []byte{0x8d, 0x48, 0x40, 0xd6, 0x20, 0x2c, 0xc3, 0x71, 0xc3, 0x2c, 0xe0, 0x57, 0x60, 0x98}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("subscription: %s, ", testData.name)
+		vdb, clock := initTestVessels()
+		sub := vdb.Subscribe(testData.filter, testData.types...)
+
+		// identification twice, callsign only changes once
+		for _, data := range [][]byte{testIdentification, testIdentification, testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+			vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+		}
+		clock.Advance(vesselExpiry + time.Second)
+		vdb.Evict()
+		sub.Close()
+
+		var events []EventType
+		for event := range sub.C {
+			events = append(events, event.Type)
+			assert.Equal(event.ICAO, event.Vessel.ICAO, testMsg+"ICAO")
+			if event.Type == EventPosition {
+				assert.True(event.Vessel.LatLonKnown, testMsg+"LatLonKnown")
+			}
+		}
+
+		// eviction order isn't defined
+		assert.ElementsMatch(testData.expectedEvents, events, testMsg+"events")
+		assert.Zero(sub.Dropped(), testMsg+"dropped")
+	}
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	assert := assert.New(t)
+
+	vdb, clock := initTestVessels()
+	sub := vdb.Subscribe(Filter{}, EventAdded)
+
+	// each eviction & update adds the vessel again
+	for i := 0; i < subscriptionBufferLength+10; i++ {
+		vdb.UpdateFromDF17(df.DecodeDF17(testAirbornePositionEven), testAirbornePositionEven)
+		clock.Advance(vesselExpiry + time.Second)
+		vdb.Evict()
+	}
+
+	assert.Len(sub.C, subscriptionBufferLength)
+	assert.Equal(uint64(10), sub.Dropped())
+}
+
+func TestPositionHistory(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name          string
+		positions     int
+		pruneAfter    int // positions before this are pruned
+		expectedFirst int
+		expectedLen   int
+	}{
+		{name: "empty", positions: 0, expectedLen: 0},
+		{name: "growing", positions: 100, expectedFirst: 0, expectedLen: 100},
+		{name: "full", positions: maxHistoryPoints, expectedFirst: 0, expectedLen: maxHistoryPoints},
+		{name: "wrapped", positions: maxHistoryPoints + 10, expectedFirst: 10, expectedLen: maxHistoryPoints},
+		{name: "pruned", positions: 100, pruneAfter: 40, expectedFirst: 40, expectedLen: 60},
+		{name: "wrapped & pruned", positions: maxHistoryPoints + 10, pruneAfter: 100, expectedFirst: 100, expectedLen: maxHistoryPoints - 90},
+		{name: "all pruned", positions: 100, pruneAfter: 100, expectedLen: 0},
+	}
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("history: %s, ", testData.name)
+		var h positionHistory
+		for i := 0; i < testData.positions; i++ {
+			h.add(Position{Time: start.Add(time.Second * time.Duration(i))})
+		}
+		h.prune(start.Add(time.Second * time.Duration(testData.pruneAfter)))
+
+		positions := h.positions()
+		assert.Len(positions, testData.expectedLen, testMsg+"len")
+		assert.LessOrEqual(len(h.buf), maxHistoryPoints, testMsg+"buffer size")
+		for i, pos := range positions {
+			assert.Equal(start.Add(time.Second*time.Duration(testData.expectedFirst+i)), pos.Time, testMsg+fmt.Sprintf("position %d", i))
+		}
+	}
+}
+
+func TestSnapshotHistory(t *testing.T) {
+	vdb, clock := initTestVessels()
+	vdb.SetHistoryDuration(time.Second * 30)
+	for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven, testAirbornePositionOdd} {
+		clock.Advance(time.Second)
+		vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+	}
+
+	assert := assert.New(t)
+
+	// only included when requested
+	vessels := vdb.Snapshot(Filter{})
+	assert.Len(vessels, 1)
+	assert.Empty(vessels[0].History)
+	vessels = vdb.Snapshot(Filter{History: true})
+	assert.Len(vessels, 1)
+	assert.Len(vessels[0].History, 2)
+	for _, pos := range vessels[0].History {
+		assert.Equal(testICAO, pos.ICAO)
+		assert.InDelta(52.26, pos.Lat, 0.05)
+		assert.InDelta(3.93, pos.Lon, 0.05)
+		assert.True(pos.AltitudeKnown)
+	}
+	assert.True(vessels[0].History[0].Time.Before(vessels[0].History[1].Time))
+
+	// older positions are dropped on eviction
+	clock.Advance(time.Millisecond * 29500)
+	vdb.Evict()
+	v, ok := vdb.Get(testICAO)
+	assert.True(ok)
+	assert.Len(v.History, 1)
+
+	// disabled
+	vdb, _ = initTestVessels()
+	vdb.SetHistoryDuration(0)
+	for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+		vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+	}
+	v, _ = vdb.Get(testICAO)
+	assert.True(v.LatLonKnown)
+	assert.Empty(v.History)
+}
+
+func TestCheckPosition(t *testing.T) {
+	type testFix struct {
+		after             time.Duration // since the previous fix
+		lat, lon          float64
+		expectedPlausible bool
+	}
+
+	// define test data, 0.05 degrees of latitude is 3NM
+	var testTable = []struct {
+		name                string
+		velocity            float64 // reported ground speed (kt), 0 if not reported
+		fixes               []testFix
+		expectedReliability int
+		expectedKnown       bool
+	}{
+		{
+			name:                "first fix",
+			fixes:               []testFix{{lat: 52, lon: 4, expectedPlausible: true}},
+			expectedReliability: 1,
+			expectedKnown:       false,
+		},
+		{
+			name: "unknown speed",
+			fixes: []testFix{
+				{lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second * 10, lat: 52.05, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 53.05, lon: 4, expectedPlausible: false},
+			},
+			expectedReliability: 1,
+			expectedKnown:       true,
+		},
+		{
+			name:     "reported speed",
+			velocity: 200,
+			fixes: []testFix{
+				{lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52.005, lon: 4, expectedPlausible: true},
+				{after: time.Second * 10, lat: 52.055, lon: 4, expectedPlausible: false},
+			},
+			expectedReliability: 1,
+			expectedKnown:       true,
+		},
+		{
+			name: "reliability capped",
+			fixes: []testFix{
+				{lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+			},
+			expectedReliability: maxPositionReliability,
+			expectedKnown:       true,
+		},
+		{
+			name: "recovers from bad first fix",
+			fixes: []testFix{
+				{lat: 10, lon: 10, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: false},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+				{after: time.Second, lat: 52, lon: 4, expectedPlausible: true},
+			},
+			expectedReliability: 2,
+			expectedKnown:       true,
+		},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("fixes: %s, ", testData.name)
+		vdb, clock := initTestVessels()
+		vdb.addVessel(testICAO)
+		v := vdb.Vessels[testICAO]
+		for i, fix := range testData.fixes {
+			clock.Advance(fix.after)
+			if testData.velocity > 0 {
+				v.VelocityKnown = true
+				v.VelocitySpeed = testData.velocity
+				v.VelocityUpdated = vdb.now()
+			}
+			err := vdb.checkPosition(testICAO, fix.lat, fix.lon, false)
+			if err == nil {
+				v.Lat, v.Lon = fix.lat, fix.lon
+			}
+			assert.Equal(fix.expectedPlausible, err == nil, testMsg+fmt.Sprintf("fix %d plausible", i))
+		}
+		assert.Equal(testData.expectedReliability, v.PositionReliability, testMsg+"reliability")
+		assert.Equal(testData.expectedKnown, v.LatLonKnown, testMsg+"known")
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	// define test data, receiver at 52N 4E
+	var testTable = []struct {
+		name             string
+		maxRange         float64
+		lat, lon         float64
+		method           string
+		expectedAccepted bool
+	}{
+		{name: "within range", maxRange: 300, lat: 53, lon: 4, method: "airborne,global", expectedAccepted: true},
+		{name: "beyond range", maxRange: 300, lat: 58, lon: 4, method: "airborne,global", expectedAccepted: false},
+		{name: "beyond range, local", maxRange: 30, lat: 53, lon: 4, method: "airborne,local", expectedAccepted: false},
+		{name: "no max range", maxRange: 0, lat: 58, lon: 4, method: "airborne,global", expectedAccepted: true},
+		{name: "surface within range", maxRange: 300, lat: 52.5, lon: 4, method: "surface,global", expectedAccepted: true},
+		{name: "surface beyond surface range", maxRange: 300, lat: 53, lon: 4, method: "surface,global", expectedAccepted: false},
+		{name: "surface beyond surface range, no max range", maxRange: 0, lat: 53, lon: 4, method: "surface,local", expectedAccepted: false},
+		{name: "surface beyond max range", maxRange: 20, lat: 52.5, lon: 4, method: "surface,global", expectedAccepted: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("range: %s, ", testData.name)
+		vdb, _ := initTestVessels()
+		vdb.SetRefLatLon(52, 4)
+		vdb.SetMaxRange(testData.maxRange)
+		var rejected []Position
+		vdb.OnRangeRejected(func(pos Position) {
+			rejected = append(rejected, pos)
+		})
+		var err error
+		vdb.update(func() {
+			err = vdb.checkRange(testICAO, testData.lat, testData.lon, testData.method)
+		})
+		assert.Equal(testData.expectedAccepted, err == nil, testMsg+"accepted")
+		if testData.expectedAccepted {
+			assert.Empty(rejected, testMsg+"rejected")
+		} else if assert.Len(rejected, 1, testMsg+"rejected") {
+			assert.Equal(testICAO, rejected[0].ICAO, testMsg+"rejected icao")
+			assert.Equal(testData.method, rejected[0].Method, testMsg+"rejected method")
+			assert.True(rejected[0].RangeKnown, testMsg+"rejected range known")
+			assert.InDelta((testData.lat-52)*60, rejected[0].Range, 0.5, testMsg+"rejected range")
+		}
+	}
+
+	// positions beyond range aren't decoded, and those within range are tagged with their range
+	for _, maxRange := range []float64{10, 30} {
+		testMsg := fmt.Sprintf("max range: %.0f, ", maxRange)
+		vdb, clock := initTestVessels()
+		vdb.SetRefLatLon(52, 4)
+		vdb.SetMaxRange(maxRange)
+		var positions []Position
+		vdb.OnPosition(func(pos Position) {
+			positions = append(positions, pos)
+		})
+		for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+			clock.Advance(time.Second)
+			vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+		}
+		if maxRange < 16 {
+			assert.Empty(positions, testMsg+"positions")
+			continue
+		}
+		if assert.Len(positions, 1, testMsg+"positions") {
+			assert.True(positions[0].RangeKnown, testMsg+"range known")
+			assert.InDelta(15.6, positions[0].Range, 0.5, testMsg+"range")
+		}
+	}
+}
+
+func TestInitDefaults(t *testing.T) {
+	// define test data
+	var testTable = []struct {
+		name                    string
+		set                     func(vdb *Vessels)
+		expectedMaxRange        float64
+		expectedHistoryDuration time.Duration
+	}{
+		{name: "defaults", set: func(vdb *Vessels) {}, expectedMaxRange: DefaultMaxRange, expectedHistoryDuration: DefaultHistoryDuration},
+		{name: "set before init", set: func(vdb *Vessels) {
+			vdb.SetMaxRange(50)
+			vdb.SetHistoryDuration(time.Minute)
+		}, expectedMaxRange: 50, expectedHistoryDuration: time.Minute},
+		{name: "disabled before init", set: func(vdb *Vessels) {
+			vdb.SetMaxRange(0)
+			vdb.SetHistoryDuration(0)
+		}, expectedMaxRange: 0, expectedHistoryDuration: 0},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("init: %s, ", testData.name)
+		vdb := &Vessels{}
+		testData.set(vdb)
+		vdb.Init()
+		assert.Equal(testData.expectedMaxRange, vdb.maxRange, testMsg+"max range")
+		assert.Equal(testData.expectedHistoryDuration, vdb.historyDuration, testMsg+"history duration")
+	}
+}
+
+func TestWaypointCommB(t *testing.T) {
+	// waypoint ABCDE, ETA 15 min, FL350, structurally valid as BDS 5,4, 5,5 & 5,6
+	testWaypoint := df.DF20message{
+		Airborne:      true,
+		AirborneKnown: true,
+		Altitude:      35000,
+		ICAO:          testICAO,
+		MB:            []byte{0x02, 0x10, 0x62, 0x0A, 0x80, 0x8C, 0x80},
+	}
+
+	assert := assert.New(t)
+	vdb, clock := initTestVessels()
+
+	// stored on the first reply, and again once the register is in the aircraft's history
+	for i := 0; i < 3; i++ {
+		testMsg := fmt.Sprintf("reply: %d, ", i)
+		vdb.UpdateFromDF20(testWaypoint, nil)
+		v, ok := vdb.Get(testICAO)
+		assert.True(ok, testMsg+"tracked")
+		if assert.Len(v.Waypoints, 1, testMsg+"waypoints") {
+			assert.Equal("ABCDE", v.Waypoints[0].Name, testMsg+"name")
+			assert.Equal(350, v.Waypoints[0].FlightLevel, testMsg+"flight level")
+			assert.Equal(clock.Now().Add(time.Minute*15), v.Waypoints[0].ETA, testMsg+"eta")
+		}
+		clock.Advance(time.Second)
+	}
+}
+
+func TestExtendedSquitterNotInGICB(t *testing.T) {
+	// GICB capability report (BDS 1,7) with only BDS 2,0 & 4,0 available
+	testGICB := df.DF20message{
+		Airborne:      true,
+		AirborneKnown: true,
+		Altitude:      38000,
+		ICAO:          testICAO,
+		MB:            []byte{0x02, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00},
+	}
+
+	assert := assert.New(t)
+	vdb, clock := initTestVessels()
+	vdb.UpdateFromDF20(testGICB, nil)
+	if !assert.True(vdb.Vessels[testICAO].GICBCapabilityKnown, "gicb known") {
+		return
+	}
+	assert.False(vdb.Vessels[testICAO].GICBCapability.Supports(bds.BDS05), "supports bds 0,5")
+
+	// extended squitter positions are still decoded
+	var positions []Position
+	vdb.OnPosition(func(pos Position) {
+		positions = append(positions, pos)
+	})
+	for _, data := range [][]byte{testAirbornePositionEven, testAirbornePositionOdd, testAirbornePositionEven} {
+		clock.Advance(time.Second)
+		vdb.UpdateFromDF17(df.DecodeDF17(data), data)
+	}
+	assert.Len(positions, 1, "positions")
+}
+
+func TestGroundEvents(t *testing.T) {
+	// DF4 surveillance altitude replies from ICAO 7C7F25, differing only in flight status
+	testFlightStatus := map[int][]byte{
+		0: {0x20, 0x00, 0x02, 0x94, 0xe7, 0xdc, 0x54}, // airborne
+		1: {0x21, 0x00, 0x02, 0x94, 0xcc, 0x21, 0x07}, // on ground
+		4: {0x24, 0x00, 0x02, 0x94, 0x48, 0x29, 0x18}, // alert & SPI, airborne or on ground
+		5: {0x25, 0x00, 0x02, 0x94, 0x63, 0xd4, 0x4b}, // SPI, airborne or on ground
+	}
+
+	// define test data
+	var testTable = []struct {
+		flightStatus     []int
+		expectedEvents   int
+		expectedAirborne bool
+	}{
+		{flightStatus: []int{0, 5, 0}, expectedEvents: 0, expectedAirborne: true},
+		{flightStatus: []int{0, 4, 5, 4, 0}, expectedEvents: 0, expectedAirborne: true},
+		{flightStatus: []int{1, 5, 1}, expectedEvents: 0, expectedAirborne: false},
+		{flightStatus: []int{0, 5, 1}, expectedEvents: 1, expectedAirborne: false},
+		{flightStatus: []int{1, 0, 5, 1}, expectedEvents: 2, expectedAirborne: false},
+	}
+
+	assert := assert.New(t)
+	for _, testData := range testTable {
+		testMsg := fmt.Sprintf("flight status: %v, ", testData.flightStatus)
+		vdb, _ := initTestVessels()
+		sub := vdb.Subscribe(Filter{}, EventGround)
+		for _, fs := range testData.flightStatus {
+			msg, err := df.DecodeDF4(testFlightStatus[fs])
+			assert.NoError(err, testMsg+"DecodeDF4 error")
+			vdb.UpdateFromDF4(msg)
+		}
+		sub.Close()
+
+		events := 0
+		for range sub.C {
+			events++
+		}
+		assert.Equal(testData.expectedEvents, events, testMsg+"events")
+		v, ok := vdb.Get(0x7C7F25)
+		if assert.True(ok, testMsg+"tracked") {
+			assert.True(v.AirborneStatusKnown, testMsg+"AirborneStatusKnown")
+			assert.Equal(testData.expectedAirborne, v.Airborne, testMsg+"Airborne")
+		}
+	}
+}
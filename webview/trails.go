@@ -0,0 +1,84 @@
+package webview
+
+import (
+	"beastdecoder/vesselstate"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Size of the trail map (px)
+const trailMapWidth = 800
+const trailMapHeight = 500
+
+// Space around the trails (px)
+const trailMapMargin = 20
+
+// Trails of vessels' recent positions, projected onto the map
+type trailMap struct {
+	Width, Height int
+	Trails        []trail
+}
+
+type trail struct {
+	ICAO      int
+	Label     string // callsign, or ICAO if not known
+	Points    string // SVG polyline points, oldest first
+	X, Y      float64
+	Emergency bool
+}
+
+func newTrailMap(vessels []vesselstate.VesselState) *trailMap {
+	// projects the history of each vessel onto an equirectangular map fitted to every position
+	// returns nil if no vessel has a history
+
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	for _, v := range vessels {
+		for _, pos := range v.History {
+			minLat, maxLat = math.Min(minLat, pos.Lat), math.Max(maxLat, pos.Lat)
+			minLon, maxLon = math.Min(minLon, pos.Lon), math.Max(maxLon, pos.Lon)
+		}
+	}
+	if math.IsInf(minLat, 1) {
+		return nil
+	}
+
+	// degrees of longitude shrink towards the poles
+	xScale := math.Cos((minLat + maxLat) / 2 * math.Pi / 180)
+	width := math.Max((maxLon-minLon)*xScale, 1e-6)
+	height := math.Max(maxLat-minLat, 1e-6)
+	scale := math.Min((trailMapWidth-2*trailMapMargin)/width, (trailMapHeight-2*trailMapMargin)/height)
+
+	project := func(lat, lon float64) (x, y float64) {
+		x = trailMapMargin + (lon-minLon)*xScale*scale
+		y = trailMapHeight - trailMapMargin - (lat-minLat)*scale
+		return x, y
+	}
+
+	m := &trailMap{
+		Width:  trailMapWidth,
+		Height: trailMapHeight,
+	}
+	for _, v := range vessels {
+		if len(v.History) == 0 {
+			continue
+		}
+		t := trail{
+			ICAO:      v.ICAO,
+			Label:     fmt.Sprintf("%06x", v.ICAO),
+			Emergency: v.Emergency,
+		}
+		if v.CallsignKnown {
+			t.Label = strings.TrimSpace(v.Callsign)
+		}
+		points := make([]string, 0, len(v.History))
+		for _, pos := range v.History {
+			t.X, t.Y = project(pos.Lat, pos.Lon)
+			points = append(points, fmt.Sprintf("%.1f,%.1f", t.X, t.Y))
+		}
+		t.Points = strings.Join(points, " ")
+		m.Trails = append(m.Trails, t)
+	}
+	return m
+}
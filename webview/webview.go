@@ -1,12 +1,19 @@
 package webview
 
 import (
+	"beastdecoder/meteo"
 	"beastdecoder/vesselstate"
+	"context"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -14,6 +21,9 @@ import (
 //go:embed webview.gtpl
 var webviewTemplate string
 
+// requests in progress are given this long to complete on shutdown
+const shutdownTimeout = time.Second * 5
+
 func httpRenderWebview(w http.ResponseWriter, r *http.Request, vdb *vesselstate.Vessels) {
 	log := log.With().Str("component", "webview").Logger()
 
@@ -23,29 +33,160 @@ func httpRenderWebview(w http.ResponseWriter, r *http.Request, vdb *vesselstate.
 		log.Err(err).Str("func", "httpRenderWebview").Str("reqURI", r.RequestURI).Msg("could not render webviewTemplate")
 	}
 
-	vdb.RLock()
-	defer vdb.RUnlock()
+	filter, err := parseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// vessels' recent positions are drawn as trails
+	filter.History = true
+	vessels := vdb.Snapshot(filter)
+	data := struct {
+		Vessels []vesselstate.VesselState
+		Map     *trailMap
+	}{
+		Vessels: vessels,
+		Map:     newTrailMap(vessels),
+	}
 
-	err = t.Execute(w, vdb.Vessels)
+	err = t.Execute(w, data)
 	if err != nil {
 		fmt.Println(err)
 		log.Panic().AnErr("err", err).Str("func", "httpRenderWebview").Str("reqURI", r.RequestURI).Msg("could not execute webviewTemplate")
 	}
 }
 
-func Init(addr net.Addr, vdb *vesselstate.Vessels) {
+func parseFilter(r *http.Request) (filter vesselstate.Filter, err error) {
+	// selects vessels by query parameters, eg: ?icao=4840d6,40621d&bbox=51,3,53,5&max_age=30s
+	//  icao    = comma separated ICAO addresses (hex)
+	//  bbox    = min lat, min lon, max lat, max lon (degrees)
+	//  max_age = vessels heard from within this long
+
+	query := r.URL.Query()
+
+	if s := query.Get("icao"); s != "" {
+		for _, hex := range strings.Split(s, ",") {
+			icao, err := strconv.ParseInt(strings.TrimSpace(hex), 16, 32)
+			if err != nil {
+				return filter, fmt.Errorf("could not parse icao (icao: %s)", hex)
+			}
+			filter.ICAOs = append(filter.ICAOs, int(icao))
+		}
+	}
+
+	if s := query.Get("bbox"); s != "" {
+		parts := strings.Split(s, ",")
+		if len(parts) != 4 {
+			return filter, fmt.Errorf("bbox must be min lat, min lon, max lat, max lon (bbox: %s)", s)
+		}
+		var values [4]float64
+		for i, part := range parts {
+			values[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return filter, fmt.Errorf("could not parse bbox (bbox: %s)", s)
+			}
+		}
+		filter.Bounds = &vesselstate.Bounds{
+			MinLat: values[0],
+			MinLon: values[1],
+			MaxLat: values[2],
+			MaxLon: values[3],
+		}
+	}
+
+	if s := query.Get("max_age"); s != "" {
+		filter.MaxAge, err = time.ParseDuration(s)
+		if err != nil {
+			return filter, fmt.Errorf("could not parse max_age (max_age: %s)", s)
+		}
+	}
+
+	return filter, nil
+}
+
+func httpRenderMeteoJSON(w http.ResponseWriter, r *http.Request, grid *meteo.Grid) {
 	log := log.With().Str("component", "webview").Logger()
+	w.Header().Set("Content-Type", "application/json")
+	err := grid.WriteJSON(w, time.Now())
+	if err != nil {
+		log.Err(err).Str("func", "httpRenderMeteoJSON").Str("reqURI", r.RequestURI).Msg("could not write meteo grid")
+	}
+}
 
-	// stats http server routes
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+func httpRenderMeteoCSV(w http.ResponseWriter, r *http.Request, grid *meteo.Grid) {
+	log := log.With().Str("component", "webview").Logger()
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"meteo.csv\"")
+	err := grid.WriteCSV(w, time.Now())
+	if err != nil {
+		log.Err(err).Str("func", "httpRenderMeteoCSV").Str("reqURI", r.RequestURI).Msg("could not write meteo grid")
+	}
+}
+
+func httpRenderStatusJSON(w http.ResponseWriter, r *http.Request, status func() any) {
+	log := log.With().Str("component", "webview").Logger()
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(status())
+	if err != nil {
+		log.Err(err).Str("func", "httpRenderStatusJSON").Str("reqURI", r.RequestURI).Msg("could not write status")
+	}
+}
+
+func Serve(ctx context.Context, addr net.Addr, vdb *vesselstate.Vessels, grid *meteo.Grid, status func() any) error {
+	// serves the web interface until ctx is cancelled
+	// status returns the decoder's status (eg: of each input), served at /status.json
+
+	log := log.With().Str("component", "webview").Logger()
+
+	mux := http.NewServeMux()
+
+	// stats http server routes, vessels can be filtered by query parameters
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		httpRenderWebview(w, r, vdb)
 	})
 
-	// start stats http server
-	log.Info().Str("ip", "0.0.0.0").Int("port", 6969).Msg("starting webview listener")
-	err := http.ListenAndServe(addr.String(), nil)
-	if err != nil {
-		log.Panic().AnErr("err", err).Msg("stats server stopped")
+	// decoder status route
+	if status != nil {
+		mux.HandleFunc("/status.json", func(w http.ResponseWriter, r *http.Request) {
+			httpRenderStatusJSON(w, r, status)
+		})
+	}
+
+	// gridded wind/temperature routes
+	if grid != nil {
+		mux.HandleFunc("/meteo.json", func(w http.ResponseWriter, r *http.Request) {
+			httpRenderMeteoJSON(w, r, grid)
+		})
+		mux.HandleFunc("/meteo.csv", func(w http.ResponseWriter, r *http.Request) {
+			httpRenderMeteoCSV(w, r, grid)
+		})
 	}
 
+	server := &http.Server{
+		Addr:    addr.String(),
+		Handler: mux,
+	}
+
+	// stop accepting connections & wait for requests in progress when cancelled
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Err(err).Msg("could not shut down webview listener cleanly")
+		}
+	}()
+
+	// start stats http server
+	log.Info().Str("addr", addr.String()).Msg("starting webview listener")
+	err := server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-stopped
+	log.Info().Msg("webview listener stopped")
+	return nil
 }